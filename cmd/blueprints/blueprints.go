@@ -22,6 +22,13 @@ func NewBlueprintsCommand(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(NewGetCommand(ctx))
 	cmd.AddCommand(NewValidationCommand(ctx))
 	cmd.AddCommand(NewRenderCommand(ctx))
+	cmd.AddCommand(NewExampleValuesCommand(ctx))
+	cmd.AddCommand(NewCheckCommand(ctx))
+	cmd.AddCommand(NewDocsCommand(ctx))
+	cmd.AddCommand(NewSchemaCommand(ctx))
+	cmd.AddCommand(NewCompatCommand(ctx))
+	cmd.AddCommand(NewPackageCommand(ctx))
+	cmd.AddCommand(NewUnpackCommand(ctx))
 
 	return cmd
 }
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/policies"
+)
+
+const (
+	// TextOut prints the check results as human readable table.
+	TextOut = "text"
+)
+
+// CheckOptions describes the options for the check command.
+type CheckOptions struct {
+	RenderOptions
+
+	// PolicyDir is the directory containing the policy files.
+	PolicyDir string
+	// ReportFormat defines the format of the check report.
+	ReportFormat string
+
+	rules []policies.Rule
+}
+
+// NewCheckCommand creates a new command to check a rendered blueprint against policies.
+func NewCheckCommand(ctx context.Context) *cobra.Command {
+	opts := &CheckOptions{}
+	cmd := &cobra.Command{
+		Use:     "check",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli blueprints check BLUEPRINT_DIR -p POLICY_DIR -f values.yaml",
+		Short:   "checks the rendered deploy items of a blueprint against policies",
+		Long: `
+Renders the blueprint with the given values files like the render command and evaluates the rules 
+of all policy files in the policy directory against the rendered deploy items and their expanded manifests.
+
+The command exits with a non-zero exit code if a rule with severity error is violated.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(logger.Log, args, osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			report, err := opts.Check(ctx, logger.Log, osfs.New())
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			if err := opts.printReport(os.Stdout, report); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			if report.HasErrors() {
+				os.Exit(1)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *CheckOptions) AddFlags(fs *pflag.FlagSet) {
+	o.addInputFlags(fs)
+	fs.StringVarP(&o.PolicyDir, "policies", "p", "", "Path to the directory containing the policy files")
+	fs.StringVarP(&o.ReportFormat, "output", "o", TextOut, "The format of the check report. Can be text, json or yaml.")
+}
+
+func (o *CheckOptions) Complete(log logr.Logger, args []string, fs vfs.FileSystem) error {
+	if len(o.PolicyDir) == 0 {
+		return fmt.Errorf("a policy directory has to be specified")
+	}
+	if o.ReportFormat != TextOut && o.ReportFormat != JSONOut && o.ReportFormat != YAMLOut {
+		return fmt.Errorf("output format is expected to be text, json or yaml but got '%s'", o.ReportFormat)
+	}
+
+	absPolicyDir, err := filepath.Abs(o.PolicyDir)
+	if err != nil {
+		return fmt.Errorf("unable get absolute policy directory path for %s: %w", o.PolicyDir, err)
+	}
+	o.PolicyDir = absPolicyDir
+	o.rules, err = policies.LoadFromDir(fs, o.PolicyDir)
+	if err != nil {
+		return err
+	}
+
+	o.RenderOptions.OutputFormat = YAMLOut
+	o.RenderOptions.Expand = true
+	o.RenderOptions.silent = true
+	return o.RenderOptions.Complete(log, args, fs)
+}
+
+// Check renders the blueprint and evaluates the policies against the rendered deploy items and manifests.
+func (o *CheckOptions) Check(ctx context.Context, log logr.Logger, fs vfs.FileSystem) (*policies.Report, error) {
+	evaluator, err := policies.NewEvaluator(o.rules)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := o.Render(ctx, log, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := checkObjects(result)
+	if err != nil {
+		return nil, err
+	}
+	return evaluator.Evaluate(objects)
+}
+
+// checkObjects returns all deploy items of the render result followed by their manifests.
+func checkObjects(result *RenderResult) ([]policies.Object, error) {
+	objects := []policies.Object{}
+	for _, inst := range result.Installations {
+		for _, di := range inst.DeployItems {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(di)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert deploy item %s of installation %s: %w", di.Name, inst.Path, err)
+			}
+			diObj := &unstructured.Unstructured{Object: content}
+			// the kind of rendered deploy items is not always set
+			diObj.SetAPIVersion("landscaper.gardener.cloud/v1alpha1")
+			diObj.SetKind("DeployItem")
+
+			objects = append(objects, policies.Object{
+				Installation:     inst.Path,
+				DeployItem:       di.Name,
+				DeployItemType:   string(di.Spec.Type),
+				Object:           diObj,
+				DeployItemObject: diObj,
+			})
+			for _, obj := range inst.Manifests[di.Name] {
+				objects = append(objects, policies.Object{
+					Installation:     inst.Path,
+					DeployItem:       di.Name,
+					DeployItemType:   string(di.Spec.Type),
+					Object:           obj,
+					DeployItemObject: diObj,
+				})
+			}
+		}
+	}
+	return objects, nil
+}
+
+func (o *CheckOptions) printReport(w io.Writer, report *policies.Report) error {
+	switch o.ReportFormat {
+	case JSONOut:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case YAMLOut:
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	if len(report.Violations) == 0 {
+		_, err := fmt.Fprintf(w, "checked %d objects against %d rules: no violations found\n", report.Objects, report.Rules)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tRULE\tINSTALLATION\tDEPLOYITEM\tOBJECT\tMESSAGE")
+	for _, v := range report.Violations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Severity, v.Rule, v.Installation, v.DeployItem, v.Object, v.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nchecked %d objects against %d rules: %d violations found\n", report.Objects, report.Rules, len(report.Violations))
+	return err
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/cmd/blueprints"
+	"github.com/gardener/landscapercli/pkg/policies"
+)
+
+func TestCheckCommand(t *testing.T) {
+	a := assert.New(t)
+	fail := failFunc(a)
+
+	testdataFs, err := createTestdataFs("/")
+	fail(a.NoError(err))
+	checkOpts := &blueprints.CheckOptions{
+		RenderOptions: blueprints.RenderOptions{
+			ValueFiles: []string{"./testdata/01-render-expand/imports.yaml"},
+		},
+		PolicyDir:    "./testdata/01-render-expand/policies",
+		ReportFormat: blueprints.JSONOut,
+	}
+
+	fail(a.NoError(checkOpts.Complete(logr.Discard(), []string{"./testdata/01-render-expand/blueprint"}, testdataFs)))
+	report, err := checkOpts.Check(context.TODO(), logr.Discard(), testdataFs)
+	fail(a.NoError(err))
+
+	a.Equal(3, report.Rules)
+	a.Equal(3, report.Objects, "expect the deploy item and its two manifests")
+	fail(a.Len(report.Violations, 2))
+	a.True(report.HasErrors())
+
+	a.Equal("deployitem-types", report.Violations[0].Rule)
+	a.Equal(policies.SeverityError, report.Violations[0].Severity)
+	a.Equal("root", report.Violations[0].Installation)
+	a.Equal("config", report.Violations[0].DeployItem)
+	a.Equal("DeployItem", report.Violations[0].Object.Kind)
+
+	a.Equal(policies.Violation{
+		Rule:         "configmap-immutable",
+		Severity:     policies.SeverityWarning,
+		Installation: "root",
+		DeployItem:   "config",
+		Object: policies.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "my-namespace",
+			Name:       "config",
+		},
+		Message: "configmap config of deploy item config should be immutable",
+	}, report.Violations[1])
+}
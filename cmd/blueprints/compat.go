@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/gardener/component-cli/ociclient"
+	"github.com/gardener/component-cli/ociclient/cache"
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/cmd/constants"
+	"github.com/gardener/landscapercli/pkg/compat"
+	"github.com/gardener/landscapercli/pkg/logger"
+)
+
+const (
+	compatTableOutput = "table"
+	compatJSONOutput  = "json"
+)
+
+type compatOptions struct {
+	// oldRef is the blueprint directory or oci reference of the old blueprint.
+	oldRef string
+	// newRef is the blueprint directory or oci reference of the new blueprint.
+	newRef string
+	// outputFormat is the format of the report.
+	outputFormat string
+	// allowPlainHttp allows the fallback to http if the oci registry does not support https
+	allowPlainHttp bool
+
+	// cacheDir defines the oci cache directory
+	cacheDir string
+	// ociClient is created when the first blueprint is downloaded.
+	ociClient ociclient.Client
+}
+
+// NewCompatCommand creates a new command to check the compatibility of two blueprint versions.
+func NewCompatCommand(ctx context.Context) *cobra.Command {
+	opts := &compatOptions{}
+	cmd := &cobra.Command{
+		Use:  "compat OLD NEW",
+		Args: cobra.ExactArgs(2),
+		Example: `landscaper-cli blueprints compat my-registry/my-repository:v1.0.0 ./blueprint
+landscaper-cli blueprints compat ./old-blueprint ./new-blueprint -o json`,
+		Short: "reports the breaking changes between two versions of a blueprint",
+		Long: `
+Compares two versions of a blueprint and reports the changes that break existing installations of the old version:
+removed imports, newly required imports, narrowed import schemas, removed exports, changed (target) types
+and renamed or removed deploy items, which cause a redeployment.
+
+The blueprints can be given as local blueprint directories or as references to OCI artifacts.
+The command fails if breaking changes are found.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(args); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.run(ctx, logger.Log, osfs.New(), os.Stdout); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *compatOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.outputFormat, "output", "o", compatTableOutput, "The format of the report. Can be table or json.")
+	fs.BoolVar(&o.allowPlainHttp, "allow-plain-http", false, "allows the fallback to http if the oci registry does not support https")
+}
+
+func (o *compatOptions) Complete(args []string) error {
+	o.oldRef = args[0]
+	o.newRef = args[1]
+
+	if o.outputFormat != compatTableOutput && o.outputFormat != compatJSONOutput {
+		return fmt.Errorf("output format is expected to be %s or %s but got '%s'", compatTableOutput, compatJSONOutput, o.outputFormat)
+	}
+
+	landscaperCliHomeDir, err := constants.LandscaperCliHomeDir()
+	if err != nil {
+		return err
+	}
+	o.cacheDir = filepath.Join(landscaperCliHomeDir, "blueprints")
+	if err := os.MkdirAll(o.cacheDir, os.ModePerm); err != nil {
+		return fmt.Errorf("unable to create cache directory %s: %w", o.cacheDir, err)
+	}
+	return nil
+}
+
+func (o *compatOptions) run(ctx context.Context, log logr.Logger, fs vfs.FileSystem, w io.Writer) error {
+	oldBlueprint, err := o.loadBlueprint(ctx, log, fs, o.oldRef)
+	if err != nil {
+		return fmt.Errorf("unable to load old blueprint %s: %w", o.oldRef, err)
+	}
+	newBlueprint, err := o.loadBlueprint(ctx, log, fs, o.newRef)
+	if err != nil {
+		return fmt.Errorf("unable to load new blueprint %s: %w", o.newRef, err)
+	}
+
+	changes, err := compat.Compare(oldBlueprint, newBlueprint)
+	if err != nil {
+		return err
+	}
+	if err := printChanges(w, changes, o.outputFormat); err != nil {
+		return err
+	}
+
+	if breaking := compat.CountBreakingChanges(changes); breaking != 0 {
+		return fmt.Errorf("found %d breaking changes", breaking)
+	}
+	return nil
+}
+
+// loadBlueprint reads the blueprint from a local directory or downloads it if the reference is no directory.
+func (o *compatOptions) loadBlueprint(ctx context.Context, log logr.Logger, fs vfs.FileSystem, ref string) (*compat.Blueprint, error) {
+	if info, err := fs.Stat(ref); err == nil && info.IsDir() {
+		blueprintFs, err := projectionfs.New(fs, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to construct blueprint filesystem: %w", err)
+		}
+		return compat.ReadBlueprint(blueprintFs)
+	}
+
+	if o.ociClient == nil {
+		cache, err := cache.NewCache(log, cache.WithBasePath(o.cacheDir))
+		if err != nil {
+			return nil, err
+		}
+		o.ociClient, err = ociclient.NewClient(log, ociclient.WithCache(cache), ociclient.AllowPlainHttp(o.allowPlainHttp))
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, artifact, err := fetchBlueprintArtifact(ctx, o.ociClient, ref)
+	if err != nil {
+		return nil, err
+	}
+	memFS := memoryfs.New()
+	if err := artifact.Extract(ctx, memFS, "/"); err != nil {
+		return nil, fmt.Errorf("unable to extract blueprint: %w", err)
+	}
+	return compat.ReadBlueprint(memFS)
+}
+
+// printChanges prints the changes as table or json.
+func printChanges(w io.Writer, changes []compat.Change, format string) error {
+	if format == compatJSONOutput {
+		if changes == nil {
+			changes = []compat.Change{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal changes: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes found.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tKIND\tNAME\tCHANGE")
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.Severity, change.Kind, change.Name, change.Message)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/pkg/docs"
+	"github.com/gardener/landscapercli/pkg/logger"
+)
+
+// DocsOptions describes the options for the docs command.
+type DocsOptions struct {
+	RenderOptions
+
+	// Format is the format of the generated documentation.
+	Format string
+	// OutputPath is the file the documentation is written to.
+	OutputPath string
+	// Name is the name of the blueprint in the documentation.
+	Name string
+}
+
+// NewDocsCommand creates a new command to generate the documentation of a blueprint.
+func NewDocsCommand(ctx context.Context) *cobra.Command {
+	opts := &DocsOptions{}
+	cmd := &cobra.Command{
+		Use:  "docs",
+		Args: cobra.ExactArgs(1),
+		Example: `landscaper-cli blueprints docs BLUEPRINT_DIR -w README.md
+landscaper-cli blueprints docs BLUEPRINT_DIR -c component-descriptor.yaml -f values.yaml -o html -w index.html`,
+		Short: "generates the documentation of a blueprint",
+		Long: `
+Generates Markdown or HTML documentation of a blueprint that describes all imports and exports with their
+JSON schemas, target types, deploy executions with the deployer types they use, and subinstallations.
+
+References in the JSON schemas are resolved. References to the component descriptor are only resolved
+if a component descriptor is given.
+
+Without import values, the deployer types of the deploy executions are guessed from the "type" keys
+in the template text and marked as guessed. If import values are given, the blueprint is rendered like
+with the render command, the deployer types are taken from the rendered deploy items, and the rendered
+deploy items are documented as well.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(logger.Log, args, osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.Run(ctx, logger.Log, osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *DocsOptions) AddFlags(fs *pflag.FlagSet) {
+	o.addInputFlags(fs)
+	fs.StringVarP(&o.Format, "output", "o", docs.MarkdownFormat, "The format of the documentation. Can be markdown or html.")
+	fs.StringVarP(&o.OutputPath, "write", "w", "", "The file the documentation is written to. By default, it is printed to stdout.")
+	fs.StringVar(&o.Name, "name", "", "The name of the blueprint in the documentation. Defaults to the name of the blueprint directory.")
+}
+
+func (o *DocsOptions) Complete(log logr.Logger, args []string, fs vfs.FileSystem) error {
+	if o.Format != docs.MarkdownFormat && o.Format != docs.HTMLFormat {
+		return fmt.Errorf("output format is expected to be %s or %s but got '%s'", docs.MarkdownFormat, docs.HTMLFormat, o.Format)
+	}
+
+	o.RenderOptions.OutputFormat = YAMLOut
+	o.RenderOptions.silent = true
+	if err := o.RenderOptions.Complete(log, args, fs); err != nil {
+		return err
+	}
+	if len(o.Name) == 0 {
+		o.Name = filepath.Base(o.BlueprintPath)
+	}
+	return nil
+}
+
+// Generate generates the document of the blueprint.
+// The deploy items are only added if import values are given, otherwise the deployer types are guessed.
+func (o *DocsOptions) Generate(ctx context.Context, log logr.Logger, fs vfs.FileSystem) (*docs.Document, error) {
+	generator := &docs.Generator{
+		Fs:                  o.blueprintFs,
+		ComponentDescriptor: o.componentDescriptor,
+		ComponentResolver:   o.componentResolver,
+	}
+	doc, err := generator.Generate(o.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.ValueFiles) == 0 {
+		return doc, nil
+	}
+
+	result, err := o.Render(ctx, log, fs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the deploy items: %w", err)
+	}
+	deployItems := []docs.DeployItem{}
+	for _, inst := range result.Installations {
+		for _, di := range inst.DeployItems {
+			deployItems = append(deployItems, docs.DeployItem{
+				Installation: inst.Path,
+				Name:         di.Name,
+				Type:         string(di.Spec.Type),
+				Execution:    inst.DeployItemSources[di.Name],
+			})
+		}
+	}
+	doc.SetDeployItems(RootInstallationName, deployItems)
+	return doc, nil
+}
+
+// Run generates the documentation and prints it or writes it to the output file.
+func (o *DocsOptions) Run(ctx context.Context, log logr.Logger, fs vfs.FileSystem) error {
+	doc, err := o.Generate(ctx, log, fs)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf, o.Format); err != nil {
+		return err
+	}
+	if len(o.OutputPath) == 0 {
+		fmt.Print(buf.String())
+		return nil
+	}
+	if err := vfs.WriteFile(fs, o.OutputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("unable to write documentation to %s: %w", o.OutputPath, err)
+	}
+	fmt.Printf("Documentation written to %s\n", o.OutputPath)
+	return nil
+}
@@ -7,17 +7,21 @@ package blueprints
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 
 	lsv1alpha1 "github.com/gardener/landscaper/apis/core"
-	"github.com/gardener/landscaper/pkg/utils/tar"
 
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/osfs"
 	"github.com/mandelsoft/vfs/pkg/vfs"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -25,11 +29,15 @@ import (
 
 	"github.com/gardener/component-cli/ociclient"
 	"github.com/gardener/component-cli/ociclient/cache"
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/codec"
 
 	lsinstall "github.com/gardener/landscaper/apis/core/v1alpha1"
 	"github.com/gardener/landscaper/pkg/api"
 
+	"github.com/gardener/landscapercli/pkg/artifacts"
 	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
 
 	"github.com/gardener/landscapercli/cmd/constants"
 )
@@ -39,6 +47,19 @@ type showOptions struct {
 	ref string
 	// allowPlainHttp allows the fallback to http if the oci registry does not support https
 	allowPlainHttp bool
+	// outputPath is the directory all files of the blueprint are written to.
+	outputPath string
+	// showManifest prints the oci manifest of the blueprint artifact.
+	showManifest bool
+	// componentDescriptorPath is the path to the optional component descriptor the blueprint is verified against.
+	componentDescriptorPath string
+	// blueprintResourceName is the name of the blueprint resource in the component descriptor.
+	blueprintResourceName string
+	// verification configures the signature verification of the component descriptor.
+	verification artifacts.VerificationOptions
+
+	// componentDescriptor is the component descriptor the blueprint is verified against.
+	componentDescriptor *cdv2.ComponentDescriptor
 
 	// cacheDir defines the oci cache directory
 	cacheDir string
@@ -48,19 +69,26 @@ type showOptions struct {
 func NewGetCommand(ctx context.Context) *cobra.Command {
 	opts := &showOptions{}
 	cmd := &cobra.Command{
-		Use:     "get OCI_ARTIFACT_REF",
-		Args:    cobra.MinimumNArgs(1),
-		Example: "landscaper-cli blueprints get my-registry/my-repository:v1.0.0",
-		Short:   "command to download a blueprint from an oci registry",
+		Use:  "get OCI_ARTIFACT_REF",
+		Args: cobra.MinimumNArgs(1),
+		Example: `landscaper-cli blueprints get my-registry/my-repository:v1.0.0
+landscaper-cli blueprints get my-registry/my-repository:v1.0.0 -o path/to/blueprint/directory --show-manifest`,
+		Short: "command to download a blueprint from an oci registry",
 		Long: "The get command downloads a Blueprint from an OCI registry. The reference to the OCI artifact " +
-			"consists of the base URL of the OCI registry, the repository (namespace), and the tag.",
+			"consists of the base URL of the OCI registry, the repository (namespace), and the tag. " +
+			"By default, the blueprint definition is printed. With --output, all files of the blueprint are written " +
+			"to the given directory, which must not exist or must be empty. " +
+			"The digests of the downloaded blobs are verified against the manifest. " +
+			"If a component descriptor is given, the blueprint is additionally verified against the digest of its resource " +
+			"in the component descriptor, and with --public-key also the signature of the component descriptor is verified.",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := opts.Complete(args); err != nil {
+			fs := osfs.New()
+			if err := opts.Complete(args, fs); err != nil {
 				fmt.Println(err.Error())
 				os.Exit(1)
 			}
 
-			if err := opts.run(ctx, logger.Log); err != nil {
+			if err := opts.run(ctx, logger.Log, fs); err != nil {
 				fmt.Println(err.Error())
 				os.Exit(1)
 			}
@@ -72,7 +100,13 @@ func NewGetCommand(ctx context.Context) *cobra.Command {
 	return cmd
 }
 
-func (o *showOptions) run(ctx context.Context, log logr.Logger) error {
+func (o *showOptions) run(ctx context.Context, log logr.Logger, fs vfs.FileSystem) error {
+	if len(o.outputPath) != 0 {
+		if entries, err := vfs.ReadDir(fs, o.outputPath); err == nil && len(entries) != 0 {
+			return fmt.Errorf("the output directory %s is not empty", o.outputPath)
+		}
+	}
+
 	cache, err := cache.NewCache(log, cache.WithBasePath(o.cacheDir))
 	if err != nil {
 		return err
@@ -83,18 +117,46 @@ func (o *showOptions) run(ctx context.Context, log logr.Logger) error {
 		return err
 	}
 
-	manifest, err := ociClient.GetManifest(ctx, o.ref)
+	manifestData, artifact, err := fetchBlueprintArtifact(ctx, ociClient, o.ref)
 	if err != nil {
 		return err
 	}
+	// blueprints pushed by other tools are accepted unless the manifest is shown or the blueprint is verified
+	strict := o.showManifest || o.componentDescriptor != nil
+	if strict {
+		if err := artifact.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.componentDescriptor != nil {
+		if err := o.verify(manifestData, artifact); err != nil {
+			return err
+		}
+	}
+	extract := artifact.Extract
+	if strict {
+		extract = artifact.ExtractStrict
+	}
 
-	var data bytes.Buffer
-	if err := ociClient.Fetch(ctx, o.ref, manifest.Layers[0], &data); err != nil {
-		return err
+	if o.showManifest {
+		if err := printManifest(os.Stdout, o.ref, artifact); err != nil {
+			return err
+		}
+	}
+
+	if len(o.outputPath) != 0 {
+		if err := fs.MkdirAll(o.outputPath, os.ModePerm); err != nil {
+			return fmt.Errorf("unable to create output directory %s: %w", o.outputPath, err)
+		}
+		if err := extract(ctx, fs, o.outputPath); err != nil {
+			return fmt.Errorf("unable to extract blueprint: %w", err)
+		}
+		fmt.Printf("Blueprint %s written to %s\n", o.ref, o.outputPath)
+		return nil
 	}
 
 	memFS := memoryfs.New()
-	if err := tar.ExtractTarGzip(ctx, &data, memFS, tar.ToPath("/")); err != nil {
+	if err := extract(ctx, memFS, "/"); err != nil {
 		return err
 	}
 
@@ -117,9 +179,98 @@ func (o *showOptions) run(ctx context.Context, log logr.Logger) error {
 	return nil
 }
 
-func (o *showOptions) Complete(args []string) error {
+// fetchBlueprintArtifact downloads the blueprint artifact and verifies the digests of the manifest and its blobs.
+func fetchBlueprintArtifact(ctx context.Context, ociClient ociclient.Client, ref string) ([]byte, *artifacts.BlueprintArtifact, error) {
+	desc, manifestData, err := ociClient.GetRawManifest(ctx, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := artifacts.VerifyBlob(desc, manifestData); err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	artifact, err := artifacts.NewBlueprintArtifact(manifestData, func(desc ocispecv1.Descriptor) ([]byte, error) {
+		var data bytes.Buffer
+		if err := ociClient.Fetch(ctx, ref, desc, &data); err != nil {
+			return nil, err
+		}
+		return data.Bytes(), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifestData, artifact, nil
+}
+
+// verify verifies the blueprint against the digest of its resource in the component descriptor and,
+// if configured, the signature of the component descriptor.
+func (o *showOptions) verify(manifestData []byte, artifact *artifacts.BlueprintArtifact) error {
+	if o.verification.VerifySignatureEnabled() {
+		if err := o.verification.VerifySignature(o.componentDescriptor); err != nil {
+			return err
+		}
+	}
+	res, err := util.GetBlueprintResource(o.componentDescriptor, o.blueprintResourceName)
+	if err != nil {
+		return err
+	}
+	if err := artifacts.VerifyResourceDigest(*res, manifestData, artifact.Layer); err != nil {
+		return fmt.Errorf("unable to verify blueprint: %w", err)
+	}
+	return nil
+}
+
+// printManifest prints the digest, media types and sizes of the artifact, followed by its manifest.
+func printManifest(w io.Writer, ref string, artifact *artifacts.BlueprintArtifact) error {
+	manifestDesc := artifact.ManifestDescriptor()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Reference:\t%s\n", ref)
+	fmt.Fprintf(tw, "Digest:\t%s\n", manifestDesc.Digest)
+	fmt.Fprintf(tw, "Media Type:\t%s\n", manifestDesc.MediaType)
+	fmt.Fprintf(tw, "Size:\t%d\n", manifestDesc.Size)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BLOB\tMEDIA TYPE\tDIGEST\tSIZE")
+	fmt.Fprintf(tw, "config\t%s\t%s\t%d\n", artifact.Manifest.Config.MediaType, artifact.Manifest.Config.Digest, artifact.Manifest.Config.Size)
+	for i, layer := range artifact.Manifest.Layers {
+		fmt.Fprintf(tw, "layer %d\t%s\t%s\t%d\n", i, layer.MediaType, layer.Digest, layer.Size)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var manifest bytes.Buffer
+	if err := json.Indent(&manifest, artifact.ManifestData, "", "  "); err != nil {
+		return fmt.Errorf("unable to format manifest: %w", err)
+	}
+	fmt.Fprintf(w, "\nManifest:\n%s\n\n", manifest.String())
+	return nil
+}
+
+func (o *showOptions) Complete(args []string, fs vfs.FileSystem) error {
 	o.ref = args[0]
 
+	if err := o.verification.Validate(); err != nil {
+		return err
+	}
+	if len(o.componentDescriptorPath) != 0 {
+		data, err := vfs.ReadFile(fs, o.componentDescriptorPath)
+		if err != nil {
+			return fmt.Errorf("unable to read component descriptor from %s: %w", o.componentDescriptorPath, err)
+		}
+		cd := &cdv2.ComponentDescriptor{}
+		if err := codec.Decode(data, cd); err != nil {
+			return fmt.Errorf("unable to decode component descriptor: %w", err)
+		}
+		o.componentDescriptor = cd
+	} else if o.verification.VerifySignatureEnabled() {
+		return errors.New("a component descriptor (option -c) must be defined to verify its signature")
+	}
+
 	landscaperCliHomeDir, err := constants.LandscaperCliHomeDir()
 	if err != nil {
 		return err
@@ -137,4 +288,9 @@ func (o *showOptions) Complete(args []string) error {
 
 func (o *showOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.allowPlainHttp, "allow-plain-http", false, "allows the fallback to http if the oci registry does not support https")
+	fs.StringVarP(&o.outputPath, "output", "o", "", "directory all files of the blueprint are written to instead of printing the blueprint definition")
+	fs.BoolVar(&o.showManifest, "show-manifest", false, "print the oci manifest with the digest, media types and sizes of the blueprint artifact")
+	fs.StringVarP(&o.componentDescriptorPath, "component-descriptor", "c", "", "path to the local component descriptor whose resource digest the blueprint is verified against")
+	fs.StringVar(&o.blueprintResourceName, "blueprint-resource-name", "", "name of the blueprint resource in the component descriptor (optional if only one blueprint resource is specified in the component descriptor)")
+	o.verification.AddFlags(fs)
 }
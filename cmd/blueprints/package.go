@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/pkg/artifacts"
+	"github.com/gardener/landscapercli/pkg/validation"
+)
+
+type packageOptions struct {
+	// blueprintPath is the path to the directory containing the definition.
+	blueprintPath string
+	// outputPath is the path of the archive that is written.
+	outputPath string
+	// strict prevents the packaging on all validation errors instead of only on errors of the landscaper api validation.
+	strict bool
+}
+
+// NewPackageCommand creates a new blueprint command to package blueprints into a local archive.
+func NewPackageCommand(_ context.Context) *cobra.Command {
+	opts := &packageOptions{}
+	cmd := &cobra.Command{
+		Use:     "package BLUEPRINT_DIR",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli blueprints package path/to/blueprint/directory -o blueprint.tar.gz",
+		Short:   "packages a local blueprint into an archive",
+		Long: "The package command builds the OCI artifact of a Blueprint in a local directory, like it is written by the push command " +
+			"with --oci-layout or --ctf, and writes it as gzipped tar of an OCI image layout. " +
+			"The archive is reproducible: the files of the blueprint are sorted and their modification times and owners are normalized, " +
+			"so the same blueprint always results in the same digests. " +
+			"The config is the same as the one pushed into an OCI registry, but the layer and therefore the manifest digest differ, " +
+			"because the push into a registry keeps the modification times of the files. " +
+			"Like with the push command, the blueprint is validated before it is packaged. Only errors of the landscaper " +
+			"api validation prevent the packaging, all other findings are reported as warnings unless --strict is set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(args); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			artifact, err := opts.run(osfs.New())
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			fmt.Printf("Blueprint packaged to %s\n", opts.outputPath)
+			fmt.Printf("Manifest digest: %s\n", artifact.ManifestDescriptor().Digest)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *packageOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.outputPath, "output", "o", "blueprint.tar.gz", "Path of the archive that is written")
+	fs.BoolVar(&o.strict, "strict", false, "prevent the packaging on all validation errors. By default, only errors of the landscaper api validation prevent the packaging and all other findings are reported as warnings")
+}
+
+func (o *packageOptions) Complete(args []string) error {
+	o.blueprintPath = args[0]
+
+	findings, err := validateBlueprintForUpload(osfs.New(), o.blueprintPath, o.strict)
+	if err != nil {
+		return err
+	}
+	if findings.HasErrors() {
+		return &validation.Error{Findings: findings}
+	}
+	return validation.NewReport(o.blueprintPath, findings).Write(os.Stdout, validation.TextOutput)
+}
+
+func (o *packageOptions) run(fs vfs.FileSystem) (*artifacts.BlueprintArtifact, error) {
+	artifact, err := artifacts.BuildBlueprint(fs, o.blueprintPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(o.outputPath), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create directory for %s: %w", o.outputPath, err)
+	}
+	file, err := fs.Create(o.outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create archive %s: %w", o.outputPath, err)
+	}
+	err = artifact.WriteArchive(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// do not leave a truncated archive behind
+		_ = fs.Remove(o.outputPath)
+		return nil, fmt.Errorf("unable to write archive %s: %w", o.outputPath, err)
+	}
+	return artifact, nil
+}
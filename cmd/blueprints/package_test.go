@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/gardener/component-cli/ociclient/cache"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/utils/tar"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/artifacts"
+)
+
+func TestPackageComparedToRegistryPush(t *testing.T) {
+	ctx := context.Background()
+	definition, err := ioutil.ReadFile("./testdata/00-render/blueprint/blueprint.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	fs := memoryfs.New()
+	assert.NoError(t, fs.MkdirAll("/blueprint", 0755))
+	assert.NoError(t, vfs.WriteFile(fs, filepath.Join("/blueprint", lsv1alpha1.BlueprintFileName), definition, 0644))
+
+	opts := &packageOptions{blueprintPath: "/blueprint", outputPath: "/out/blueprint.tar.gz"}
+	packaged, err := opts.run(fs)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	blobs := cache.NewInMemoryCache()
+	pushed, err := buildRegistryBlueprint(blobs, fs, "/blueprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	pushedManifest, err := json.Marshal(pushed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, packaged.Manifest.Config, pushed.Config, "expect the same config")
+	assert.Equal(t, packaged.Manifest.Layers[0].MediaType, pushed.Layers[0].MediaType)
+	assert.NotEqual(t, packaged.Manifest.Layers[0].Digest, pushed.Layers[0].Digest,
+		"expect a different layer, since the registry layer keeps the modification times")
+	assert.NotEqual(t, packaged.ManifestDescriptor().Digest, digest.FromBytes(pushedManifest))
+
+	// both layers contain the same files
+	layer, err := blobs.Get(pushed.Layers[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer layer.Close()
+	pushedFs := memoryfs.New()
+	assert.NoError(t, tar.ExtractTarGzip(ctx, layer, pushedFs, tar.ToPath("/")))
+	packagedFs := memoryfs.New()
+	assert.NoError(t, packaged.ExtractStrict(ctx, packagedFs, "/"))
+	assert.Equal(t, readTestFile(t, pushedFs, lsv1alpha1.BlueprintFileName), readTestFile(t, packagedFs, lsv1alpha1.BlueprintFileName))
+
+	// the archive contains the packaged artifact
+	file, err := fs.Open("/out/blueprint.tar.gz")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer file.Close()
+	archived, err := artifacts.ReadArchive(ctx, file)
+	if assert.NoError(t, err) {
+		assert.Equal(t, packaged.ManifestDescriptor().Digest, archived.ManifestDescriptor().Digest)
+	}
+}
+
+func readTestFile(t *testing.T, fs vfs.FileSystem, path string) string {
+	data, err := vfs.ReadFile(fs, path)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestValidateBlueprintForUpload(t *testing.T) {
+	fs := memoryfs.New()
+	assert.NoError(t, fs.MkdirAll("/blueprint", 0755))
+	assert.NoError(t, vfs.WriteFile(fs, filepath.Join("/blueprint", lsv1alpha1.BlueprintFileName), []byte(`apiVersion: landscaper.gardener.cloud/v1alpha1
+kind: Blueprint
+deployExecutions:
+- name: default
+  type: GoTemplate
+  file: /missing.yaml
+`), 0644))
+
+	findings, err := validateBlueprintForUpload(fs, "/blueprint", false)
+	if assert.NoError(t, err) {
+		assert.NotEmpty(t, findings)
+		assert.False(t, findings.HasErrors(), "expect the findings to be reported as warnings")
+	}
+
+	findings, err = validateBlueprintForUpload(fs, "/blueprint", true)
+	if assert.NoError(t, err) {
+		assert.True(t, findings.HasErrors())
+	}
+}
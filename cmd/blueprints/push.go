@@ -8,7 +8,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -16,17 +15,16 @@ import (
 	"github.com/gardener/component-cli/ociclient/cache"
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
 
-	"github.com/gardener/landscaper/apis/core"
-	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
-	"github.com/gardener/landscaper/apis/core/validation"
-	"github.com/gardener/landscaper/pkg/api"
 	"github.com/gardener/landscaper/pkg/landscaper/blueprints/bputils"
 
+	"github.com/gardener/landscapercli/pkg/artifacts"
 	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/validation"
 
 	"github.com/gardener/landscapercli/cmd/constants"
 )
@@ -41,31 +39,65 @@ type pushOptions struct {
 	blueprintPath string
 	// cacheDir defines the oci cache directory
 	cacheDir string
+	// output is the format of the validation findings.
+	output string
+	// ociLayoutPath is the path to a local oci image layout directory the blueprint is written to instead of a registry.
+	ociLayoutPath string
+	// ctfPath is the path to a ctf archive the blueprint is written to instead of a registry.
+	ctfPath string
+	// resourceName is the name of the blueprint resource in the component of the ctf archive.
+	resourceName string
+	// strict prevents the upload on all validation errors instead of only on errors of the landscaper api validation.
+	strict bool
+
+	// findings are the findings of the validation of the blueprint.
+	findings validation.Findings
 }
 
 // NewPushCommand creates a new blueprint command to push blueprints
 func NewPushCommand(ctx context.Context) *cobra.Command {
 	opts := &pushOptions{}
 	cmd := &cobra.Command{
-		Use:     "push OCI_ARTIFACT_REF BLUEPRINT_DIR",
-		Args:    cobra.ExactArgs(2),
-		Example: "landscaper-cli blueprints push my-registry/my-repository:v1.0.0 path/to/blueprint/directory",
-		Short:   "command to upload a blueprint into an oci registry",
+		Use:  "push OCI_ARTIFACT_REF BLUEPRINT_DIR",
+		Args: cobra.ExactArgs(2),
+		Example: `landscaper-cli blueprints push my-registry/my-repository:v1.0.0 path/to/blueprint/directory
+landscaper-cli blueprints push --oci-layout ./layout my-registry/my-repository:v1.0.0 path/to/blueprint/directory
+landscaper-cli blueprints push --ctf ./transport.tar github.com/acme/my-component:v1.0.0 path/to/blueprint/directory`,
+		Short: "command to upload a blueprint into an oci registry",
 		Long: "The push command uploads a Blueprint from a local directory into an OCI registry. " +
 			"The blueprint directory must contain a file with name blueprint.yaml. The reference to the OCI artifact " +
-			"consists of the base URL of the OCI registry, the repository (namespace), and the tag.",
+			"consists of the base URL of the OCI registry, the repository (namespace), and the tag. " +
+			"Before the upload, the blueprint is validated like with the validate command. Only errors of the landscaper " +
+			"api validation prevent the upload, all other findings are reported as warnings unless --strict is set.\n\n" +
+			"With --oci-layout, the blueprint is written into a local OCI image layout directory instead, " +
+			"and the reference is stored as ref name annotation of the manifest. " +
+			"With --ctf, the blueprint is added as local blob resource to a component archive in a component-cli CTF archive " +
+			"and the reference must be the component name and version, e.g. github.com/acme/my-component:v1.0.0.",
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := opts.Complete(args); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, opts.blueprintPath, err, validation.RuleInvalidArgument)
+				os.Exit(1)
+			}
+
+			out, err := validation.WriteReport(os.Stdout, os.Stderr, opts.output, validation.NewReport(opts.blueprintPath, opts.findings))
+			if err != nil {
+				fmt.Fprintln(out, err.Error())
 				os.Exit(1)
 			}
 
 			if err := opts.run(ctx, logger.Log); err != nil {
-				fmt.Println(err.Error())
+				fmt.Fprintln(out, err.Error())
 				os.Exit(1)
 			}
 
-			fmt.Printf("Blueprint uploaded %s\n", opts.ref)
+			switch {
+			case len(opts.ociLayoutPath) != 0:
+				fmt.Fprintf(out, "Blueprint %s written to oci image layout %s\n", opts.ref, opts.ociLayoutPath)
+			case len(opts.ctfPath) != 0:
+				fmt.Fprintf(out, "Blueprint added as resource %s of component %s to ctf archive %s\n", opts.resourceName, opts.ref, opts.ctfPath)
+			default:
+				fmt.Fprintf(out, "Blueprint uploaded %s\n", opts.ref)
+			}
 		},
 	}
 
@@ -75,12 +107,27 @@ func NewPushCommand(ctx context.Context) *cobra.Command {
 }
 
 func (o *pushOptions) run(ctx context.Context, log logr.Logger) error {
-	cache, err := cache.NewCache(log, cache.WithBasePath(o.cacheDir))
-	if err != nil {
-		return err
+	fs := osfs.New()
+	if len(o.ociLayoutPath) != 0 || len(o.ctfPath) != 0 {
+		artifact, err := artifacts.BuildBlueprint(fs, o.blueprintPath)
+		if err != nil {
+			return err
+		}
+		if len(o.ociLayoutPath) != 0 {
+			return artifact.AddToLayout(fs, o.ociLayoutPath, o.ref)
+		}
+		name, version, err := artifacts.ParseComponentReference(o.ref)
+		if err != nil {
+			return err
+		}
+		return artifact.AddToCTF(fs, o.ctfPath, artifacts.CTFResource{
+			ComponentName:    name,
+			ComponentVersion: version,
+			ResourceName:     o.resourceName,
+		})
 	}
 
-	defManifest, err := bputils.BuildNewBlueprint(cache, osfs.New(), o.blueprintPath)
+	cache, err := cache.NewCache(log, cache.WithBasePath(o.cacheDir))
 	if err != nil {
 		return err
 	}
@@ -92,9 +139,21 @@ func (o *pushOptions) run(ctx context.Context, log logr.Logger) error {
 		return err
 	}
 
+	defManifest, err := buildRegistryBlueprint(cache, fs, o.blueprintPath)
+	if err != nil {
+		return err
+	}
+
 	return ociClient.PushManifest(ctx, o.ref, defManifest)
 }
 
+// buildRegistryBlueprint builds the manifest of the blueprint that is pushed into an oci registry and adds its blobs to the cache.
+// The layer is built like by landscaper, so the digests of already pushed blueprints do not change. In contrast to
+// artifacts.BuildBlueprint, the layer contains the modification times of the files and is not reproducible.
+func buildRegistryBlueprint(cache cache.Cache, fs vfs.FileSystem, path string) (*ocispecv1.Manifest, error) {
+	return bputils.BuildNewBlueprint(cache, fs, path)
+}
+
 func (o *pushOptions) Complete(args []string) error {
 	o.ref = args[0]
 	o.blueprintPath = args[1]
@@ -114,19 +173,32 @@ func (o *pushOptions) Complete(args []string) error {
 	return o.Validate()
 }
 
-// Validate validates push options
+// Validate validates push options and the blueprint.
+// The blueprint is validated like by the validate command. Only findings of the landscaper api validation prevent the
+// upload, all other findings are reported as warnings unless strict is set.
 func (o *pushOptions) Validate() error {
-	data, err := ioutil.ReadFile(filepath.Join(o.blueprintPath, lsv1alpha1.BlueprintFileName))
-	if err != nil {
+	if err := validation.ValidateOutputFormat(o.output); err != nil {
 		return err
 	}
-	blueprint := &core.Blueprint{}
-	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
-		return err
+	if len(o.ociLayoutPath) != 0 && len(o.ctfPath) != 0 {
+		return errors.New("only one of --oci-layout and --ctf can be specified")
+	}
+	if len(o.ctfPath) != 0 {
+		if _, _, err := artifacts.ParseComponentReference(o.ref); err != nil {
+			return err
+		}
+		if len(o.resourceName) == 0 {
+			return errors.New("a resource name must be defined")
+		}
 	}
 
-	if errList := validation.ValidateBlueprint(blueprint); len(errList) != 0 {
-		return errList.ToAggregate()
+	var err error
+	o.findings, err = validateBlueprintForUpload(osfs.New(), o.blueprintPath, o.strict)
+	if err != nil {
+		return err
+	}
+	if o.findings.HasErrors() {
+		return &validation.Error{Findings: o.findings}
 	}
 
 	return nil
@@ -134,4 +206,9 @@ func (o *pushOptions) Validate() error {
 
 func (o *pushOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.allowPlainHttp, "allow-plain-http", false, "allows the fallback to http if the oci registry does not support https")
+	fs.StringVarP(&o.output, "output", "o", validation.TextOutput, validation.OutputFlagUsage)
+	fs.StringVar(&o.ociLayoutPath, "oci-layout", "", "path to a local oci image layout directory the blueprint is written to instead of the oci registry")
+	fs.StringVar(&o.ctfPath, "ctf", "", "path to a component-cli ctf archive the blueprint is added to instead of the oci registry")
+	fs.StringVar(&o.resourceName, "resource-name", "blueprint", "name of the blueprint resource in the component of the ctf archive")
+	fs.BoolVar(&o.strict, "strict", false, "prevent the upload on all validation errors. By default, only errors of the landscaper api validation prevent the upload and all other findings are reported as warnings")
 }
@@ -8,6 +8,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,16 +17,23 @@ import (
 
 	"github.com/gardener/landscaper/pkg/landscaper/blueprints"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/gardener/component-cli/pkg/commands/componentarchive/resources"
 
 	"github.com/gardener/landscapercli/pkg/components"
+	"github.com/gardener/landscapercli/pkg/deployitems"
+	"github.com/gardener/landscapercli/pkg/executions"
 	"github.com/gardener/landscapercli/pkg/resolver"
 
+	"github.com/gardener/component-cli/ociclient"
 	ociclientopts "github.com/gardener/component-cli/ociclient/options"
 	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
 	"github.com/gardener/component-spec/bindings-go/codec"
 	"github.com/gardener/component-spec/bindings-go/ctf"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/spiff"
 	componentsregistry "github.com/gardener/landscaper/pkg/landscaper/registry/components"
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/vfs/pkg/layerfs"
@@ -65,9 +74,6 @@ const (
 	YAMLOut = "yaml"
 )
 
-const DeployItemOutputDir = "deployitems"
-const SubinstallationOutputDir = "subinstallations"
-
 // RenderOptions describes the options for the render command.
 type RenderOptions struct {
 	// BlueprintPath is the path to the directory containing the definition.
@@ -86,6 +92,10 @@ type RenderOptions struct {
 	OutputFormat string
 	// OutDir is the directory where the rendered should be written to.
 	OutDir string
+	// Expand defines whether manifest and helm deploy items should be expanded into plain kubernetes objects.
+	Expand bool
+	// Watch defines whether the blueprint should be rendered again whenever one of its inputs changes.
+	Watch bool
 
 	OCIOptions ociclientopts.Options
 
@@ -95,8 +105,12 @@ type RenderOptions struct {
 	componentDescriptor     *cdv2.ComponentDescriptor
 	componentDescriptorList *cdv2.ComponentDescriptorList
 	componentResolver       ctf.ComponentResolver
+	ociClient               ociclient.Client
 	resources               []resources.ResourceOptions
 	exportTemplates         lsutils.ExportTemplates
+
+	// silent suppresses the progress output on stdout, e.g. if the render result is not printed but processed further.
+	silent bool
 }
 
 // NewRenderCommand creates a new local command to render a blueprint instance locally
@@ -128,6 +142,14 @@ Available resources are
 				os.Exit(1)
 			}
 
+			if opts.Watch {
+				if err := opts.RunWatch(ctx, logger.Log, osfs.New()); err != nil {
+					fmt.Println(err.Error())
+					os.Exit(1)
+				}
+				return
+			}
+
 			if err := opts.Run(ctx, logger.Log, osfs.New()); err != nil {
 				fmt.Println(err.Error())
 				os.Exit(1)
@@ -140,13 +162,20 @@ Available resources are
 }
 
 func (o *RenderOptions) AddFlags(fs *pflag.FlagSet) {
+	o.addInputFlags(fs)
+	fs.StringVarP(&o.OutputFormat, "output", "o", YAMLOut, "The format of the output. Can be json or yaml.")
+	fs.StringVarP(&o.OutDir, "write", "w", "", "The output directory where the rendered files should be written to")
+	fs.BoolVar(&o.Expand, "expand", false, "Expand manifest and helm deploy items into the kubernetes objects that would be deployed")
+	fs.BoolVar(&o.Watch, "watch", false, "Watch the blueprint and all input files and render again on changes")
+}
+
+// addInputFlags adds the flags that define the inputs of the rendering.
+func (o *RenderOptions) addInputFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&o.ComponentDescriptorPath, "component-descriptor", "c", "", "Path to the local component descriptor")
 	fs.StringArrayVarP(&o.AdditionalComponentDescriptorPath, "additional-component-descriptor", "a", []string{}, "Path to additional local component descriptors")
 	fs.StringVarP(&o.ResourcesPath, "resources", "r", "", "Path to the resources yaml file")
 	fs.StringVarP(&o.ExportTemplatesPath, "export-templates", "e", "", "Path to the yaml file, defining the export templates")
 	fs.StringArrayVarP(&o.ValueFiles, "file", "f", []string{}, "List of filepaths to value yaml files that define the imports")
-	fs.StringVarP(&o.OutputFormat, "output", "o", YAMLOut, "The format of the output. Can be json or yaml.")
-	fs.StringVarP(&o.OutDir, "write", "w", "", "The output directory where the rendered files should be written to")
 	o.OCIOptions.AddFlags(fs)
 }
 
@@ -160,67 +189,124 @@ func formatState(state map[string][]byte) map[string]map[string]json.RawMessage
 	return stateOut
 }
 
-type SimulatorCallbacks struct {
-	options *RenderOptions
-	fs      vfs.FileSystem
+// RenderResult contains all objects that have been rendered for a blueprint.
+type RenderResult struct {
+	// Installations contains the rendered installations in the order they have been executed.
+	// The first installation is always the root installation.
+	Installations []*RenderedInstallation
 }
 
-func (c SimulatorCallbacks) OnInstallation(installationPath string, installation *lsv1alpha1.Installation) {
-	fmt.Printf("executing installation %s\n", installationPath)
+// RenderedInstallation contains the objects that have been rendered for one installation.
+type RenderedInstallation struct {
+	// Path is the installation path, e.g. "root/subinst-a".
+	Path string
+	// Installation is the rendered installation.
+	Installation *lsv1alpha1.Installation
+	// Imports contains the imports of the installation.
+	Imports map[string]interface{}
+	// Exports contains the exports of the installation.
+	// Exports are only calculated if export templates are given.
+	Exports map[string]interface{}
+	// DeployItems contains the rendered deploy items of the installation.
+	DeployItems []*lsv1alpha1.DeployItem
+	// DeployItemSources maps the name of a deploy item to the name of the deploy execution that rendered it.
+	DeployItemSources map[string]string
+	// Manifests maps the name of a deploy item to the kubernetes objects it deploys.
+	// Manifests are only calculated if the expand option is set.
+	Manifests map[string][]*unstructured.Unstructured
+	// DeployItemTemplateState contains the state of the deploy item templates.
+	DeployItemTemplateState map[string][]byte
+	// InstallationTemplateState contains the state of the subinstallation templates.
+	InstallationTemplateState map[string][]byte
+	// SubInstallations contains the paths of the subinstallations.
+	SubInstallations []string
+}
 
-	if c.options.outputResources.Has(OutputResourceSubinstallations) {
-		_ = c.options.out(c.fs, installation, installationPath, "installation")
+// Get returns the rendered installation with the given path or nil if no such installation exists.
+func (r *RenderResult) Get(installationPath string) *RenderedInstallation {
+	for _, inst := range r.Installations {
+		if inst.Path == installationPath {
+			return inst
+		}
 	}
+	return nil
 }
 
-func (c SimulatorCallbacks) OnInstallationTemplateState(installationPath string, state map[string][]byte) {
-	if c.options.outputResources.Has(OutputResourceDeployItems) {
-		_ = c.options.out(c.fs, formatState(state), installationPath, "installation", "state")
+// getOrCreate returns the rendered installation with the given path and adds a new one if it does not exist yet.
+func (r *RenderResult) getOrCreate(installationPath string) *RenderedInstallation {
+	if inst := r.Get(installationPath); inst != nil {
+		return inst
+	}
+	inst := &RenderedInstallation{
+		Path: installationPath,
+	}
+	r.Installations = append(r.Installations, inst)
+
+	if parent := r.Get(path.Dir(installationPath)); parent != nil && parent != inst {
+		parent.SubInstallations = append(parent.SubInstallations, installationPath)
 	}
+	return inst
+}
+
+// SimulatorCallbacks collects the objects found by the installation simulator in a render result.
+type SimulatorCallbacks struct {
+	result   *RenderResult
+	progress io.Writer
+}
+
+func (c SimulatorCallbacks) OnInstallation(installationPath string, installation *lsv1alpha1.Installation) {
+	fmt.Fprintf(c.progress, "executing installation %s\n", installationPath)
+	c.result.getOrCreate(installationPath).Installation = installation
+}
+
+func (c SimulatorCallbacks) OnInstallationTemplateState(installationPath string, state map[string][]byte) {
+	c.result.getOrCreate(installationPath).InstallationTemplateState = state
 }
 
 func (c SimulatorCallbacks) OnImports(installationPath string, imports map[string]interface{}) {
-	if c.options.outputResources.Has(OutputResourceImports) {
-		_ = c.options.out(c.fs, imports, installationPath, "imports")
-	}
+	c.result.getOrCreate(installationPath).Imports = imports
 }
 
 func (c SimulatorCallbacks) OnDeployItem(installationPath string, deployItem *lsv1alpha1.DeployItem) {
-	fmt.Printf("executing deploy item %s\n", path.Join(installationPath, deployItem.Name))
-
-	if c.options.outputResources.Has(OutputResourceDeployItems) {
-		_ = c.options.out(c.fs, deployItem, installationPath, "deployitems", deployItem.Name)
-	}
+	fmt.Fprintf(c.progress, "executing deploy item %s\n", path.Join(installationPath, deployItem.Name))
+	inst := c.result.getOrCreate(installationPath)
+	inst.DeployItems = append(inst.DeployItems, deployItem)
 }
 
 func (c SimulatorCallbacks) OnDeployItemTemplateState(installationPath string, state map[string][]byte) {
-	if c.options.outputResources.Has(OutputResourceDeployItems) {
-		_ = c.options.out(c.fs, formatState(state), installationPath, "deployitems", "state")
-	}
+	c.result.getOrCreate(installationPath).DeployItemTemplateState = state
 }
 
 func (c SimulatorCallbacks) OnExports(installationPath string, exports map[string]interface{}) {
-	if c.options.outputResources.Has(OutputResourceExports) {
-		_ = c.options.out(c.fs, exports, installationPath, "exports")
-	}
+	c.result.getOrCreate(installationPath).Exports = exports
 }
 
+// Run renders the blueprint and prints the rendered resources or writes them to the output directory.
 func (o *RenderOptions) Run(ctx context.Context, log logr.Logger, fs vfs.FileSystem) error {
+	result, err := o.Render(ctx, log, fs)
+	if err != nil {
+		return err
+	}
+	return o.write(fs, result)
+}
+
+// Render renders the blueprint and returns the rendered resources without printing them.
+func (o *RenderOptions) Render(ctx context.Context, log logr.Logger, fs vfs.FileSystem) (*RenderResult, error) {
 	log.V(3).Info(fmt.Sprintf("rendering %s", strings.Join(o.outputResources.List(), ", ")))
 
 	overlayFs := layerfs.New(memoryfs.New(), fs)
 	if err := overlayFs.MkdirAll("/apptmp", os.ModePerm); err != nil {
-		return err
+		return nil, err
 	}
 
 	imports, err := o.setupImports(overlayFs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	blueprint, err := blueprints.NewFromFs(o.blueprintFs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	componentDescriptorList := cdv2.ComponentDescriptorList{
@@ -233,103 +319,275 @@ func (o *RenderOptions) Run(ctx context.Context, log logr.Logger, fs vfs.FileSys
 		for _, ref := range o.componentDescriptor.ComponentReferences {
 			if _, err := componentDescriptorList.GetComponent(ref.ComponentName, ref.Version); err != nil {
 				// not found
-				fmt.Printf("resolving component descriptor reference %q (%s:%s)\n", ref.Name, ref.ComponentName, ref.Version)
+				fmt.Fprintf(o.progress(), "resolving component descriptor reference %q (%s:%s)\n", ref.Name, ref.ComponentName, ref.Version)
 				cd, err := o.componentResolver.Resolve(ctx, o.componentDescriptor.GetEffectiveRepositoryContext(), ref.ComponentName, ref.Version)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				componentDescriptorList.Components = append(componentDescriptorList.Components, *cd)
 			}
 		}
 	}
 
+	result := &RenderResult{}
+
 	if len(o.ExportTemplatesPath) != 0 {
 		simulator, err := lsutils.NewInstallationSimulator(&componentDescriptorList, o.componentResolver, nil, o.exportTemplates)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		simulator.SetCallbacks(SimulatorCallbacks{options: o, fs: fs})
+		simulator.SetCallbacks(SimulatorCallbacks{result: result, progress: o.progress()})
 		_, err = simulator.Run(o.componentDescriptor, blueprint, imports.Imports, imports.Imports)
 		if err != nil {
-			return err
-		}
-	} else {
-		blueprintRenderer := lsutils.NewBlueprintRenderer(&componentDescriptorList, o.componentResolver, nil)
-
-		installation := &lsv1alpha1.Installation{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "root",
-				Namespace: "default",
-			},
-			Spec: lsv1alpha1.InstallationSpec{
-				Blueprint: lsv1alpha1.BlueprintDefinition{
-					Reference: &lsv1alpha1.RemoteBlueprintReference{
-						ResourceName: "example-blueprint",
-					},
-				},
-			},
+			resolved := &lsutils.ResolvedInstallation{
+				ComponentDescriptor: o.componentDescriptor,
+				Installation:        o.rootInstallation(),
+				Blueprint:           blueprint,
+			}
+			return nil, o.locateTemplateError(ctx, &componentDescriptorList, resolved, imports.Imports, err)
 		}
 
-		if o.componentDescriptor != nil {
-			installation.Spec.ComponentDescriptor = &lsv1alpha1.ComponentDescriptorDefinition{
-				Reference: &lsv1alpha1.ComponentDescriptorReference{
-					ComponentName: o.componentDescriptor.ComponentSpec.Name,
-					Version:       o.componentDescriptor.ComponentSpec.Version,
-				},
+		for _, inst := range result.Installations {
+			if len(inst.DeployItems) == 0 {
+				continue
 			}
-		} else {
-			installation.Spec.ComponentDescriptor = &lsv1alpha1.ComponentDescriptorDefinition{
-				Reference: &lsv1alpha1.ComponentDescriptorReference{
-					ComponentName: "my-example-component",
-					Version:       "v0.0.0",
-				},
+			resolved := &lsutils.ResolvedInstallation{
+				ComponentDescriptor: o.componentDescriptor,
+				Installation:        inst.Installation,
+				Blueprint:           blueprint,
+			}
+			if inst.Path != RootInstallationName {
+				resolved, err = o.resolveInstallation(ctx, inst.Installation)
+				if err != nil {
+					return nil, fmt.Errorf("unable to resolve installation %s: %w", inst.Path, err)
+				}
+			}
+			inst.DeployItemSources, err = o.deployItemSources(ctx, &componentDescriptorList, resolved, inst.Imports, inst.DeployItems)
+			if err != nil {
+				return nil, fmt.Errorf("unable to determine deploy executions of installation %s: %w", inst.Path, err)
 			}
 		}
+	} else {
+		blueprintRenderer := lsutils.NewBlueprintRenderer(&componentDescriptorList, o.componentResolver, nil)
 
-		out, err := blueprintRenderer.RenderDeployItemsAndSubInstallations(&lsutils.ResolvedInstallation{
+		installation := o.rootInstallation()
+		resolved := &lsutils.ResolvedInstallation{
 			ComponentDescriptor: o.componentDescriptor,
 			Installation:        installation,
 			Blueprint:           blueprint,
-		}, imports.Imports)
-
+		}
+		out, err := blueprintRenderer.RenderDeployItemsAndSubInstallations(resolved, imports.Imports)
 		if err != nil {
-			return err
+			return nil, o.locateTemplateError(ctx, &componentDescriptorList, resolved, imports.Imports, err)
 		}
 
-		if o.outputResources.Has(OutputResourceDeployItems) {
-			if len(out.DeployItems) == 0 {
-				fmt.Println("No deploy items defined")
-			}
+		root := result.getOrCreate(RootInstallationName)
+		root.Installation = installation
+		root.Imports = imports.Imports
+		root.DeployItems = out.DeployItems
+		root.DeployItemTemplateState = out.DeployItemTemplateState
+		root.InstallationTemplateState = out.InstallationTemplateState
+		for _, inst := range out.Installations {
+			result.getOrCreate(path.Join(RootInstallationName, inst.Installation.Name)).Installation = inst.Installation
+		}
 
-			if err := o.out(fs, formatState(out.DeployItemTemplateState), DeployItemOutputDir, "state"); err != nil {
-				return err
+		if len(out.DeployItems) != 0 {
+			root.DeployItemSources, err = o.deployItemSources(ctx, &componentDescriptorList, resolved, imports.Imports, out.DeployItems)
+			if err != nil {
+				return nil, fmt.Errorf("unable to determine deploy executions: %w", err)
 			}
+		}
+	}
 
-			for _, diTmpl := range out.DeployItems {
-				if err := o.out(fs, diTmpl, DeployItemOutputDir, diTmpl.Name); err != nil {
-					return err
-				}
-			}
+	if o.Expand {
+		if err := o.expand(ctx, log, result); err != nil {
+			return nil, err
 		}
+	}
+
+	return result, nil
+}
+
+// progress returns the writer for progress messages.
+func (o *RenderOptions) progress() io.Writer {
+	if o.silent {
+		return ioutil.Discard
+	}
+	return os.Stdout
+}
 
-		if o.outputResources.Has(OutputResourceSubinstallations) {
-			if len(out.Installations) == 0 {
-				fmt.Println("No subinstallations defined")
+// expand expands all manifest and helm deploy items of the render result into plain kubernetes objects.
+func (o *RenderOptions) expand(ctx context.Context, log logr.Logger, result *RenderResult) error {
+	expander := deployitems.NewExpander(log, o.ociClient, o.componentResolver)
+	for _, inst := range result.Installations {
+		for _, di := range inst.DeployItems {
+			if !deployitems.CanExpand(di) {
+				log.V(3).Info(fmt.Sprintf("deploy item %s of type %s cannot be expanded", di.Name, di.Spec.Type))
+				continue
+			}
+			objects, err := expander.Expand(ctx, di)
+			if err != nil {
+				return fmt.Errorf("unable to expand deploy item %s of installation %s: %w", di.Name, inst.Path, err)
 			}
+			if inst.Manifests == nil {
+				inst.Manifests = map[string][]*unstructured.Unstructured{}
+			}
+			inst.Manifests[di.Name] = objects
+		}
+	}
+	return nil
+}
+
+// rootInstallation returns the installation that is used to render the blueprint.
+func (o *RenderOptions) rootInstallation() *lsv1alpha1.Installation {
+	installation := &lsv1alpha1.Installation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RootInstallationName,
+			Namespace: "default",
+		},
+		Spec: lsv1alpha1.InstallationSpec{
+			Blueprint: lsv1alpha1.BlueprintDefinition{
+				Reference: &lsv1alpha1.RemoteBlueprintReference{
+					ResourceName: "example-blueprint",
+				},
+			},
+		},
+	}
 
-			if err := o.out(fs, formatState(out.InstallationTemplateState), SubinstallationOutputDir, "state"); err != nil {
-				return err
+	if o.componentDescriptor != nil {
+		installation.Spec.ComponentDescriptor = &lsv1alpha1.ComponentDescriptorDefinition{
+			Reference: &lsv1alpha1.ComponentDescriptorReference{
+				ComponentName: o.componentDescriptor.ComponentSpec.Name,
+				Version:       o.componentDescriptor.ComponentSpec.Version,
+			},
+		}
+	} else {
+		installation.Spec.ComponentDescriptor = &lsv1alpha1.ComponentDescriptorDefinition{
+			Reference: &lsv1alpha1.ComponentDescriptorReference{
+				ComponentName: "my-example-component",
+				Version:       "v0.0.0",
+			},
+		}
+	}
+	return installation
+}
+
+// resolveInstallation resolves the component descriptor and the blueprint of a rendered subinstallation.
+func (o *RenderOptions) resolveInstallation(ctx context.Context, installation *lsv1alpha1.Installation) (*lsutils.ResolvedInstallation, error) {
+	if installation.Spec.ComponentDescriptor == nil || installation.Spec.ComponentDescriptor.Reference == nil {
+		return nil, fmt.Errorf("no component descriptor reference defined")
+	}
+	cdRef := installation.Spec.ComponentDescriptor.Reference
+
+	cd, err := o.componentResolver.Resolve(ctx, cdRef.RepositoryContext, cdRef.ComponentName, cdRef.Version)
+	if err != nil {
+		return nil, err
+	}
+	blueprint, err := blueprints.Resolve(ctx, o.componentResolver, cdRef, installation.Spec.Blueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lsutils.ResolvedInstallation{
+		ComponentDescriptor: cd,
+		Installation:        installation,
+		Blueprint:           blueprint,
+	}, nil
+}
+
+// newTemplater creates a templater for the executions of the given installation.
+func (o *RenderOptions) newTemplater(ctx context.Context, inst *lsutils.ResolvedInstallation) (*template.Templater, error) {
+	var blobResolver ctf.BlobResolver
+	if inst.ComponentDescriptor != nil {
+		var err error
+		_, blobResolver, err = o.componentResolver.ResolveWithBlobResolver(ctx, inst.ComponentDescriptor.GetEffectiveRepositoryContext(),
+			inst.ComponentDescriptor.GetName(), inst.ComponentDescriptor.GetVersion())
+		if err != nil {
+			return nil, fmt.Errorf("unable to get blob resolver: %w", err)
+		}
+	}
+
+	stateHandler := template.NewMemoryStateHandler()
+	formatter := template.NewTemplateInputFormatter(true)
+	return template.New(gotemplate.New(blobResolver, stateHandler).WithInputFormatter(formatter),
+		spiff.New(stateHandler).WithInputFormatter(formatter)), nil
+}
+
+// deployItemSources returns the name of the deploy execution for every rendered deploy item of the installation.
+func (o *RenderOptions) deployItemSources(ctx context.Context, cdList *cdv2.ComponentDescriptorList, inst *lsutils.ResolvedInstallation, imports map[string]interface{}, deployItems []*lsv1alpha1.DeployItem) (map[string]string, error) {
+	names := make([]string, 0, len(deployItems))
+	for _, di := range deployItems {
+		names = append(names, di.Name)
+	}
+	newTemplater := func() (*template.Templater, error) {
+		return o.newTemplater(ctx, inst)
+	}
+	return executions.DeployItemSources(newTemplater, template.DeployExecutionOptions{
+		Imports:              imports,
+		Blueprint:            inst.Blueprint,
+		ComponentDescriptor:  inst.ComponentDescriptor,
+		ComponentDescriptors: cdList,
+		Installation:         inst.Installation,
+	}, names)
+}
+
+// locateTemplateError templates every deploy and subinstallation execution of the local blueprint on its own
+// to find the execution that caused the render error.
+// If an execution fails, its error is returned with the location in the blueprint directory.
+// Otherwise, e.g. if the error is caused by a remote blueprint of a subinstallation, the render error is returned.
+func (o *RenderOptions) locateTemplateError(ctx context.Context, cdList *cdv2.ComponentDescriptorList, inst *lsutils.ResolvedInstallation, imports map[string]interface{}, renderErr error) error {
+	if !executions.IsTemplateError(renderErr) {
+		return renderErr
+	}
+	sources, err := executions.LocateExecutions(o.blueprintFs)
+	if err != nil {
+		return renderErr
+	}
+
+	newTemplater := func() (*template.Templater, error) {
+		return o.newTemplater(ctx, inst)
+	}
+	_, errs := executions.TemplateDeployExecutions(newTemplater, template.DeployExecutionOptions{
+		Imports:              imports,
+		Blueprint:            inst.Blueprint,
+		ComponentDescriptor:  inst.ComponentDescriptor,
+		ComponentDescriptors: cdList,
+		Installation:         inst.Installation,
+	})
+	for _, execution := range inst.Blueprint.Info.DeployExecutions {
+		if err, ok := errs[execution.Name]; ok {
+			if source := executions.Find(sources, executions.DeployExecution, execution.Name); source != nil {
+				return executions.NewTemplateError(source, err, executions.ImportKeys(imports))
 			}
+			return renderErr
+		}
+	}
 
-			for _, inst := range out.Installations {
-				if err := o.out(fs, inst.Installation, SubinstallationOutputDir, inst.Installation.Name); err != nil {
-					return err
-				}
+	for _, execution := range inst.Blueprint.Info.SubinstallationExecutions {
+		info := *inst.Blueprint.Info
+		info.DeployExecutions = nil
+		info.SubinstallationExecutions = []lsv1alpha1.TemplateExecutor{execution}
+
+		templater, err := o.newTemplater(ctx, inst)
+		if err != nil {
+			return renderErr
+		}
+		_, err = templater.TemplateSubinstallationExecutions(template.DeployExecutionOptions{
+			Imports:              imports,
+			Blueprint:            blueprints.New(&info, inst.Blueprint.Fs),
+			ComponentDescriptor:  inst.ComponentDescriptor,
+			ComponentDescriptors: cdList,
+			Installation:         inst.Installation,
+		})
+		if err != nil {
+			if source := executions.Find(sources, executions.SubinstallationExecution, execution.Name); source != nil {
+				return executions.NewTemplateError(source, err, executions.ImportKeys(imports))
 			}
+			return renderErr
 		}
 	}
 
-	return nil
+	return renderErr
 }
 
 type Imports struct {
@@ -379,12 +637,7 @@ func (o *RenderOptions) Complete(log logr.Logger, args []string, fs vfs.FileSyst
 
 	o.BlueprintPath = absBlueprintPath
 
-	data, err := vfs.ReadFile(fs, filepath.Join(o.BlueprintPath, lsv1alpha1.BlueprintFileName))
-	if err != nil {
-		return fmt.Errorf("unable to read blueprint from %s: %w", filepath.Join(o.BlueprintPath, lsv1alpha1.BlueprintFileName), err)
-	}
-	o.blueprint = &lsv1alpha1.Blueprint{}
-	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, o.blueprint); err != nil {
+	if err := o.readBlueprint(fs); err != nil {
 		return err
 	}
 	o.blueprintFs, err = projectionfs.New(fs, o.BlueprintPath)
@@ -393,13 +646,74 @@ func (o *RenderOptions) Complete(log logr.Logger, args []string, fs vfs.FileSyst
 	}
 
 	if len(o.ComponentDescriptorPath) != 0 {
-		absComponentDescriptorPath, err := filepath.Abs(o.ComponentDescriptorPath)
+		o.ComponentDescriptorPath, err = filepath.Abs(o.ComponentDescriptorPath)
 		if err != nil {
 			return fmt.Errorf("unable get absolute component descriptor path for %s: %w", o.ComponentDescriptorPath, err)
 		}
+	}
+	for i, cdPath := range o.AdditionalComponentDescriptorPath {
+		o.AdditionalComponentDescriptorPath[i], err = filepath.Abs(cdPath)
+		if err != nil {
+			return fmt.Errorf("unable get absolute component descriptor path for %s: %w", cdPath, err)
+		}
+	}
+	if len(o.ResourcesPath) != 0 {
+		o.ResourcesPath, err = filepath.Abs(o.ResourcesPath)
+		if err != nil {
+			return fmt.Errorf("unable get absolute resources path for %s: %w", o.ResourcesPath, err)
+		}
+	}
+	if len(o.ExportTemplatesPath) != 0 {
+		o.ExportTemplatesPath, err = filepath.Abs(o.ExportTemplatesPath)
+		if err != nil {
+			return fmt.Errorf("unable get absolute exports template path for %s: %w", o.ExportTemplatesPath, err)
+		}
+	}
+
+	if err := o.parseOutputResources(args); err != nil {
+		return err
+	}
+
+	if err := o.readComponentDescriptors(fs); err != nil {
+		return err
+	}
+
+	if err := o.readExportTemplates(fs); err != nil {
+		return err
+	}
+
+	// build component resolver with oci client
+	ociClient, _, err := o.OCIOptions.Build(log, fs)
+	if err != nil {
+		return err
+	}
+	o.ociClient = ociClient
+
+	if err := o.buildComponentResolver(log, fs); err != nil {
+		return err
+	}
+
+	return o.Validate()
+}
 
-		o.ComponentDescriptorPath = absComponentDescriptorPath
+// readBlueprint reads the blueprint definition from the blueprint directory.
+func (o *RenderOptions) readBlueprint(fs vfs.FileSystem) error {
+	data, err := vfs.ReadFile(fs, filepath.Join(o.BlueprintPath, lsv1alpha1.BlueprintFileName))
+	if err != nil {
+		return fmt.Errorf("unable to read blueprint from %s: %w", filepath.Join(o.BlueprintPath, lsv1alpha1.BlueprintFileName), err)
+	}
+	o.blueprint = &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, o.blueprint); err != nil {
+		return err
+	}
+	return nil
+}
 
+// readComponentDescriptors reads the component descriptor, the additional component descriptors and
+// the local resources that are added to the component descriptor.
+func (o *RenderOptions) readComponentDescriptors(fs vfs.FileSystem) error {
+	o.componentDescriptor = nil
+	if len(o.ComponentDescriptorPath) != 0 {
 		data, err := vfs.ReadFile(fs, o.ComponentDescriptorPath)
 		if err != nil {
 			return fmt.Errorf("unable to read component descriptor from %s: %w", o.ComponentDescriptorPath, err)
@@ -413,14 +727,9 @@ func (o *RenderOptions) Complete(log logr.Logger, args []string, fs vfs.FileSyst
 
 	o.componentDescriptorList = &cdv2.ComponentDescriptorList{}
 	for _, cdPath := range o.AdditionalComponentDescriptorPath {
-		absCdPath, err := filepath.Abs(cdPath)
-		if err != nil {
-			return fmt.Errorf("unable get absolute component descriptor path for %s: %w", cdPath, err)
-		}
-
-		data, err := vfs.ReadFile(fs, absCdPath)
+		data, err := vfs.ReadFile(fs, cdPath)
 		if err != nil {
-			return fmt.Errorf("unable to read component descriptor from %s: %w", o.ComponentDescriptorPath, err)
+			return fmt.Errorf("unable to read component descriptor from %s: %w", cdPath, err)
 		}
 		cd := cdv2.ComponentDescriptor{}
 		if err := codec.Decode(data, &cd); err != nil {
@@ -429,14 +738,9 @@ func (o *RenderOptions) Complete(log logr.Logger, args []string, fs vfs.FileSyst
 		o.componentDescriptorList.Components = append(o.componentDescriptorList.Components, cd)
 	}
 
+	o.resources = nil
 	if len(o.ResourcesPath) != 0 {
-		absResourcesPath, err := filepath.Abs(o.ResourcesPath)
-		if err != nil {
-			return fmt.Errorf("unable get absolute resources path for %s: %w", o.ResourcesPath, err)
-		}
-
-		o.ResourcesPath = absResourcesPath
-
+		var err error
 		resourceReader := components.NewResourceReader(o.ResourcesPath)
 		o.resources, err = resourceReader.Read()
 		if err != nil {
@@ -444,62 +748,56 @@ func (o *RenderOptions) Complete(log logr.Logger, args []string, fs vfs.FileSyst
 		}
 	}
 
-	if err := o.parseOutputResources(args); err != nil {
-		return err
-	}
-
 	if len(o.resources) > 0 {
 		if o.componentDescriptor == nil {
 			return fmt.Errorf("if you specify a resources yaml file (option -r) you must also specify a component descriptor (option -c)")
 		}
 
+		var err error
 		o.componentDescriptor, err = resolver.AddLocalResourcesForRender(o.componentDescriptor, o.resources)
-
 		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	if len(o.ExportTemplatesPath) != 0 {
-		absExportTemplatesPath, err := filepath.Abs(o.ExportTemplatesPath)
-		if err != nil {
-			return fmt.Errorf("unable get absolute exports template path for %s: %w", o.ExportTemplatesPath, err)
-		}
-
-		o.ExportTemplatesPath = absExportTemplatesPath
-
-		data, err := vfs.ReadFile(fs, o.ExportTemplatesPath)
-		if err != nil {
-			return fmt.Errorf("failed to read exports template path %s: %w", o.ExportTemplatesPath, err)
-		}
+// readExportTemplates reads the export templates.
+func (o *RenderOptions) readExportTemplates(fs vfs.FileSystem) error {
+	o.exportTemplates = lsutils.ExportTemplates{}
+	if len(o.ExportTemplatesPath) == 0 {
+		return nil
+	}
 
-		err = yaml.Unmarshal(data, &o.exportTemplates)
-		if err != nil {
-			return fmt.Errorf("failed to parse export templates: %w", err)
-		}
+	data, err := vfs.ReadFile(fs, o.ExportTemplatesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read exports template path %s: %w", o.ExportTemplatesPath, err)
 	}
 
-	// build component resolver with oci client
-	ociClient, _, err := o.OCIOptions.Build(log, fs)
+	err = yaml.Unmarshal(data, &o.exportTemplates)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to parse export templates: %w", err)
 	}
+	return nil
+}
 
+// buildComponentResolver builds the component resolver for the local component descriptors with the oci client.
+func (o *RenderOptions) buildComponentResolver(log logr.Logger, fs vfs.FileSystem) error {
+	var err error
 	if o.componentDescriptor == nil {
-		o.componentResolver, err = componentsregistry.NewOCIRegistryWithOCIClient(log, ociClient)
+		o.componentResolver, err = componentsregistry.NewOCIRegistryWithOCIClient(log, o.ociClient)
 		if err != nil {
 			return err
 		}
 	} else {
-		o.componentResolver, err = componentsregistry.NewOCIRegistryWithOCIClient(log, ociClient, o.componentDescriptor)
+		o.componentResolver, err = componentsregistry.NewOCIRegistryWithOCIClient(log, o.ociClient, o.componentDescriptor)
 		if err != nil {
 			return err
 		}
 	}
 
 	o.componentResolver = resolver.NewRenderComponentResolver(o.componentResolver, o.componentDescriptor, o.componentDescriptorList, o.ResourcesPath, fs)
-
-	return o.Validate()
+	return nil
 }
 
 // Validate validates push options
@@ -543,42 +841,3 @@ func (o *RenderOptions) parseOutputResources(args []string) error {
 	}
 	return nil
 }
-
-func (o *RenderOptions) out(fs vfs.FileSystem, obj interface{}, names ...string) error {
-
-	var data []byte
-	switch o.OutputFormat {
-	case YAMLOut:
-		var err error
-		data, err = yaml.Marshal(obj)
-		if err != nil {
-			return err
-		}
-	case JSONOut:
-		var err error
-		data, err = json.MarshalIndent(obj, "", "  ")
-		if err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("unknown output format '%s'", o.OutputFormat)
-	}
-
-	// print to stdout if no directory is given
-	if len(o.OutDir) == 0 {
-
-		if len(names) != 0 {
-			fmt.Println("--------------------------------------")
-			fmt.Printf("-- %s\n", strings.Join(names, " "))
-			fmt.Println("--------------------------------------")
-		}
-		fmt.Printf("%s\n", data)
-		return nil
-	}
-
-	objFilePath := filepath.Join(append([]string{o.OutDir}, names...)...)
-	if err := fs.MkdirAll(filepath.Dir(objFilePath), os.ModePerm); err != nil {
-		return fmt.Errorf("unable to create path %s", o.OutDir)
-	}
-	return vfs.WriteFile(fs, objFilePath, data, os.ModePerm)
-}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/schemas"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+// ExampleValuesOptions describes the options for the render-example-values command.
+type ExampleValuesOptions struct {
+	RenderOptions
+
+	// OutputPath is the file the example values are written to.
+	OutputPath string
+}
+
+// NewExampleValuesCommand creates a new command to generate an example values file for the render command.
+func NewExampleValuesCommand(ctx context.Context) *cobra.Command {
+	opts := &ExampleValuesOptions{}
+	cmd := &cobra.Command{
+		Use:  "render-example-values",
+		Args: cobra.ExactArgs(1),
+		Example: `landscaper-cli blueprints render-example-values BLUEPRINT_DIR -w values.yaml
+landscaper-cli blueprints render-example-values BLUEPRINT_DIR -c component-descriptor.yaml`,
+		Short: "generates an example import values file for the render command",
+		Long: `
+Generates an example values file with all imports of a blueprint in the format expected by the render command.
+
+The example values are taken from the default values of the imports and from the defaults, examples,
+const and enum values of their JSON schemas. Otherwise, objects are generated with all their properties,
+arrays with a single item and scalar values with empty values.
+Each value is commented with its description, type and whether it is required.
+
+References in the JSON schemas are resolved. References to the component descriptor are only resolved
+if a component descriptor is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(logger.Log, args, osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.Run(osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *ExampleValuesOptions) AddFlags(fs *pflag.FlagSet) {
+	o.addInputFlags(fs)
+	fs.StringVarP(&o.OutputPath, "write", "w", "", "The file the example values are written to. By default, they are printed to stdout.")
+}
+
+func (o *ExampleValuesOptions) Complete(log logr.Logger, args []string, fs vfs.FileSystem) error {
+	o.RenderOptions.OutputFormat = YAMLOut
+	o.RenderOptions.silent = true
+	return o.RenderOptions.Complete(log, args, fs)
+}
+
+// Run generates the example values and prints them or writes them to the output file.
+func (o *ExampleValuesOptions) Run(fs vfs.FileSystem) error {
+	generator := &schemas.ValuesGenerator{
+		Fs:                  o.blueprintFs,
+		ComponentDescriptor: o.componentDescriptor,
+		ComponentResolver:   o.componentResolver,
+	}
+	values, err := generator.Generate()
+	if err != nil {
+		return err
+	}
+
+	data, err := util.MarshalYaml(values)
+	if err != nil {
+		return fmt.Errorf("unable to marshal example values: %w", err)
+	}
+	if len(o.OutputPath) == 0 {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := vfs.WriteFile(fs, o.OutputPath, data, 0644); err != nil {
+		return fmt.Errorf("unable to write example values to %s: %w", o.OutputPath, err)
+	}
+	fmt.Printf("Example values written to %s\n", o.OutputPath)
+	return nil
+}
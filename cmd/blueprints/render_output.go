@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RootInstallationName is the name and the installation path of the rendered root installation.
+	RootInstallationName = "root"
+
+	// IndexFileName is the name of the index file that is written to the root of the output directory.
+	// In contrast to the other files, the index is always written as yaml, independent of the output format.
+	IndexFileName = "index.yaml"
+	// InstallationFileName is the name of the file containing the installation.
+	InstallationFileName = "installation"
+	// ImportsFileName is the name of the file containing the imports of an installation.
+	ImportsFileName = "imports"
+	// ExportsFileName is the name of the file containing the exports of an installation.
+	ExportsFileName = "exports"
+	// DeployItemOutputDir is the name of the directory containing the deploy items of an installation.
+	// It is also the name of the state file of the deploy item templates.
+	DeployItemOutputDir = "deployitems"
+	// SubinstallationOutputDir is the name of the directory containing the subinstallations of an installation.
+	// It is also the name of the state file of the subinstallation templates.
+	SubinstallationOutputDir = "subinstallations"
+	// StateOutputDir is the name of the directory containing the template state of an installation.
+	StateOutputDir = "state"
+	// ManifestsOutputDir is the name of the directory containing the expanded kubernetes objects of the deploy items.
+	ManifestsOutputDir = "manifests"
+)
+
+// RenderIndex describes all files that have been written by the render command.
+// All file paths are relative to the output directory.
+type RenderIndex struct {
+	// Installations contains all rendered installations.
+	Installations []RenderIndexInstallation `json:"installations"`
+}
+
+// RenderIndexInstallation describes the files of a rendered installation.
+type RenderIndexInstallation struct {
+	// Path is the installation path, e.g. "root/subinst-a".
+	Path string `json:"path"`
+	// Installation is the file containing the installation.
+	Installation string `json:"installation,omitempty"`
+	// Imports is the file containing the imports of the installation.
+	Imports string `json:"imports,omitempty"`
+	// Exports is the file containing the exports of the installation.
+	Exports string `json:"exports,omitempty"`
+	// DeployItems contains the rendered deploy items of the installation.
+	DeployItems []RenderIndexDeployItem `json:"deployItems,omitempty"`
+	// SubInstallations contains the installation paths of all subinstallations.
+	SubInstallations []string `json:"subinstallations,omitempty"`
+}
+
+// RenderIndexDeployItem describes a rendered deploy item.
+type RenderIndexDeployItem struct {
+	// Name is the name of the deploy item.
+	Name string `json:"name"`
+	// Type is the deployer type of the deploy item.
+	Type lsv1alpha1.DeployItemType `json:"type"`
+	// Target is the target the deploy item is deployed to.
+	Target *lsv1alpha1.ObjectReference `json:"target,omitempty"`
+	// Execution is the name of the deploy execution that rendered the deploy item.
+	Execution string `json:"execution,omitempty"`
+	// File is the file containing the deploy item.
+	File string `json:"file,omitempty"`
+	// Manifests is the file containing the expanded kubernetes objects of the deploy item.
+	Manifests string `json:"manifests,omitempty"`
+}
+
+// write prints the render result or writes it to the output directory.
+func (o *RenderOptions) write(fs vfs.FileSystem, result *RenderResult) error {
+	index := &RenderIndex{
+		Installations: make([]RenderIndexInstallation, 0, len(result.Installations)),
+	}
+
+	for _, inst := range result.Installations {
+		entry := RenderIndexInstallation{
+			Path:             inst.Path,
+			SubInstallations: inst.SubInstallations,
+		}
+		dir := inst.Path
+		if len(o.OutDir) != 0 {
+			dir = installationOutputDir(inst.Path)
+		}
+
+		var err error
+		if o.outputResources.Has(OutputResourceSubinstallations) && inst.Installation != nil {
+			if entry.Installation, err = o.out(fs, inst.Installation, dir, InstallationFileName); err != nil {
+				return err
+			}
+		}
+
+		if o.outputResources.Has(OutputResourceImports) && inst.Imports != nil {
+			if entry.Imports, err = o.out(fs, inst.Imports, dir, ImportsFileName); err != nil {
+				return err
+			}
+		}
+
+		if o.outputResources.Has(OutputResourceDeployItems) {
+			if len(o.ExportTemplatesPath) == 0 && inst.Path == RootInstallationName && len(inst.DeployItems) == 0 {
+				fmt.Fprintln(o.progress(), "No deploy items defined")
+			}
+			if inst.DeployItemTemplateState != nil {
+				if _, err := o.out(fs, formatState(inst.DeployItemTemplateState), dir, StateOutputDir, DeployItemOutputDir); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, di := range inst.DeployItems {
+			diEntry := RenderIndexDeployItem{
+				Name:      di.Name,
+				Type:      di.Spec.Type,
+				Target:    di.Spec.Target,
+				Execution: inst.DeployItemSources[di.Name],
+			}
+			if o.outputResources.Has(OutputResourceDeployItems) {
+				if diEntry.File, err = o.out(fs, di, dir, DeployItemOutputDir, di.Name); err != nil {
+					return err
+				}
+				if objects, ok := inst.Manifests[di.Name]; ok {
+					if diEntry.Manifests, err = o.out(fs, manifestList(objects), dir, ManifestsOutputDir, di.Name); err != nil {
+						return err
+					}
+				}
+			}
+			entry.DeployItems = append(entry.DeployItems, diEntry)
+		}
+
+		if o.outputResources.Has(OutputResourceSubinstallations) {
+			if len(o.ExportTemplatesPath) == 0 && inst.Path == RootInstallationName && len(inst.SubInstallations) == 0 {
+				fmt.Fprintln(o.progress(), "No subinstallations defined")
+			}
+			if inst.InstallationTemplateState != nil {
+				if _, err := o.out(fs, formatState(inst.InstallationTemplateState), dir, StateOutputDir, SubinstallationOutputDir); err != nil {
+					return err
+				}
+			}
+		}
+
+		if o.outputResources.Has(OutputResourceExports) && inst.Exports != nil {
+			if entry.Exports, err = o.out(fs, inst.Exports, dir, ExportsFileName); err != nil {
+				return err
+			}
+		}
+
+		index.Installations = append(index.Installations, entry)
+	}
+
+	if len(o.OutDir) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return vfs.WriteFile(fs, filepath.Join(o.OutDir, IndexFileName), data, os.ModePerm)
+}
+
+// installationOutputDir returns the directory of the installation with the given installation path.
+// Subinstallations are written to the subinstallation directory of their parent,
+// so that they cannot collide with the other directories of the parent, e.g. a subinstallation named "deployitems".
+func installationOutputDir(installationPath string) string {
+	return strings.Join(strings.Split(installationPath, "/"), "/"+SubinstallationOutputDir+"/")
+}
+
+// out prints the given object to stdout or writes it to the output directory.
+// The names define the path of the file relative to the output directory.
+// The relative file path is returned if the object was written to a file.
+func (o *RenderOptions) out(fs vfs.FileSystem, obj interface{}, names ...string) (string, error) {
+	data, err := o.marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	// print to stdout if no directory is given
+	if len(o.OutDir) == 0 {
+		if len(names) != 0 {
+			fmt.Println("--------------------------------------")
+			fmt.Printf("-- %s\n", strings.Join(names, " "))
+			fmt.Println("--------------------------------------")
+		}
+		fmt.Printf("%s\n", data)
+		return "", nil
+	}
+
+	relFilePath := path.Join(names...) + o.fileExtension()
+	objFilePath := filepath.Join(o.OutDir, filepath.FromSlash(relFilePath))
+	if err := fs.MkdirAll(filepath.Dir(objFilePath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("unable to create path %s", o.OutDir)
+	}
+	if err := vfs.WriteFile(fs, objFilePath, data, os.ModePerm); err != nil {
+		return "", err
+	}
+	return relFilePath, nil
+}
+
+// manifestList wraps the given objects in a kubernetes list so that they can be written to one file.
+func manifestList(objects []*unstructured.Unstructured) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+		},
+		Items: make([]unstructured.Unstructured, 0, len(objects)),
+	}
+	for _, obj := range objects {
+		list.Items = append(list.Items, *obj)
+	}
+	return list
+}
+
+func (o *RenderOptions) marshal(obj interface{}) ([]byte, error) {
+	switch o.OutputFormat {
+	case YAMLOut:
+		return yaml.Marshal(obj)
+	case JSONOut:
+		return json.MarshalIndent(obj, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown output format '%s'", o.OutputFormat)
+	}
+}
+
+func (o *RenderOptions) fileExtension() string {
+	if o.OutputFormat == JSONOut {
+		return ".json"
+	}
+	return ".yaml"
+}
@@ -6,12 +6,15 @@ package blueprints_test
 
 import (
 	"context"
+	"errors"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
 	"github.com/go-logr/logr"
 	"github.com/mandelsoft/vfs/pkg/layerfs"
 	"github.com/mandelsoft/vfs/pkg/memoryfs"
@@ -23,6 +26,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/gardener/landscapercli/cmd/blueprints"
+	"github.com/gardener/landscapercli/pkg/executions"
 )
 
 func TestRenderCommandWithComponentDescriptor(t *testing.T) {
@@ -45,11 +49,14 @@ func TestRenderCommandWithComponentDescriptor(t *testing.T) {
 	fail(a.Equal(absBlueprintPath, renderOpts.BlueprintPath))
 	fail(a.NoError(renderOpts.Run(context.TODO(), logr.Discard(), testdataFs)))
 
-	renderedFiles, err := vfs.ReadDir(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.DeployItemOutputDir))
+	renderedFiles, err := vfs.ReadDir(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.DeployItemOutputDir))
 	fail(a.NoError(err))
-	a.Len(renderedFiles, 2, "expect a deploy item and the state file")
+	a.Len(renderedFiles, 1, "expect a deploy item")
+	stateExists, err := vfs.FileExists(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.StateOutputDir, "deployitems.yaml"))
+	fail(a.NoError(err))
+	a.True(stateExists, "expect the state file of the deploy items")
 
-	data, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.DeployItemOutputDir, renderedFiles[0].Name()))
+	data, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.DeployItemOutputDir, renderedFiles[0].Name()))
 	fail(a.NoError(err))
 	var actual unstructured.Unstructured
 	fail(a.NoError(yaml.Unmarshal(data, &actual)))
@@ -62,6 +69,23 @@ func TestRenderCommandWithComponentDescriptor(t *testing.T) {
 	assertNestedString(actual, "example-blueprint", "spec", "config", "blueprint", "ref", "resourceName")
 	assertNestedString(actual, "example.com/render-cmd", "spec", "config", "componentDescriptorDef", "ref", "componentName")
 	assertNestedString(actual, "0.1.0", "spec", "config", "componentDescriptorDef", "ref", "version")
+
+	indexData, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.IndexFileName))
+	fail(a.NoError(err))
+	index := &blueprints.RenderIndex{}
+	fail(a.NoError(yaml.Unmarshal(indexData, index)))
+	fail(a.Len(index.Installations, 1))
+	a.Equal(blueprints.RootInstallationName, index.Installations[0].Path)
+	a.Equal("root/imports.yaml", index.Installations[0].Imports)
+	a.Equal([]blueprints.RenderIndexDeployItem{
+		{
+			Name:      "first-di",
+			Type:      "mock",
+			Target:    &lsv1alpha1.ObjectReference{Name: "my-target", Namespace: "test"},
+			Execution: "execution-name",
+			File:      "root/deployitems/first-di.yaml",
+		},
+	}, index.Installations[0].DeployItems)
 }
 
 func TestRenderCommandWithDefaults(t *testing.T) {
@@ -83,11 +107,14 @@ func TestRenderCommandWithDefaults(t *testing.T) {
 	fail(a.Equal(absBlueprintFilePath, renderOpts.BlueprintPath))
 	fail(a.NoError(renderOpts.Run(context.TODO(), logr.Discard(), testdataFs)))
 
-	renderedFiles, err := vfs.ReadDir(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.DeployItemOutputDir))
+	renderedFiles, err := vfs.ReadDir(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.DeployItemOutputDir))
 	fail(a.NoError(err))
-	a.Len(renderedFiles, 2, "expect a deploy item and the state file")
+	a.Len(renderedFiles, 1, "expect a deploy item")
+	stateExists, err := vfs.FileExists(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.StateOutputDir, "deployitems.yaml"))
+	fail(a.NoError(err))
+	a.True(stateExists, "expect the state file of the deploy items")
 
-	data, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.DeployItemOutputDir, renderedFiles[0].Name()))
+	data, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.DeployItemOutputDir, renderedFiles[0].Name()))
 	fail(a.NoError(err))
 	var actual unstructured.Unstructured
 	fail(a.NoError(yaml.Unmarshal(data, &actual)))
@@ -204,6 +231,86 @@ imports:
 
 }
 
+func TestRenderCommandWithExpand(t *testing.T) {
+	a := assert.New(t)
+	fail := failFunc(a)
+
+	testdataFs, err := createTestdataFs("/")
+	fail(a.NoError(err))
+	renderOpts := &blueprints.RenderOptions{
+		ValueFiles:   []string{"./testdata/01-render-expand/imports.yaml"},
+		OutDir:       "./out",
+		OutputFormat: blueprints.YAMLOut,
+		Expand:       true,
+	}
+
+	a.NoError(renderOpts.Complete(logr.Discard(), []string{"./testdata/01-render-expand/blueprint"}, testdataFs))
+	fail(a.NoError(renderOpts.Run(context.TODO(), logr.Discard(), testdataFs)))
+
+	data, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.ManifestsOutputDir, "config.yaml"))
+	fail(a.NoError(err))
+	var list unstructured.UnstructuredList
+	fail(a.NoError(yaml.Unmarshal(data, &list)))
+	fail(a.Len(list.Items, 2))
+	a.Equal("Namespace", list.Items[0].GetKind())
+	a.Equal("my-namespace", list.Items[0].GetName())
+	a.Equal("ConfigMap", list.Items[1].GetKind())
+	a.Equal("my-namespace", list.Items[1].GetNamespace())
+
+	indexData, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.IndexFileName))
+	fail(a.NoError(err))
+	index := &blueprints.RenderIndex{}
+	fail(a.NoError(yaml.Unmarshal(indexData, index)))
+	fail(a.Len(index.Installations, 1))
+	fail(a.Len(index.Installations[0].DeployItems, 1))
+	a.Equal("root/manifests/config.yaml", index.Installations[0].DeployItems[0].Manifests)
+}
+
+func TestRenderCommandWritesYAMLIndexForJSONOutput(t *testing.T) {
+	a := assert.New(t)
+	fail := failFunc(a)
+
+	testdataFs, err := createTestdataFs("/")
+	fail(a.NoError(err))
+	renderOpts := &blueprints.RenderOptions{
+		ValueFiles:   []string{"./testdata/01-render-expand/imports.yaml"},
+		OutDir:       "./out",
+		OutputFormat: blueprints.JSONOut,
+	}
+
+	a.NoError(renderOpts.Complete(logr.Discard(), []string{"./testdata/01-render-expand/blueprint"}, testdataFs))
+	fail(a.NoError(renderOpts.Run(context.TODO(), logr.Discard(), testdataFs)))
+
+	indexData, err := vfs.ReadFile(testdataFs, filepath.Join(renderOpts.OutDir, blueprints.IndexFileName))
+	fail(a.NoError(err))
+	a.False(strings.HasPrefix(string(indexData), "{"), "expect the index to be written as yaml")
+	index := &blueprints.RenderIndex{}
+	fail(a.NoError(yaml.Unmarshal(indexData, index)))
+	fail(a.Len(index.Installations, 1))
+	a.Equal("root/installation.json", index.Installations[0].Installation)
+}
+
+func TestRenderCommandWithTemplateError(t *testing.T) {
+	a := assert.New(t)
+	fail := failFunc(a)
+
+	testdataFs, err := createTestdataFs("/")
+	fail(a.NoError(err))
+	renderOpts := &blueprints.RenderOptions{
+		ValueFiles:   []string{"./testdata/00-render/imports.yaml"},
+		OutputFormat: blueprints.YAMLOut,
+	}
+
+	fail(a.NoError(renderOpts.Complete(logr.Discard(), []string{"./testdata/02-render-template-error/blueprint"}, testdataFs)))
+	err = renderOpts.Run(context.TODO(), logr.Discard(), testdataFs)
+	var tmplErr *executions.TemplateError
+	fail(a.True(errors.As(err, &tmplErr), "expect a template error but got %v", err))
+	a.Equal("invalid", tmplErr.Source.Name)
+	a.Equal("blueprint.yaml:27:27", tmplErr.Location())
+	a.Equal([]string{"cluster", "imp1"}, tmplErr.ImportKeys)
+	a.Contains(err.Error(), "> 27 |         value: {{ .imports.cluster.spec.config.value }}")
+}
+
 func TestRenderCommandWithExportTemplates(t *testing.T) {
 	a := assert.New(t)
 	fail := failFunc(a)
@@ -256,24 +363,43 @@ func TestRenderCommandWithExportTemplates(t *testing.T) {
 	})
 	fail(a.NoError(err))
 
-	fail(a.Contains(files, "out/root/installation"))
-	fail(a.Contains(files, "out/root/imports"))
-	fail(a.Contains(files, "out/root/exports"))
+	fail(a.Contains(files, "out/root/installation.yaml"))
+	fail(a.Contains(files, "out/root/imports.yaml"))
+	fail(a.Contains(files, "out/root/exports.yaml"))
+
+	fail(a.Contains(files, "out/root/subinstallations/subinst-a/installation.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-a/imports.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-a/exports.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-a/deployitems/subinst-a-deploy.yaml"))
 
-	fail(a.Contains(files, "out/root/subinst-a/installation"))
-	fail(a.Contains(files, "out/root/subinst-a/imports"))
-	fail(a.Contains(files, "out/root/subinst-a/exports"))
-	fail(a.Contains(files, "out/root/subinst-a/deployitems/subinst-a-deploy"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-b/installation.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-b/imports.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-b/exports.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-b/deployitems/subinst-b-deploy.yaml"))
 
-	fail(a.Contains(files, "out/root/subinst-b/installation"))
-	fail(a.Contains(files, "out/root/subinst-b/imports"))
-	fail(a.Contains(files, "out/root/subinst-b/exports"))
-	fail(a.Contains(files, "out/root/subinst-b/deployitems/subinst-b-deploy"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-c/installation.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-c/imports.yaml"))
+	fail(a.Contains(files, "out/root/subinstallations/subinst-c/exports.yaml"))
+	fail(a.NotContains(files, "out/root/subinstallations/subinst-a/deployitems/subinst-c-deploy.yaml"))
 
-	fail(a.Contains(files, "out/root/subinst-c/installation"))
-	fail(a.Contains(files, "out/root/subinst-c/imports"))
-	fail(a.Contains(files, "out/root/subinst-c/exports"))
-	fail(a.NotContains(files, "out/root/subinst-a/deployitems/subinst-c-deploy"))
+	fail(a.Contains(files, "out/index.yaml"))
+	indexData, err := vfs.ReadFile(testdataFs, "out/index.yaml")
+	fail(a.NoError(err))
+	index := &blueprints.RenderIndex{}
+	fail(a.NoError(yaml.Unmarshal(indexData, index)))
+	fail(a.Len(index.Installations, 4))
+	a.Equal("root", index.Installations[0].Path)
+	a.Equal([]string{"root/subinst-a", "root/subinst-b", "root/subinst-c"}, index.Installations[0].SubInstallations)
+	a.Equal("root/subinst-a", index.Installations[1].Path)
+	a.Equal("root/subinstallations/subinst-a/installation.yaml", index.Installations[1].Installation)
+	fail(a.Len(index.Installations[1].DeployItems, 1))
+	di := index.Installations[1].DeployItems[0]
+	a.Equal("subinst-a-deploy", di.Name)
+	a.Equal("landscaper.gardener.cloud/mock", string(di.Type))
+	a.Equal("deploy-execution", di.Execution)
+	a.Equal("root/subinstallations/subinst-a/deployitems/subinst-a-deploy.yaml", di.File)
+	fail(a.NotNil(di.Target))
+	a.Equal("test-cluster", di.Target.Name)
 
 }
 
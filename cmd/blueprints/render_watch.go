@@ -0,0 +1,341 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// watchDebounce is the time to wait for further file changes before the blueprint is rendered again.
+const watchDebounce = 300 * time.Millisecond
+
+// renderedFiles maps the file paths of a render result to their content.
+type renderedFiles map[string][]byte
+
+// RunWatch renders the blueprint and renders it again whenever one of its inputs changes.
+// After the first render, only the changes since the last render are printed.
+// Render errors are reported but do not stop the watch.
+func (o *RenderOptions) RunWatch(ctx context.Context, log logr.Logger, fs vfs.FileSystem) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	watched, err := o.addWatches(watcher)
+	if err != nil {
+		return err
+	}
+
+	last, err := o.renderToFiles(ctx, log, fs)
+	if err != nil {
+		fmt.Printf("render failed: %s\n", err.Error())
+	} else if err := o.writeFiles(os.Stdout, fs, nil, last); err != nil {
+		return err
+	}
+	fmt.Printf("watching %d files and directories for changes\n", len(watched))
+
+	var (
+		timer   *time.Timer
+		trigger <-chan time.Time
+		changed = map[string]bool{}
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error(err, "error while watching files")
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !o.isWatched(watched, event.Name) {
+				continue
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watcher.Add(event.Name); err != nil {
+						log.Error(err, "unable to watch directory", "path", event.Name)
+					}
+				}
+			}
+			changed[event.Name] = true
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(watchDebounce)
+			trigger = timer.C
+		case <-trigger:
+			trigger = nil
+			changedFiles := sortedKeys(changed)
+			fmt.Printf("\n%s changed, rendering again\n", strings.Join(changedFiles, ", "))
+			changed = map[string]bool{}
+
+			if err := o.reload(log, fs, changedFiles); err != nil {
+				fmt.Printf("render failed: %s\n", err.Error())
+				// the inputs are read again together with the next change
+				for _, file := range changedFiles {
+					changed[file] = true
+				}
+				continue
+			}
+			current, err := o.renderToFiles(ctx, log, fs)
+			if err != nil {
+				fmt.Printf("render failed: %s\n", err.Error())
+				continue
+			}
+			if err := o.writeFiles(os.Stdout, fs, last, current); err != nil {
+				return err
+			}
+			last = current
+		}
+	}
+}
+
+// reload reads the changed inputs again. The oci client with its cache is kept, and the component resolver
+// is only rebuilt if one of the component descriptors or the resources has changed.
+// The value files do not need to be reloaded, because they are read for every render.
+func (o *RenderOptions) reload(log logr.Logger, fs vfs.FileSystem, changedFiles []string) error {
+	var blueprintChanged, componentDescriptorsChanged, exportTemplatesChanged bool
+	componentDescriptorFiles := append([]string{o.ComponentDescriptorPath, o.ResourcesPath}, o.AdditionalComponentDescriptorPath...)
+	for _, file := range changedFiles {
+		switch {
+		case strings.HasPrefix(file, o.BlueprintPath+string(filepath.Separator)):
+			blueprintChanged = true
+		case file == o.ExportTemplatesPath:
+			exportTemplatesChanged = true
+		case containsString(componentDescriptorFiles, file):
+			componentDescriptorsChanged = true
+		}
+	}
+
+	if blueprintChanged {
+		if err := o.readBlueprint(fs); err != nil {
+			return err
+		}
+		if err := o.Validate(); err != nil {
+			return err
+		}
+	}
+	if exportTemplatesChanged {
+		if err := o.readExportTemplates(fs); err != nil {
+			return err
+		}
+	}
+	if componentDescriptorsChanged {
+		if err := o.readComponentDescriptors(fs); err != nil {
+			return err
+		}
+		if err := o.buildComponentResolver(log, fs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addWatches watches the blueprint directory and the directories of all input files.
+// Directories are watched instead of the files themselves, because many editors replace files on save.
+// It returns the watched paths, i.e. the blueprint directory and all input files.
+func (o *RenderOptions) addWatches(watcher *fsnotify.Watcher) ([]string, error) {
+	watched := []string{o.BlueprintPath}
+	err := filepath.Walk(o.BlueprintPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to watch blueprint directory %s: %w", o.BlueprintPath, err)
+	}
+
+	files := []string{o.ComponentDescriptorPath, o.ResourcesPath, o.ExportTemplatesPath}
+	files = append(files, o.AdditionalComponentDescriptorPath...)
+	files = append(files, o.ValueFiles...)
+	for _, file := range files {
+		if len(file) == 0 {
+			continue
+		}
+		absPath, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable get absolute path for %s: %w", file, err)
+		}
+		if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+			return nil, fmt.Errorf("unable to watch %s: %w", file, err)
+		}
+		watched = append(watched, absPath)
+	}
+	return watched, nil
+}
+
+// isWatched returns whether the given path is one of the watched files or inside the blueprint directory.
+func (o *RenderOptions) isWatched(watched []string, path string) bool {
+	path = filepath.Clean(path)
+	if strings.HasPrefix(path, o.BlueprintPath+string(filepath.Separator)) {
+		return true
+	}
+	for _, w := range watched {
+		if path == w {
+			return true
+		}
+	}
+	return false
+}
+
+// renderToFiles renders the blueprint and returns the files that would be written to the output directory.
+func (o *RenderOptions) renderToFiles(ctx context.Context, log logr.Logger, fs vfs.FileSystem) (renderedFiles, error) {
+	o.silent = true
+	result, err := o.Render(ctx, log, fs)
+	if err != nil {
+		return nil, err
+	}
+	// the reconcile timestamp changes with every render and would show up in every diff
+	for _, inst := range result.Installations {
+		for _, di := range inst.DeployItems {
+			delete(di.Annotations, lsv1alpha1.ReconcileTimestampAnnotation)
+		}
+	}
+
+	memOpts := *o
+	memOpts.OutDir = "/"
+	memFs := memoryfs.New()
+	if err := memOpts.write(memFs, result); err != nil {
+		return nil, err
+	}
+
+	files := renderedFiles{}
+	err = vfs.Walk(memFs, "/", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := vfs.ReadFile(memFs, path)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(path, "/")] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// writeFiles prints the changes between the last and the current render to w.
+// If an output directory is given, the changed files are also updated in the output directory.
+func (o *RenderOptions) writeFiles(w io.Writer, fs vfs.FileSystem, last, current renderedFiles) error {
+	diff := diffFiles(last, current)
+	if len(diff) == 0 {
+		fmt.Fprintln(w, "no changes")
+		return nil
+	}
+	fmt.Fprint(w, diff)
+
+	if len(o.OutDir) == 0 {
+		return nil
+	}
+	for name := range last {
+		if _, ok := current[name]; !ok {
+			if err := fs.Remove(filepath.Join(o.OutDir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	for name, data := range current {
+		filePath := filepath.Join(o.OutDir, filepath.FromSlash(name))
+		if err := fs.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			return fmt.Errorf("unable to create path %s", filepath.Dir(filePath))
+		}
+		if err := vfs.WriteFile(fs, filePath, data, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffFiles returns a unified diff of all files that have been added, removed or changed.
+func diffFiles(last, current renderedFiles) string {
+	names := map[string]bool{}
+	for name := range last {
+		names[name] = true
+	}
+	for name := range current {
+		names[name] = true
+	}
+
+	var sb strings.Builder
+	for _, name := range sortedKeys(names) {
+		oldData, oldOk := last[name]
+		newData, newOk := current[name]
+		if oldOk && newOk && string(oldData) == string(newData) {
+			continue
+		}
+		fromFile, toFile := name, name
+		if !oldOk {
+			fromFile = "/dev/null"
+		}
+		if !newOk {
+			toFile = "/dev/null"
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(oldData),
+			B:        splitLines(newData),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			// the diff is only written to a string builder and cannot fail
+			continue
+		}
+		sb.WriteString(diff)
+	}
+	return sb.String()
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(data))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/cmd/blueprints"
+)
+
+func TestRenderCommandWithWatch(t *testing.T) {
+	a := assert.New(t)
+	fail := failFunc(a)
+
+	tmpDir, err := ioutil.TempDir("", "render-watch")
+	fail(a.NoError(err))
+	defer os.RemoveAll(tmpDir)
+
+	blueprintDir := filepath.Join(tmpDir, "blueprint")
+	fail(a.NoError(os.MkdirAll(blueprintDir, os.ModePerm)))
+	fail(a.NoError(copyFile("./testdata/00-render/blueprint/blueprint.yaml", filepath.Join(blueprintDir, "blueprint.yaml"))))
+	importsPath := filepath.Join(tmpDir, "imports.yaml")
+	fail(a.NoError(copyFile("./testdata/00-render/imports.yaml", importsPath)))
+
+	renderOpts := &blueprints.RenderOptions{
+		ValueFiles:   []string{importsPath},
+		OutDir:       filepath.Join(tmpDir, "out"),
+		OutputFormat: blueprints.YAMLOut,
+		Watch:        true,
+	}
+	args := []string{blueprintDir}
+	fail(a.NoError(renderOpts.Complete(logr.Discard(), args, osfs.New())))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- renderOpts.RunWatch(ctx, logr.Discard(), osfs.New())
+	}()
+
+	deployItemPath := filepath.Join(renderOpts.OutDir, blueprints.RootInstallationName, blueprints.DeployItemOutputDir, "first-di.yaml")
+	fail(a.True(waitForFileContent(deployItemPath, "first-value"), "expect the initial render"))
+
+	imports, err := ioutil.ReadFile(importsPath)
+	fail(a.NoError(err))
+	fail(a.NoError(ioutil.WriteFile(importsPath, []byte(strings.ReplaceAll(string(imports), "first-value", "second-value")), os.ModePerm)))
+	a.True(waitForFileContent(deployItemPath, "second-value"), "expect a render after the imports changed")
+
+	blueprintPath := filepath.Join(blueprintDir, "blueprint.yaml")
+	blueprint, err := ioutil.ReadFile(blueprintPath)
+	fail(a.NoError(err))
+	fail(a.NoError(ioutil.WriteFile(blueprintPath, []byte(strings.ReplaceAll(string(blueprint), "type: mock", "type: other-mock")), os.ModePerm)))
+	a.True(waitForFileContent(deployItemPath, "type: other-mock"), "expect a render after the blueprint changed")
+
+	cancel()
+	a.NoError(<-done)
+}
+
+func waitForFileContent(path, content string) bool {
+	for i := 0; i < 100; i++ {
+		data, err := ioutil.ReadFile(path)
+		if err == nil && strings.Contains(string(data), content) {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, data, os.ModePerm)
+}
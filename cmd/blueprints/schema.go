@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/schemas"
+)
+
+// NewSchemaCommand creates a new command to work with the json schemas of a blueprint.
+func NewSchemaCommand(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "command to work with the json schemas of a blueprint",
+	}
+
+	cmd.AddCommand(NewSchemaBundleCommand(ctx))
+
+	return cmd
+}
+
+// SchemaBundleOptions describes the options for the schema bundle command.
+type SchemaBundleOptions struct {
+	RenderOptions
+
+	// OutputPath is the file the bundled schema is written to.
+	OutputPath string
+	// Exports defines whether the schemas of the exports are added to the bundle.
+	Exports bool
+	// Title is the title of the bundled schema.
+	Title string
+}
+
+// NewSchemaBundleCommand creates a new command to bundle the json schemas of a blueprint.
+func NewSchemaBundleCommand(ctx context.Context) *cobra.Command {
+	opts := &SchemaBundleOptions{}
+	cmd := &cobra.Command{
+		Use:  "bundle",
+		Args: cobra.ExactArgs(1),
+		Example: `landscaper-cli blueprints schema bundle BLUEPRINT_DIR -w values.schema.json
+landscaper-cli blueprints schema bundle BLUEPRINT_DIR -c component-descriptor.yaml --exports`,
+		Short: "bundles the json schemas of the imports of a blueprint into a single json schema",
+		Long: `
+Bundles the JSON schemas of the imports of a blueprint into a single self-contained JSON schema (draft-07).
+The bundled schema describes an import values file as used by the render command,
+i.e. all imports are properties of the "imports" object.
+It can be used by IDEs and external validators to author values files.
+The schema of the imports can be referenced with "#/properties/imports".
+
+All references to local types, files of the blueprint and resources of the component descriptor are
+added to the "definitions" of the bundled schema and the references are rewritten accordingly.
+References to the component descriptor can only be resolved if a component descriptor is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(logger.Log, args, osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.Run(osfs.New()); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
+func (o *SchemaBundleOptions) AddFlags(fs *pflag.FlagSet) {
+	o.addInputFlags(fs)
+	fs.StringVarP(&o.OutputPath, "write", "w", "", "The file the bundled schema is written to. By default, it is printed to stdout.")
+	fs.BoolVar(&o.Exports, "exports", false, "Adds the schemas of the exports as \"exports\" property to the bundled schema.")
+	fs.StringVar(&o.Title, "title", "", "The title of the bundled schema. Defaults to a title with the name of the blueprint directory.")
+}
+
+func (o *SchemaBundleOptions) Complete(log logr.Logger, args []string, fs vfs.FileSystem) error {
+	o.RenderOptions.OutputFormat = YAMLOut
+	o.RenderOptions.silent = true
+	if err := o.RenderOptions.Complete(log, args, fs); err != nil {
+		return err
+	}
+	if len(o.Title) == 0 {
+		o.Title = fmt.Sprintf("Imports of blueprint %s", filepath.Base(o.BlueprintPath))
+	}
+	return nil
+}
+
+// Run bundles the schemas and prints them or writes them to the output file.
+func (o *SchemaBundleOptions) Run(fs vfs.FileSystem) error {
+	bundler := &schemas.Bundler{
+		Fs:                  o.blueprintFs,
+		ComponentDescriptor: o.componentDescriptor,
+		ComponentResolver:   o.componentResolver,
+		Exports:             o.Exports,
+	}
+	bundle, err := bundler.Bundle(o.Title)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal bundled schema: %w", err)
+	}
+	data = append(data, '\n')
+	if len(o.OutputPath) == 0 {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := vfs.WriteFile(fs, o.OutputPath, data, 0644); err != nil {
+		return fmt.Errorf("unable to write bundled schema to %s: %w", o.OutputPath, err)
+	}
+	fmt.Printf("Bundled schema written to %s\n", o.OutputPath)
+	return nil
+}
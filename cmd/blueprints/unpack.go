@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package blueprints
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/pkg/artifacts"
+)
+
+type unpackOptions struct {
+	// archivePath is the path of the archive that has been written by the package command.
+	archivePath string
+	// outputPath is the directory the blueprint is extracted to.
+	outputPath string
+}
+
+// NewUnpackCommand creates a new blueprint command to extract blueprints from a local archive.
+func NewUnpackCommand(ctx context.Context) *cobra.Command {
+	opts := &unpackOptions{}
+	cmd := &cobra.Command{
+		Use:     "unpack ARCHIVE",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli blueprints unpack blueprint.tar.gz -o path/to/blueprint/directory",
+		Short:   "extracts a blueprint from an archive",
+		Long: "The unpack command extracts the files of a Blueprint from an archive that has been written by the package command. " +
+			"The digests of all blobs are verified. The output directory must not exist or must be empty.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.Complete(args); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			artifact, err := opts.run(ctx, osfs.New())
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			fmt.Printf("Blueprint %s unpacked to %s\n", artifact.ManifestDescriptor().Digest, opts.outputPath)
+		},
+	}
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *unpackOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.outputPath, "output", "o", "blueprint", "Directory the blueprint is extracted to")
+}
+
+func (o *unpackOptions) Complete(args []string) error {
+	o.archivePath = args[0]
+	return nil
+}
+
+func (o *unpackOptions) run(ctx context.Context, fs vfs.FileSystem) (*artifacts.BlueprintArtifact, error) {
+	if entries, err := vfs.ReadDir(fs, o.outputPath); err == nil && len(entries) != 0 {
+		return nil, fmt.Errorf("the output directory %s is not empty", o.outputPath)
+	}
+
+	file, err := fs.Open(o.archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open archive %s: %w", o.archivePath, err)
+	}
+	defer file.Close()
+
+	artifact, err := artifacts.ReadArchive(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.MkdirAll(o.outputPath, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create output directory %s: %w", o.outputPath, err)
+	}
+	if err := artifact.ExtractStrict(ctx, fs, o.outputPath); err != nil {
+		return nil, fmt.Errorf("unable to extract blueprint: %w", err)
+	}
+	return artifact, nil
+}
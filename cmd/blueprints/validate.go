@@ -7,22 +7,42 @@ package blueprints
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path/filepath"
 
+	ociclientopts "github.com/gardener/component-cli/ociclient/options"
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/codec"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	componentsregistry "github.com/gardener/landscaper/pkg/landscaper/registry/components"
+	"github.com/go-logr/logr"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
 	"github.com/spf13/cobra"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"github.com/spf13/pflag"
 
-	"github.com/gardener/landscaper/apis/core"
-	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
-	"github.com/gardener/landscaper/apis/core/validation"
-	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscapercli/pkg/components"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/resolver"
+	"github.com/gardener/landscapercli/pkg/validation"
 )
 
 type validationOptions struct {
 	// blueprintPath is the path to the directory containing the definition.
 	blueprintPath string
+	// componentDescriptorPath is the path to the optional component descriptor of the blueprint.
+	componentDescriptorPath string
+	// resourcesPath is the path to the optional resources yaml file.
+	resourcesPath string
+	// output is the format of the findings.
+	output string
+
+	ociOptions ociclientopts.Options
+
+	componentDescriptor *cdv2.ComponentDescriptor
+	componentResolver   ctf.ComponentResolver
 }
 
 // NewValidationCommand creates a new blueprint command to validate blueprints.
@@ -34,43 +54,153 @@ func NewValidationCommand(_ context.Context) *cobra.Command {
 		Example: "landscaper-cli blueprints validate path/to/blueprint/directory",
 		Short:   "validates a local blueprint filesystem",
 		Long: "The validate command validates a Blueprint in a local directory. " +
-			"The blueprint directory must contain a file with name blueprint.yaml.",
+			"The blueprint directory must contain a file with name blueprint.yaml. " +
+			"Besides the blueprint api validation, it is checked that all files that are referenced by executions and subinstallations exist, " +
+			"that the schemas of imports, exports and local types are valid json schemas " +
+			"and that the go templates of all executions can be parsed. " +
+			"All problems are reported with their location in the blueprint directory.\n\n" +
+			"References to a component descriptor (cd://) can only be resolved if the component descriptor of the blueprint is given. " +
+			"Otherwise they are reported as warnings.\n\n" +
+			"With --output json or --output sarif, the findings are printed as json report or SARIF log " +
+			"that can be used to annotate pull requests.",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := opts.Complete(args); err != nil {
-				fmt.Println(err.Error())
+			if err := opts.Complete(logger.Log, args, osfs.New()); err != nil {
+				validation.PrintError(os.Stdout, opts.output, opts.blueprintPath, err, validation.RuleInvalidArgument)
 				os.Exit(1)
 			}
 
-			if err := opts.run(); err != nil {
-				fmt.Println(err.Error())
+			findings, err := opts.run(osfs.New())
+			if err != nil {
+				validation.PrintError(os.Stdout, opts.output, opts.blueprintPath, err, validation.RuleBlueprintDecode)
 				os.Exit(1)
 			}
 
-			fmt.Printf("Blueprint validated without errors\n")
+			if err := opts.printFindings(os.Stdout, findings); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			if findings.HasErrors() {
+				os.Exit(1)
+			}
 		},
 	}
 
+	opts.AddFlags(cmd.Flags())
 	return cmd
 }
 
-func (o *validationOptions) run() error {
-	data, err := ioutil.ReadFile(filepath.Join(o.blueprintPath, lsv1alpha1.BlueprintFileName))
+func (o *validationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.componentDescriptorPath, "component-descriptor", "c", "", "Path to the local component descriptor that is used to resolve component descriptor references")
+	fs.StringVarP(&o.resourcesPath, "resources", "r", "", "Path to the resources yaml file")
+	fs.StringVarP(&o.output, "output", "o", validation.TextOutput, validation.OutputFlagUsage)
+	o.ociOptions.AddFlags(fs)
+}
+
+func (o *validationOptions) Complete(log logr.Logger, args []string, fs vfs.FileSystem) error {
+	o.blueprintPath = args[0]
+
+	if err := validation.ValidateOutputFormat(o.output); err != nil {
+		return err
+	}
+
+	if len(o.componentDescriptorPath) != 0 {
+		data, err := vfs.ReadFile(fs, o.componentDescriptorPath)
+		if err != nil {
+			return fmt.Errorf("unable to read component descriptor from %s: %w", o.componentDescriptorPath, err)
+		}
+		cd := &cdv2.ComponentDescriptor{}
+		if err := codec.Decode(data, cd); err != nil {
+			return fmt.Errorf("unable to decode component descriptor: %w", err)
+		}
+		o.componentDescriptor = cd
+	}
+
+	if len(o.resourcesPath) != 0 {
+		if o.componentDescriptor == nil {
+			return fmt.Errorf("if you specify a resources yaml file (option -r) you must also specify a component descriptor (option -c)")
+		}
+
+		absResourcesPath, err := filepath.Abs(o.resourcesPath)
+		if err != nil {
+			return fmt.Errorf("unable get absolute resources path for %s: %w", o.resourcesPath, err)
+		}
+		o.resourcesPath = absResourcesPath
+
+		res, err := components.NewResourceReader(o.resourcesPath).Read()
+		if err != nil {
+			return fmt.Errorf("unable to read resources from file %s: %w", o.resourcesPath, err)
+		}
+		o.componentDescriptor, err = resolver.AddLocalResourcesForRender(o.componentDescriptor, res)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.componentDescriptor == nil {
+		return nil
+	}
+
+	ociClient, _, err := o.ociOptions.Build(log, fs)
 	if err != nil {
 		return err
 	}
-	blueprint := &core.Blueprint{}
-	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+	o.componentResolver, err = componentsregistry.NewOCIRegistryWithOCIClient(log, ociClient, o.componentDescriptor)
+	if err != nil {
 		return err
 	}
+	o.componentResolver = resolver.NewRenderComponentResolver(o.componentResolver, o.componentDescriptor, &cdv2.ComponentDescriptorList{}, o.resourcesPath, fs)
+	return nil
+}
 
-	if errList := validation.ValidateBlueprint(blueprint); len(errList) != 0 {
-		return errList.ToAggregate()
+func (o *validationOptions) run(fs vfs.FileSystem) (validation.Findings, error) {
+	blueprintFs, err := projectionfs.New(fs, o.blueprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct blueprint filesystem: %w", err)
+	}
+	validator := &validation.BlueprintValidator{
+		Fs:                  blueprintFs,
+		ComponentDescriptor: o.componentDescriptor,
+		ComponentResolver:   o.componentResolver,
 	}
+	return validator.Validate()
+}
 
-	return nil
+// validateBlueprint runs the checks of the validate command without a component descriptor.
+func validateBlueprint(fs vfs.FileSystem, blueprintPath string) (validation.Findings, error) {
+	return (&validationOptions{blueprintPath: blueprintPath}).run(fs)
 }
 
-func (o *validationOptions) Complete(args []string) error {
-	o.blueprintPath = args[0]
+// validateBlueprintForUpload validates the blueprint before it is pushed or packaged.
+// Unless strict is set, only findings of the landscaper api validation are errors and all other findings are warnings.
+func validateBlueprintForUpload(fs vfs.FileSystem, blueprintPath string, strict bool) (validation.Findings, error) {
+	findings, err := validateBlueprint(fs, blueprintPath)
+	if err != nil {
+		return nil, err
+	}
+	if !strict {
+		findings = findings.AsWarnings(validation.RuleBlueprintDecode, validation.RuleBlueprintSpec)
+	}
+	return findings, nil
+}
+
+// printFindings prints the findings in the output format.
+// The text format is followed by a summary.
+func (o *validationOptions) printFindings(w io.Writer, findings validation.Findings) error {
+	if err := validation.NewReport(o.blueprintPath, findings).Write(w, o.output); err != nil {
+		return err
+	}
+	if o.output != validation.TextOutput {
+		return nil
+	}
+
+	switch {
+	case findings.HasErrors():
+		fmt.Fprintf(w, "Blueprint validation failed with %d error(s) and %d warning(s)\n",
+			findings.Count(validation.SeverityError), findings.Count(validation.SeverityWarning))
+	case len(findings) != 0:
+		fmt.Fprintf(w, "Blueprint validated without errors but with %d warning(s)\n", len(findings))
+	default:
+		fmt.Fprintf(w, "Blueprint validated without errors\n")
+	}
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/gardener/landscapercli/pkg/blueprints"
 	"github.com/gardener/landscapercli/pkg/logger"
 	"github.com/gardener/landscapercli/pkg/util"
+	"github.com/gardener/landscapercli/pkg/validation"
 )
 
 const ociImage = "ociImage"
@@ -80,6 +81,9 @@ type addContainerDeployItemOptions struct {
 	clusterParam string
 
 	addComponentData bool
+
+	// output is the format of validation errors
+	output string
 }
 
 func NewAddContainerDeployItemCommand(ctx context.Context) *cobra.Command {
@@ -92,20 +96,26 @@ func NewAddContainerDeployItemCommand(ctx context.Context) *cobra.Command {
 
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := opts.Complete(args); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleInvalidArgument)
 				os.Exit(1)
 			}
 
 			if err := opts.run(ctx, logger.Log); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleExecution)
+				os.Exit(1)
+			}
+
+			out, err := validation.WriteReport(os.Stdout, os.Stderr, opts.output, validation.NewReport("", nil))
+			if err != nil {
+				fmt.Fprintln(out, err.Error())
 				os.Exit(1)
 			}
 
-			fmt.Printf("Deploy item added")
-			fmt.Printf("  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
-			fmt.Printf("  \n- file reference to deploy item definition added to blueprint")
-			fmt.Printf("  \n- import and export definitions added to blueprint")
-			fmt.Printf("  \n- reference to image added to resources.yaml")
+			fmt.Fprintf(out, "Deploy item added")
+			fmt.Fprintf(out, "  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
+			fmt.Fprintf(out, "  \n- file reference to deploy item definition added to blueprint")
+			fmt.Fprintf(out, "  \n- import and export definitions added to blueprint")
+			fmt.Fprintf(out, "  \n- reference to image added to resources.yaml")
 		},
 	}
 
@@ -117,6 +127,10 @@ func NewAddContainerDeployItemCommand(ctx context.Context) *cobra.Command {
 func (o *addContainerDeployItemOptions) Complete(args []string) error {
 	o.deployItemName = args[0]
 
+	if err := validation.ValidateOutputFormat(o.output); err != nil {
+		return err
+	}
+
 	if err := o.parseParameterDefinitions(); err != nil {
 		return err
 	}
@@ -176,6 +190,11 @@ func (o *addContainerDeployItemOptions) AddFlags(fs *pflag.FlagSet) {
 		"add-component-data",
 		false,
 		"provide component descriptor and blueprint to container")
+	fs.StringVarP(&o.output,
+		"output",
+		"o",
+		validation.TextOutput,
+		validation.OutputFlagUsage)
 }
 
 func (o *addContainerDeployItemOptions) parseParameterDefinitions() (err error) {
@@ -196,17 +215,17 @@ func (o *addContainerDeployItemOptions) parseParameterDefinitions() (err error)
 
 func (o *addContainerDeployItemOptions) validate() error {
 	if !identityKeyValidationRegexp.Match([]byte(o.deployItemName)) {
-		return fmt.Errorf("the deploy item name must consist of lower case alphanumeric characters, '-', '_' " +
+		return validation.Errorf(validation.RuleDeployItemName, "the deploy item name must consist of lower case alphanumeric characters, '-', '_' "+
 			"or '+', and must start and end with an alphanumeric character")
 	}
 
 	if o.resourceVersion == "" {
-		return fmt.Errorf("resource-version is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "resource-version is missing")
 	}
 
 	_, err := semver.NewVersion(o.resourceVersion)
 	if err != nil {
-		return fmt.Errorf("resource-version %s is not semver compatible", o.resourceVersion)
+		return validation.Errorf(validation.RuleInvalidArgument, "resource-version %s is not semver compatible", o.resourceVersion)
 	}
 
 	err = o.checkIfDeployItemNotAlreadyAdded()
@@ -215,7 +234,7 @@ func (o *addContainerDeployItemOptions) validate() error {
 	}
 
 	if o.image == "" {
-		return fmt.Errorf("image is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "image is missing")
 	}
 
 	return nil
@@ -309,7 +328,8 @@ func (o *addContainerDeployItemOptions) checkIfDeployItemNotAlreadyAdded() error
 		return err
 	}
 
-	return fmt.Errorf("Deploy item was already added. The corresponding deploy execution file %s already exists\n",
+	return validation.FileErrorf(validation.RuleDeployItemExists, util.ExecutionFilePath(o.componentPath, o.deployItemName),
+		"Deploy item was already added. The corresponding deploy execution file %s already exists\n",
 		util.ExecutionFilePath(o.componentPath, o.deployItemName))
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/gardener/landscapercli/pkg/components"
 	"github.com/gardener/landscapercli/pkg/logger"
 	"github.com/gardener/landscapercli/pkg/util"
+	"github.com/gardener/landscapercli/pkg/validation"
 )
 
 const addHelmLSDeployItemUse = `deployitem \
@@ -69,6 +70,9 @@ type addHelmLsDeployItemOptions struct {
 
 	clusterParam  string
 	targetNsParam string
+
+	// output is the format of validation errors
+	output string
 }
 
 // NewCreateCommand creates a new blueprint command to create a blueprint
@@ -82,20 +86,26 @@ func NewAddHelmLSDeployItemCommand(ctx context.Context) *cobra.Command {
 
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := opts.Complete(args); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleInvalidArgument)
 				os.Exit(1)
 			}
 
 			if err := opts.run(ctx, logger.Log); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleExecution)
+				os.Exit(1)
+			}
+
+			out, err := validation.WriteReport(os.Stdout, os.Stderr, opts.output, validation.NewReport("", nil))
+			if err != nil {
+				fmt.Fprintln(out, err.Error())
 				os.Exit(1)
 			}
 
-			fmt.Printf("Deploy item added")
-			fmt.Printf("  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
-			fmt.Printf("  \n- file reference to deploy item definition added to blueprint")
-			fmt.Printf("  \n- import definitions added to blueprint")
-			fmt.Printf("  \n- helm chart resource added to resources.yaml")
+			fmt.Fprintf(out, "Deploy item added")
+			fmt.Fprintf(out, "  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
+			fmt.Fprintf(out, "  \n- file reference to deploy item definition added to blueprint")
+			fmt.Fprintf(out, "  \n- import definitions added to blueprint")
+			fmt.Fprintf(out, "  \n- helm chart resource added to resources.yaml")
 		},
 	}
 
@@ -107,6 +117,10 @@ func NewAddHelmLSDeployItemCommand(ctx context.Context) *cobra.Command {
 func (o *addHelmLsDeployItemOptions) Complete(args []string) error {
 	o.deployItemName = args[0]
 
+	if err := validation.ValidateOutputFormat(o.output); err != nil {
+		return err
+	}
+
 	if o.chartDirectoryPath != "" {
 		o.chartDirectoryPath = filepath.Clean(o.chartDirectoryPath)
 	}
@@ -144,49 +158,54 @@ func (o *addHelmLsDeployItemOptions) AddFlags(fs *pflag.FlagSet) {
 		"target-ns-param",
 		"",
 		"target namespace")
+	fs.StringVarP(&o.output,
+		"output",
+		"o",
+		validation.TextOutput,
+		validation.OutputFlagUsage)
 }
 
 func (o *addHelmLsDeployItemOptions) validate() error {
 	if !identityKeyValidationRegexp.Match([]byte(o.deployItemName)) {
-		return fmt.Errorf("the deploy item name must consist of lower case alphanumeric characters, '-', '_' " +
+		return validation.Errorf(validation.RuleDeployItemName, "the deploy item name must consist of lower case alphanumeric characters, '-', '_' "+
 			"or '+', and must start and end with an alphanumeric character")
 	}
 
 	if o.ociReference == "" && o.chartDirectoryPath == "" {
-		return fmt.Errorf("oci-reference and chart-directory not set, exactly one needs to be specified")
+		return validation.Errorf(validation.RuleMissingArgument, "oci-reference and chart-directory not set, exactly one needs to be specified")
 	}
 
 	if o.ociReference != "" && o.chartDirectoryPath != "" {
-		return fmt.Errorf("both oci-reference and chart-directory are set, exactly one needs to be specified")
+		return validation.Errorf(validation.RuleInvalidArgument, "both oci-reference and chart-directory are set, exactly one needs to be specified")
 	}
 
 	if o.resourceVersion == "" {
-		return fmt.Errorf("resource-version is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "resource-version is missing")
 	}
 
 	_, err := semver.NewVersion(o.resourceVersion)
 	if err != nil {
-		return fmt.Errorf("resource-version %s is not semver compatible", o.resourceVersion)
+		return validation.Errorf(validation.RuleInvalidArgument, "resource-version %s is not semver compatible", o.resourceVersion)
 	}
 
 	if o.clusterParam == "" {
-		return fmt.Errorf("cluster-param is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "cluster-param is missing")
 	}
 
 	if o.targetNsParam == "" {
-		return fmt.Errorf("target-ns-param is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "target-ns-param is missing")
 	}
 
 	if o.chartDirectoryPath != "" {
 		fileInfo, err := os.Stat(o.chartDirectoryPath)
 		if err != nil {
 			if os.IsNotExist(err) {
-				return fmt.Errorf("chart-directory does not exist")
+				return validation.FileErrorf(validation.RuleFileReference, o.chartDirectoryPath, "chart-directory does not exist")
 			}
 			return err
 		}
 		if !fileInfo.IsDir() {
-			return fmt.Errorf("chart-directory is not a directory")
+			return validation.FileErrorf(validation.RuleFileReference, o.chartDirectoryPath, "chart-directory is not a directory")
 		}
 	}
 
@@ -259,7 +278,8 @@ func (o *addHelmLsDeployItemOptions) checkIfDeployItemNotAlreadyAdded() error {
 		return err
 	}
 
-	return fmt.Errorf("Deploy item was already added. The corresponding deploy execution file %s already exists\n",
+	return validation.FileErrorf(validation.RuleDeployItemExists, util.ExecutionFilePath(o.componentPath, o.deployItemName),
+		"Deploy item was already added. The corresponding deploy execution file %s already exists\n",
 		util.ExecutionFilePath(o.componentPath, o.deployItemName))
 }
 
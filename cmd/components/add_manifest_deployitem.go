@@ -28,6 +28,7 @@ import (
 	"github.com/gardener/landscapercli/pkg/blueprints"
 	"github.com/gardener/landscapercli/pkg/logger"
 	"github.com/gardener/landscapercli/pkg/util"
+	"github.com/gardener/landscapercli/pkg/validation"
 )
 
 const addManifestDeployItemUse = `deployitem \
@@ -71,6 +72,9 @@ type addManifestDeployItemOptions struct {
 	policy string
 
 	clusterParam string
+
+	// output is the format of validation errors
+	output string
 }
 
 // NewCreateCommand creates a new blueprint command to create a blueprint
@@ -84,19 +88,25 @@ func NewAddManifestDeployItemCommand(ctx context.Context) *cobra.Command {
 
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := opts.Complete(args); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleInvalidArgument)
 				os.Exit(1)
 			}
 
 			if err := opts.run(ctx, logger.Log); err != nil {
-				fmt.Println(err.Error())
+				validation.PrintError(os.Stdout, opts.output, "", err, validation.RuleExecution)
+				os.Exit(1)
+			}
+
+			out, err := validation.WriteReport(os.Stdout, os.Stderr, opts.output, validation.NewReport("", nil))
+			if err != nil {
+				fmt.Fprintln(out, err.Error())
 				os.Exit(1)
 			}
 
-			fmt.Printf("Deploy item added")
-			fmt.Printf("  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
-			fmt.Printf("  \n- file reference to deploy item definition added to blueprint")
-			fmt.Printf("  \n- import definitions added to blueprint")
+			fmt.Fprintf(out, "Deploy item added")
+			fmt.Fprintf(out, "  \n- deploy item definition in blueprint folder in file %s created", util.ExecutionFileName(opts.deployItemName))
+			fmt.Fprintf(out, "  \n- file reference to deploy item definition added to blueprint")
+			fmt.Fprintf(out, "  \n- import definitions added to blueprint")
 		},
 	}
 
@@ -108,6 +118,10 @@ func NewAddManifestDeployItemCommand(ctx context.Context) *cobra.Command {
 func (o *addManifestDeployItemOptions) Complete(args []string) error {
 	o.deployItemName = args[0]
 
+	if err := validation.ValidateOutputFormat(o.output); err != nil {
+		return err
+	}
+
 	if err := o.parseParameterDefinitions(); err != nil {
 		return err
 	}
@@ -140,6 +154,11 @@ func (o *addManifestDeployItemOptions) AddFlags(fs *pflag.FlagSet) {
 		"cluster-param",
 		"targetCluster",
 		"import parameter name for the target resource containing the access data of the target cluster")
+	fs.StringVarP(&o.output,
+		"output",
+		"o",
+		validation.TextOutput,
+		validation.OutputFlagUsage)
 }
 
 func (o *addManifestDeployItemOptions) parseParameterDefinitions() (err error) {
@@ -160,28 +179,28 @@ func (o *addManifestDeployItemOptions) parseParameterDefinitions() (err error) {
 
 func (o *addManifestDeployItemOptions) validate() error {
 	if !identityKeyValidationRegexp.Match([]byte(o.deployItemName)) {
-		return fmt.Errorf("the deploy item name must consist of lower case alphanumeric characters, '-', '_' " +
+		return validation.Errorf(validation.RuleDeployItemName, "the deploy item name must consist of lower case alphanumeric characters, '-', '_' "+
 			"or '+', and must start and end with an alphanumeric character")
 	}
 
 	if o.clusterParam == "" {
-		return fmt.Errorf("cluster-param is missing")
+		return validation.Errorf(validation.RuleMissingArgument, "cluster-param is missing")
 	}
 
 	if o.files == nil || len(*(o.files)) == 0 {
-		return fmt.Errorf("no manifest files specified")
+		return validation.Errorf(validation.RuleMissingArgument, "no manifest files specified")
 	}
 
 	for _, path := range *(o.files) {
 		fileInfo, err := os.Stat(path)
 		if err != nil {
 			if os.IsNotExist(err) {
-				return fmt.Errorf("manifest file %s does not exist", path)
+				return validation.FileErrorf(validation.RuleFileReference, path, "manifest file %s does not exist", path)
 			}
 			return err
 		}
 		if fileInfo.IsDir() {
-			return fmt.Errorf("manifest file %s is a directory", path)
+			return validation.FileErrorf(validation.RuleFileReference, path, "manifest file %s is a directory", path)
 		}
 	}
 
@@ -227,7 +246,8 @@ func (o *addManifestDeployItemOptions) checkIfDeployItemNotAlreadyAdded() error
 		return err
 	}
 
-	return fmt.Errorf("Deploy item was already added. The corresponding deploy execution file %s already exists\n",
+	return validation.FileErrorf(validation.RuleDeployItemExists, util.ExecutionFilePath(o.componentPath, o.deployItemName),
+		"Deploy item was already added. The corresponding deploy execution file %s already exists\n",
 		util.ExecutionFilePath(o.componentPath, o.deployItemName))
 }
 
@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+type applyOptions struct {
+	kubeconfig string
+	namespace  string
+
+	// installationPath is the path to the installation.yaml
+	installationPath string
+	// targetsPath is the path to a file or directory with the targets of the installation
+	targetsPath string
+	// dataObjectsPath is the path to a file or directory with the data objects of the installation
+	dataObjectsPath string
+
+	wait    bool
+	timeout time.Duration
+}
+
+// NewApplyCommand creates or updates an installation together with its targets and data objects in the cluster
+func NewApplyCommand(ctx context.Context) *cobra.Command {
+	opts := &applyOptions{}
+	cmd := &cobra.Command{
+		Use:  "apply -f installation.yaml [--targets dir] [--data-objects dir] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args: cobra.NoArgs,
+		Example: `landscaper-cli installations apply -f installation.yaml
+landscaper-cli installations apply -f installation.yaml --targets ./targets --data-objects ./dataobjects --wait`,
+		Short: "Creates or updates an installation and the targets and data objects it imports in the cluster. " +
+			"Optionally, waits until the installation has succeeded.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.validateArgs(); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *applyOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	installations, err := readObjects(o.installationPath, &lsv1alpha1.Installation{})
+	if err != nil {
+		return fmt.Errorf("cannot read installation: %w", err)
+	}
+	if len(installations) != 1 {
+		return fmt.Errorf("expected exactly one installation in %s but found %d", o.installationPath, len(installations))
+	}
+	targets, err := readObjects(o.targetsPath, &lsv1alpha1.Target{})
+	if err != nil {
+		return fmt.Errorf("cannot read targets: %w", err)
+	}
+	dataObjects, err := readObjects(o.dataObjectsPath, &lsv1alpha1.DataObject{})
+	if err != nil {
+		return fmt.Errorf("cannot read data objects: %w", err)
+	}
+
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if o.namespace == "" {
+		o.namespace = installations[0].GetNamespace()
+	}
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+	if installationNamespace := installations[0].GetNamespace(); installationNamespace != "" && installationNamespace != o.namespace {
+		return fmt.Errorf("the namespace %s of the installation does not match the namespace %s given with --namespace", installationNamespace, o.namespace)
+	}
+
+	// the imported data objects and targets must exist before the installation is reconciled
+	objects := append(append(dataObjects, targets...), installations...)
+	if err := applyObjects(ctx, cmd, k8sClient, o.namespace, objects); err != nil {
+		return err
+	}
+
+	if !o.wait {
+		return nil
+	}
+	key := client.ObjectKeyFromObject(installations[0])
+	if err := waitForInstallation(ctx, cmd, k8sClient, key, WAIT_FOR_SUCCEEDED, o.timeout); err != nil {
+		return err
+	}
+	cmd.Printf("Installation %s succeeded\n", key.Name)
+	return nil
+}
+
+// applyObjects creates the objects or updates them if they already exist.
+// Objects without a namespace are created in the given namespace. Objects in another namespace are rejected,
+// because landscaper resolves the imports of an installation in its own namespace.
+func applyObjects(ctx context.Context, cmd *cobra.Command, k8sClient client.Client, namespace string, objects []client.Object) error {
+	for _, obj := range objects {
+		if obj.GetNamespace() != "" && obj.GetNamespace() != namespace {
+			return fmt.Errorf("the namespace of %s %s does not match the namespace %s", kindOf(obj), obj.GetName(), namespace)
+		}
+	}
+
+	for _, obj := range objects {
+		obj.SetNamespace(namespace)
+		kind := kindOf(obj)
+
+		existing := newObjectOf(obj)
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("cannot get %s %s: %w", kind, obj.GetName(), err)
+			}
+			if err := k8sClient.Create(ctx, obj); err != nil {
+				return fmt.Errorf("cannot create %s %s: %w", kind, obj.GetName(), err)
+			}
+			cmd.Printf("- created %s %s/%s\n", kind, obj.GetNamespace(), obj.GetName())
+			continue
+		}
+
+		merged, err := mergeObject(existing, obj)
+		if err != nil {
+			return fmt.Errorf("cannot merge %s %s: %w", kind, obj.GetName(), err)
+		}
+		if err := k8sClient.Patch(ctx, merged, client.MergeFrom(existing)); err != nil {
+			return fmt.Errorf("cannot update %s %s: %w", kind, obj.GetName(), err)
+		}
+		cmd.Printf("- updated %s %s/%s\n", kind, obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
+// mergeObject returns the existing object with the content of the given object, e.g. its spec.
+// The finalizers, labels and annotations that landscaper has added to the existing object are kept,
+// and the labels and annotations of the given object are added. The status is not changed.
+func mergeObject(existing, obj client.Object) (client.Object, error) {
+	existingContent, err := toContent(existing)
+	if err != nil {
+		return nil, err
+	}
+	content, err := toContent(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range existingContent {
+		if _, ok := content[key]; !ok && key != "metadata" && key != "status" {
+			delete(existingContent, key)
+		}
+	}
+	for key, value := range content {
+		if key != "metadata" && key != "status" {
+			existingContent[key] = value
+		}
+	}
+
+	data, err := json.Marshal(existingContent)
+	if err != nil {
+		return nil, err
+	}
+	merged := newObjectOf(existing)
+	if err := json.Unmarshal(data, merged); err != nil {
+		return nil, err
+	}
+	merged.SetLabels(mergeStringMaps(existing.GetLabels(), obj.GetLabels()))
+	merged.SetAnnotations(mergeStringMaps(existing.GetAnnotations(), obj.GetAnnotations()))
+	return merged, nil
+}
+
+// newObjectOf returns an empty object of the same type as the given object.
+func newObjectOf(obj client.Object) client.Object {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+}
+
+// toContent returns the json representation of an object as map.
+func toContent(obj client.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func mergeStringMaps(existing, additional map[string]string) map[string]string {
+	if len(existing) == 0 && len(additional) == 0 {
+		return existing
+	}
+	merged := map[string]string{}
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range additional {
+		merged[key] = value
+	}
+	return merged
+}
+
+// readObjects reads the objects of the given kind from a yaml file or from all yaml files of a directory.
+// A file may contain multiple yaml documents. An empty path results in no objects.
+func readObjects(path string, kind client.Object) ([]client.Object, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = nil
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	decoder := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDeserializer()
+	objects := []client.Object{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		reader := yamlutil.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+		for {
+			doc, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cannot read %s: %w", file, err)
+			}
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+
+			obj, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode %s: %w", file, err)
+			}
+			clientObj, ok := obj.(client.Object)
+			if !ok || reflect.TypeOf(obj) != reflect.TypeOf(kind) {
+				return nil, fmt.Errorf("expected only objects of kind %s in %s but found %s", kindOf(kind), file, obj.GetObjectKind().GroupVersionKind().Kind)
+			}
+			objects = append(objects, clientObj)
+		}
+	}
+	return objects, nil
+}
+
+// kindOf returns the kind of a landscaper object.
+func kindOf(obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, api.LandscaperScheme)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
+
+func (o *applyOptions) validateArgs() error {
+	if len(o.installationPath) == 0 {
+		return fmt.Errorf("the installation file must be given with --file/-f")
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("the timeout must be greater than zero")
+	}
+	return nil
+}
+
+func (o *applyOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation and of its targets and data objects. Required if --kubeconfig is used and the installation has no namespace.")
+	fs.StringVarP(&o.installationPath, "file", "f", "", "path to the installation.yaml")
+	fs.StringVar(&o.targetsPath, "targets", "", "path to a yaml file or a directory of yaml files with the targets imported by the installation")
+	fs.StringVar(&o.dataObjectsPath, "data-objects", "", "path to a yaml file or a directory of yaml files with the data objects imported by the installation")
+	fs.BoolVar(&o.wait, "wait", false, "wait until the installation and all its sub-installations, executions and deployItems have succeeded")
+	fs.DurationVar(&o.timeout, "timeout", 10*time.Minute, "the maximum time to wait. Only used together with --wait.")
+}
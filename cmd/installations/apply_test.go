@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReadObjects(t *testing.T) {
+	installations, err := readObjects("./testdata/apply/installation.yaml", &lsv1alpha1.Installation{})
+	assert.NoError(t, err)
+	assert.Len(t, installations, 1)
+	assert.Equal(t, "echo-server", installations[0].GetName())
+
+	targets, err := readObjects("./testdata/apply/targets", &lsv1alpha1.Target{})
+	assert.NoError(t, err)
+	assert.Len(t, targets, 1)
+	assert.Equal(t, "my-cluster", targets[0].GetName())
+
+	dataObjects, err := readObjects("./testdata/apply/dataobjects.yaml", &lsv1alpha1.DataObject{})
+	assert.NoError(t, err)
+	assert.Len(t, dataObjects, 2)
+
+	noObjects, err := readObjects("", &lsv1alpha1.Target{})
+	assert.NoError(t, err)
+	assert.Empty(t, noObjects)
+
+	_, err = readObjects("./testdata/apply/dataobjects.yaml", &lsv1alpha1.Target{})
+	assert.EqualError(t, err, "expected only objects of kind Target in ./testdata/apply/dataobjects.yaml but found DataObject")
+}
+
+func TestApplyObjects(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().WithScheme(api.LandscaperScheme).Build()
+	output := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(output)
+
+	dataObjects, err := readObjects("./testdata/apply/dataobjects.yaml", &lsv1alpha1.DataObject{})
+	assert.NoError(t, err)
+	assert.NoError(t, applyObjects(ctx, cmd, k8sClient, "test", dataObjects))
+	assert.Equal(t, "- created DataObject test/my-namespace\n- created DataObject test/my-replicas\n", output.String())
+
+	dataObject := &lsv1alpha1.DataObject{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "my-namespace", Namespace: "test"}, dataObject))
+	assert.Equal(t, `"example"`, string(dataObject.Data.RawMessage))
+
+	output.Reset()
+	dataObjects, err = readObjects("./testdata/apply/dataobjects.yaml", &lsv1alpha1.DataObject{})
+	assert.NoError(t, err)
+	dataObjects[0].(*lsv1alpha1.DataObject).Data = lsv1alpha1.NewAnyJSON([]byte(`"changed"`))
+	assert.NoError(t, applyObjects(ctx, cmd, k8sClient, "test", dataObjects[:1]))
+	assert.Equal(t, "- updated DataObject test/my-namespace\n", output.String())
+
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "my-namespace", Namespace: "test"}, dataObject))
+	assert.Equal(t, `"changed"`, string(dataObject.Data.RawMessage))
+
+	dataObjects, err = readObjects("./testdata/apply/dataobjects.yaml", &lsv1alpha1.DataObject{})
+	assert.NoError(t, err)
+	assert.EqualError(t, applyObjects(ctx, cmd, k8sClient, "other", dataObjects),
+		"the namespace of DataObject my-replicas does not match the namespace other")
+}
+
+func TestApplyObjectsKeepsMetadata(t *testing.T) {
+	ctx := context.Background()
+	output := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(output)
+
+	installations, err := readObjects("./testdata/apply/installation.yaml", &lsv1alpha1.Installation{})
+	assert.NoError(t, err)
+	existing := installations[0].DeepCopyObject().(*lsv1alpha1.Installation)
+	existing.Namespace = "test"
+	existing.Finalizers = []string{"finalizer.landscaper.gardener.cloud"}
+	existing.Labels = map[string]string{"existing": "label"}
+	existing.Annotations = map[string]string{lsv1alpha1.OperationAnnotation: string(lsv1alpha1.ReconcileOperation)}
+	existing.Spec.Imports.Data = nil
+	existing.Status.Phase = lsv1alpha1.ComponentPhaseSucceeded
+	k8sClient := fake.NewClientBuilder().WithScheme(api.LandscaperScheme).WithObjects(existing).Build()
+
+	installations[0].SetLabels(map[string]string{"new": "label"})
+	assert.NoError(t, applyObjects(ctx, cmd, k8sClient, "test", installations))
+	assert.Equal(t, "- updated Installation test/echo-server\n", output.String())
+
+	installation := &lsv1alpha1.Installation{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "echo-server", Namespace: "test"}, installation))
+	assert.Equal(t, []string{"finalizer.landscaper.gardener.cloud"}, installation.Finalizers)
+	assert.Equal(t, map[string]string{"existing": "label", "new": "label"}, installation.Labels)
+	assert.Equal(t, string(lsv1alpha1.ReconcileOperation), installation.Annotations[lsv1alpha1.OperationAnnotation])
+	assert.Len(t, installation.Spec.Imports.Data, 1)
+	assert.Equal(t, lsv1alpha1.ComponentPhaseSucceeded, installation.Status.Phase)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/mandelsoft/vfs/pkg/memoryfs"
 	"github.com/mandelsoft/vfs/pkg/osfs"
 	"github.com/mandelsoft/vfs/pkg/vfs"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	yamlv3 "gopkg.in/yaml.v3"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"sigs.k8s.io/yaml"
 
+	"github.com/gardener/landscapercli/pkg/artifacts"
 	"github.com/gardener/landscapercli/pkg/logger"
 	"github.com/gardener/landscapercli/pkg/util"
 )
@@ -52,6 +54,11 @@ type createOpts struct {
 	blueprintResourceName string
 	name                  string
 	renderSchemaInfo      bool
+
+	// verifyDigest verifies the blueprint against the digest of its resource in the component descriptor.
+	verifyDigest bool
+	// verification configures the signature verification of the component descriptor.
+	verification artifacts.VerificationOptions
 }
 
 func NewCreateCommand(ctx context.Context) *cobra.Command {
@@ -100,15 +107,26 @@ func (o *createOpts) run(ctx context.Context, cmd *cobra.Command, log logr.Logge
 		return fmt.Errorf("unable to to fetch component descriptor %s: %w", ociRef, err)
 	}
 
+	if o.verification.VerifySignatureEnabled() {
+		if err := o.verification.VerifySignature(cd); err != nil {
+			return err
+		}
+	}
+
 	blueprintRes, err := util.GetBlueprintResource(cd, o.blueprintResourceName)
 	if err != nil {
 		return err
 	}
 
-	data, err := resolveBlueprint(ctx, *blueprintRes, ociClient, blobResolver)
+	data, manifestData, err := resolveBlueprint(ctx, *blueprintRes, ociClient, blobResolver)
 	if err != nil {
 		return fmt.Errorf("cannot resolve blueprint: %w", err)
 	}
+	if o.verifyDigest || o.verification.VerifySignatureEnabled() {
+		if err := artifacts.VerifyResourceDigest(*blueprintRes, manifestData, data.Bytes()); err != nil {
+			return fmt.Errorf("cannot verify blueprint: %w", err)
+		}
+	}
 
 	memFS := memoryfs.New()
 	if err := tar.ExtractTarGzip(ctx, data, memFS, tar.ToPath("/")); err != nil {
@@ -197,7 +215,7 @@ func (o *createOpts) Complete(args []string) error {
 	if len(o.version) == 0 {
 		return errors.New("a component's Version must be defined")
 	}
-	return nil
+	return o.verification.Validate()
 }
 
 func annotateInstallationWithSchemaComments(installation *lsv1alpha1.Installation, blueprint *lsv1alpha1.Blueprint, referenceResolver *lsjsonschema.ReferenceResolver) (*yamlv3.Node, error) {
@@ -222,29 +240,48 @@ func annotateInstallationWithSchemaComments(installation *lsv1alpha1.Installatio
 	return commentedInstallationYaml, nil
 }
 
-func resolveBlueprint(ctx context.Context, blueprintRes cdv2.Resource, ociClient ociclient.Client, blobResolver ctf.BlobResolver) (*bytes.Buffer, error) {
-	var data bytes.Buffer
+// resolveBlueprint fetches the blueprint blob of the resource.
+// If the blueprint is stored as separate oci artifact, its raw manifest is returned, too.
+func resolveBlueprint(ctx context.Context, blueprintRes cdv2.Resource, ociClient ociclient.Client, blobResolver ctf.BlobResolver) (*bytes.Buffer, []byte, error) {
+	var (
+		data         bytes.Buffer
+		manifestData []byte
+	)
 	if blueprintRes.Access.GetType() == cdv2.OCIRegistryType {
 		ref, ok := blueprintRes.Access.Object["imageReference"].(string)
 		if !ok {
-			return nil, fmt.Errorf("cannot parse imageReference to string")
+			return nil, nil, fmt.Errorf("cannot parse imageReference to string")
 		}
 
-		manifest, err := ociClient.GetManifest(ctx, ref)
+		desc, rawManifest, err := ociClient.GetRawManifest(ctx, ref)
 		if err != nil {
-			return nil, fmt.Errorf("cannot get manifest: %w", err)
+			return nil, nil, fmt.Errorf("cannot get manifest: %w", err)
+		}
+		if err := artifacts.VerifyBlob(desc, rawManifest); err != nil {
+			return nil, nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		manifest := &ocispecv1.Manifest{}
+		if err := json.Unmarshal(rawManifest, manifest); err != nil {
+			return nil, nil, fmt.Errorf("cannot decode manifest: %w", err)
+		}
+		if len(manifest.Layers) == 0 {
+			return nil, nil, fmt.Errorf("the manifest of %s has no layers", ref)
 		}
 
 		if err := ociClient.Fetch(ctx, ref, manifest.Layers[0], &data); err != nil {
-			return nil, fmt.Errorf("cannot get manifest layer: %w", err)
+			return nil, nil, fmt.Errorf("cannot get manifest layer: %w", err)
+		}
+		if err := artifacts.VerifyBlob(manifest.Layers[0], data.Bytes()); err != nil {
+			return nil, nil, fmt.Errorf("invalid manifest layer: %w", err)
 		}
+		manifestData = rawManifest
 	} else {
 		if _, err := blobResolver.Resolve(ctx, blueprintRes, &data); err != nil {
-			return nil, fmt.Errorf("unable to to resolve blob of blueprint resource: %w", err)
+			return nil, nil, fmt.Errorf("unable to to resolve blob of blueprint resource: %w", err)
 		}
 	}
 
-	return &data, nil
+	return &data, manifestData, nil
 }
 
 func addExportSchemaComments(commentedInstallationYaml *yamlv3.Node, blueprint *lsv1alpha1.Blueprint, schemaResolver *lsjsonschema.ReferenceResolver) error {
@@ -350,6 +387,8 @@ func (o *createOpts) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.renderSchemaInfo, "render-schema-info", true, "render schema information of the component's imports and exports as comments into the installation")
 	fs.StringVar(&o.blueprintResourceName, "blueprint-resource-name", "", "name of the blueprint resource in the component descriptor (optional if only one blueprint resource is specified in the component descriptor)")
 	fs.StringVarP(&o.outputPath, "output-file", "o", "", "file path for the resulting installation yaml")
+	fs.BoolVar(&o.verifyDigest, "verify-digest", false, "verify the blueprint against the digest of its resource in the component descriptor (implied by --public-key)")
+	o.verification.AddFlags(fs)
 	o.OciOptions.AddFlags(fs)
 }
 
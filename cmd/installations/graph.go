@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gardener/landscapercli/cmd/installations/graph"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+const (
+	OUTPUT_DOT     = "dot"
+	OUTPUT_MERMAID = "mermaid"
+)
+
+type graphOptions struct {
+	kubeconfig string
+	namespace  string
+
+	outputFormat string
+}
+
+// NewGraphCommand displays the data flow between the installations of a namespace.
+func NewGraphCommand(ctx context.Context) *cobra.Command {
+	opts := &graphOptions{}
+	cmd := &cobra.Command{
+		Use:  "graph [--output dot|mermaid|json] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args: cobra.NoArgs,
+		Example: `landscaper-cli installations graph -n my-namespace | dot -Tsvg > graph.svg
+landscaper-cli installations graph -n my-namespace -o mermaid`,
+		Short: "Displays the data flow graph between all installations of a namespace: which exported data objects and targets are imported by which installations. " +
+			"Imports without existing objects are marked as missing, installations that depend on themselves as cycle " +
+			"and installations with imports whose objects do not exist (yet) as blocked.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.validateArgs(); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *graphOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	builder := graph.Builder{
+		K8sClient: k8sClient,
+	}
+	g, err := builder.Build(o.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot build graph: %w", err)
+	}
+
+	switch o.outputFormat {
+	case OUTPUT_MERMAID:
+		return g.WriteMermaid(cmd.OutOrStdout())
+	case OUTPUT_JSON:
+		return g.WriteJSON(cmd.OutOrStdout())
+	default:
+		return g.WriteDOT(cmd.OutOrStdout())
+	}
+}
+
+func (o *graphOptions) validateArgs() error {
+	switch o.outputFormat {
+	case OUTPUT_DOT, OUTPUT_MERMAID, OUTPUT_JSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %s. Use one of: %s, %s, %s", o.outputFormat, OUTPUT_DOT, OUTPUT_MERMAID, OUTPUT_JSON)
+	}
+}
+
+func (o *graphOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installations. Required if --kubeconfig is used.")
+	fs.StringVarP(&o.outputFormat, "output", "o", OUTPUT_DOT, "output format of the graph: dot, mermaid or json")
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// label returns the label of a node with its kind, context and phase.
+func (n *Node) label() string {
+	lines := []string{fmt.Sprintf("[%s] %s", n.Kind, n.Name)}
+	if n.Kind != InstallationNode && len(n.Context) != 0 {
+		lines = append(lines, fmt.Sprintf("context: %s", n.Context))
+	}
+	if len(n.Phase) != 0 {
+		lines = append(lines, fmt.Sprintf("phase: %s", n.Phase))
+	}
+
+	marks := []string{}
+	if n.Missing {
+		marks = append(marks, "MISSING")
+	}
+	if n.Blocked {
+		marks = append(marks, "BLOCKED")
+	}
+	if n.InCycle {
+		marks = append(marks, "CYCLE")
+	}
+	if len(marks) != 0 {
+		lines = append(lines, strings.Join(marks, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// label returns the label of an edge with the import and the referenced value.
+func (e *Edge) label() string {
+	if e.Import == e.Ref {
+		return e.Import
+	}
+	return fmt.Sprintf("%s: %s", e.Import, e.Ref)
+}
+
+// WriteDOT writes the graph in the DOT language of graphviz.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	b := &strings.Builder{}
+	b.WriteString("digraph installations {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%s", quoteDOT(node.label()))}
+		if node.Kind != InstallationNode {
+			attrs = append(attrs, "shape=ellipse")
+		}
+		switch {
+		case node.Missing:
+			attrs = append(attrs, "color=red", "style=dashed")
+		case node.InCycle:
+			attrs = append(attrs, "color=red")
+		case node.Blocked:
+			attrs = append(attrs, "color=orange")
+		}
+		fmt.Fprintf(b, "  %s [%s];\n", quoteDOT(node.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attrs := []string{fmt.Sprintf("label=%s", quoteDOT(edge.label()))}
+		if edge.InCycle {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(b, "  %s -> %s [%s];\n", quoteDOT(edge.From), quoteDOT(edge.To), strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as mermaid flowchart.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	// node ids of mermaid must not contain special characters
+	ids := map[string]string{}
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	b.WriteString("  classDef missing stroke:red,stroke-dasharray:5 5\n")
+	b.WriteString("  classDef blocked stroke:orange\n")
+	b.WriteString("  classDef cycle stroke:red\n")
+
+	for _, node := range g.Nodes {
+		label := quoteMermaid(node.label())
+		if node.Kind == InstallationNode {
+			fmt.Fprintf(b, "  %s[%s]\n", ids[node.ID], label)
+		} else {
+			fmt.Fprintf(b, "  %s([%s])\n", ids[node.ID], label)
+		}
+		switch {
+		case node.Missing:
+			fmt.Fprintf(b, "  class %s missing\n", ids[node.ID])
+		case node.InCycle:
+			fmt.Fprintf(b, "  class %s cycle\n", ids[node.ID])
+		case node.Blocked:
+			fmt.Fprintf(b, "  class %s blocked\n", ids[node.ID])
+		}
+	}
+
+	cycleEdges := []string{}
+	for i, edge := range g.Edges {
+		fmt.Fprintf(b, "  %s -->|%s| %s\n", ids[edge.From], quoteMermaid(edge.label()), ids[edge.To])
+		if edge.InCycle {
+			cycleEdges = append(cycleEdges, fmt.Sprint(i))
+		}
+	}
+	if len(cycleEdges) != 0 {
+		fmt.Fprintf(b, "  linkStyle %s stroke:red\n", strings.Join(cycleEdges, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the graph as json.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal graph: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func quoteDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func quoteMermaid(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
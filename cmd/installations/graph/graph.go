@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	lsv1alpha1helper "github.com/gardener/landscaper/apis/core/v1alpha1/helper"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+)
+
+// The kinds of the nodes of the graph.
+const (
+	InstallationNode = "installation"
+	DataObjectNode   = "dataObject"
+	TargetNode       = "target"
+	SecretNode       = "secret"
+	ConfigMapNode    = "configMap"
+)
+
+// Node is an installation or an imported object that is not exported by any installation.
+type Node struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Context is the context of the data objects and targets of an installation, or of an imported object.
+	Context string `json:"context,omitempty"`
+	Phase   string `json:"phase,omitempty"`
+	// Missing marks imported objects that do not exist.
+	Missing bool `json:"missing,omitempty"`
+	// Blocked marks installations with imports whose objects do not exist (yet).
+	Blocked bool `json:"blocked,omitempty"`
+	// InCycle marks installations that depend on themselves.
+	InCycle bool `json:"inCycle,omitempty"`
+}
+
+// Edge connects the node that provides a value with the installation that imports it.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Import is the name of the import of the installation.
+	Import string `json:"import"`
+	// Ref is the referenced data object, target, secret or configmap.
+	Ref     string `json:"ref"`
+	InCycle bool   `json:"inCycle,omitempty"`
+}
+
+// Graph is the data flow graph between the installations of a namespace.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+	// Cycles contains the ids of the installations of every cycle.
+	Cycles [][]string `json:"cycles,omitempty"`
+
+	nodes map[string]*Node
+}
+
+// Builder reads the installations, data objects and targets of a namespace using the K8sClient and builds their graph.
+type Builder struct {
+	K8sClient client.Client
+}
+
+// builder contains the state of a single build.
+type builder struct {
+	*Builder
+	graph     *Graph
+	namespace string
+	// producers maps the kind, context and reference of exported values to the ids of their installations.
+	producers map[string][]string
+	// existing contains the kind and name of the existing data objects and targets.
+	existing map[string]bool
+}
+
+func producerKey(kind, ctx, ref string) string {
+	return fmt.Sprintf("%s|%s|%s", kind, ctx, ref)
+}
+
+func nodeID(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// Build builds the data flow graph of all installations in the namespace.
+func (b *Builder) Build(namespace string) (*Graph, error) {
+	installations := lsv1alpha1.InstallationList{}
+	if err := b.K8sClient.List(context.TODO(), &installations, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("cannot list installations for namespace %s: %w", namespace, err)
+	}
+	dataObjects := lsv1alpha1.DataObjectList{}
+	if err := b.K8sClient.List(context.TODO(), &dataObjects, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("cannot list data objects for namespace %s: %w", namespace, err)
+	}
+	targets := lsv1alpha1.TargetList{}
+	if err := b.K8sClient.List(context.TODO(), &targets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("cannot list targets for namespace %s: %w", namespace, err)
+	}
+
+	bld := &builder{
+		Builder:   b,
+		graph:     &Graph{Nodes: []*Node{}, Edges: []*Edge{}, nodes: map[string]*Node{}},
+		namespace: namespace,
+		producers: map[string][]string{},
+		existing:  map[string]bool{},
+	}
+	for _, do := range dataObjects.Items {
+		bld.existing[nodeID(DataObjectNode, namespace, do.Name)] = true
+	}
+	for _, target := range targets.Items {
+		bld.existing[nodeID(TargetNode, namespace, target.Name)] = true
+	}
+
+	sort.Slice(installations.Items, func(i, j int) bool {
+		return installations.Items[i].Name < installations.Items[j].Name
+	})
+	for i := range installations.Items {
+		bld.addInstallation(&installations.Items[i])
+	}
+	for i := range installations.Items {
+		if err := bld.addImports(&installations.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	bld.graph.markCycles()
+	bld.graph.sort()
+	return bld.graph, nil
+}
+
+// addInstallation adds the node of an installation and registers it as producer of its exports.
+// An installation is also the producer of its imports in the context of its sub-installations.
+func (b *builder) addInstallation(inst *lsv1alpha1.Installation) {
+	ctx := inspect.InstallationContext(inst)
+	node := b.graph.addNode(&Node{
+		ID:        nodeID(InstallationNode, inst.Namespace, inst.Name),
+		Kind:      InstallationNode,
+		Name:      inst.Name,
+		Namespace: inst.Namespace,
+		Context:   ctx,
+		Phase:     string(inst.Status.Phase),
+	})
+
+	for _, exp := range inst.Spec.Exports.Data {
+		b.addProducer(DataObjectNode, ctx, exp.DataRef, node.ID)
+	}
+	for _, exp := range inst.Spec.Exports.Targets {
+		b.addProducer(TargetNode, ctx, exp.Target, node.ID)
+	}
+
+	subCtx := lsv1alpha1helper.DataObjectSourceFromInstallationName(inst.Name)
+	for _, imp := range inst.Spec.Imports.Data {
+		b.addProducer(DataObjectNode, subCtx, imp.Name, node.ID)
+	}
+	for name := range inst.Spec.ImportDataMappings {
+		b.addProducer(DataObjectNode, subCtx, name, node.ID)
+	}
+	for _, imp := range inst.Spec.Imports.Targets {
+		b.addProducer(TargetNode, subCtx, imp.Name, node.ID)
+	}
+}
+
+func (b *builder) addProducer(kind, ctx, ref, id string) {
+	key := producerKey(kind, ctx, ref)
+	b.producers[key] = append(b.producers[key], id)
+}
+
+// addImports adds the edges from the producers of the imported values to the installation.
+func (b *builder) addImports(inst *lsv1alpha1.Installation) error {
+	ctx := inspect.InstallationContext(inst)
+	consumer := b.graph.nodes[nodeID(InstallationNode, inst.Namespace, inst.Name)]
+
+	for _, imp := range inst.Spec.Imports.Data {
+		switch {
+		case len(imp.DataRef) != 0:
+			b.addImport(consumer, ctx, DataObjectNode, imp.Name, imp.DataRef)
+		case imp.SecretRef != nil:
+			if err := b.addReferenceImport(consumer, SecretNode, imp.Name, imp.SecretRef.ObjectReference, &corev1.Secret{}); err != nil {
+				return err
+			}
+		case imp.ConfigMapRef != nil:
+			if err := b.addReferenceImport(consumer, ConfigMapNode, imp.Name, imp.ConfigMapRef.ObjectReference, &corev1.ConfigMap{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, imp := range inst.Spec.Imports.Targets {
+		switch {
+		case len(imp.Target) != 0:
+			b.addImport(consumer, ctx, TargetNode, imp.Name, imp.Target)
+		case len(imp.Targets) != 0:
+			for i, target := range imp.Targets {
+				b.addImport(consumer, ctx, TargetNode, fmt.Sprintf("%s[%d]", imp.Name, i), target)
+			}
+		case len(imp.TargetListReference) != 0:
+			// target lists can only be imported from the parent installation
+			for _, producer := range b.producers[producerKey(TargetNode, ctx, imp.TargetListReference)] {
+				b.graph.addEdge(producer, consumer.ID, imp.Name, imp.TargetListReference)
+			}
+		}
+	}
+	return nil
+}
+
+// addImport adds the edges for an imported data object or target.
+// Imports that are not exported by any installation are connected to a node of the imported object.
+func (b *builder) addImport(consumer *Node, ctx, kind, importName, ref string) {
+	objectName := lsv1alpha1helper.GenerateDataObjectName(ctx, ref)
+	exists := b.existing[nodeID(kind, b.namespace, objectName)]
+	if !exists {
+		consumer.Blocked = true
+	}
+
+	producers := b.producers[producerKey(kind, ctx, ref)]
+	if len(producers) == 0 {
+		node := b.graph.addNode(&Node{
+			ID:        nodeID(kind, b.namespace, objectName),
+			Kind:      kind,
+			Name:      ref,
+			Namespace: b.namespace,
+			Context:   ctx,
+			Missing:   !exists,
+		})
+		b.graph.addEdge(node.ID, consumer.ID, importName, ref)
+		return
+	}
+	for _, producer := range producers {
+		b.graph.addEdge(producer, consumer.ID, importName, ref)
+	}
+}
+
+// addReferenceImport adds the edge for an imported secret or configmap.
+func (b *builder) addReferenceImport(consumer *Node, kind, importName string, ref lsv1alpha1.ObjectReference, obj client.Object) error {
+	namespace := ref.Namespace
+	if len(namespace) == 0 {
+		namespace = b.namespace
+	}
+
+	missing := false
+	if err := b.K8sClient.Get(context.TODO(), client.ObjectKey{Name: ref.Name, Namespace: namespace}, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("cannot get %s %s/%s: %w", kind, namespace, ref.Name, err)
+		}
+		missing = true
+		consumer.Blocked = true
+	}
+
+	node := b.graph.addNode(&Node{
+		ID:        nodeID(kind, namespace, ref.Name),
+		Kind:      kind,
+		Name:      ref.Name,
+		Namespace: namespace,
+		Missing:   missing,
+	})
+	b.graph.addEdge(node.ID, consumer.ID, importName, fmt.Sprintf("%s/%s", namespace, ref.Name))
+	return nil
+}
+
+// addNode adds the node unless a node with the same id exists. It returns the node of the graph.
+func (g *Graph) addNode(node *Node) *Node {
+	if existing, ok := g.nodes[node.ID]; ok {
+		return existing
+	}
+	g.nodes[node.ID] = node
+	g.Nodes = append(g.Nodes, node)
+	return node
+}
+
+func (g *Graph) addEdge(from, to, importName, ref string) {
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Import: importName, Ref: ref})
+}
+
+// markCycles finds the strongly connected components of the installations with Tarjan's algorithm
+// and marks the installations and edges of all components with more than one installation or a self-reference.
+func (g *Graph) markCycles() {
+	successors := map[string][]string{}
+	for _, edge := range g.Edges {
+		successors[edge.From] = append(successors[edge.From], edge.To)
+	}
+
+	index := 0
+	indices := map[string]int{}
+	lowLinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	components := [][]string{}
+
+	var connect func(id string)
+	connect = func(id string) {
+		indices[id] = index
+		lowLinks[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, succ := range successors[id] {
+			if _, visited := indices[succ]; !visited {
+				connect(succ)
+				if lowLinks[succ] < lowLinks[id] {
+					lowLinks[id] = lowLinks[succ]
+				}
+			} else if onStack[succ] && indices[succ] < lowLinks[id] {
+				lowLinks[id] = indices[succ]
+			}
+		}
+
+		if lowLinks[id] == indices[id] {
+			component := []string{}
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if _, visited := indices[node.ID]; !visited {
+			connect(node.ID)
+		}
+	}
+
+	componentOf := map[string]int{}
+	for i, component := range components {
+		for _, id := range component {
+			componentOf[id] = i
+		}
+	}
+	inCycle := map[int]bool{}
+	for i, component := range components {
+		if len(component) > 1 {
+			inCycle[i] = true
+		}
+	}
+	for _, edge := range g.Edges {
+		if edge.From == edge.To {
+			inCycle[componentOf[edge.From]] = true
+		}
+	}
+
+	for _, edge := range g.Edges {
+		if componentOf[edge.From] == componentOf[edge.To] && inCycle[componentOf[edge.From]] {
+			edge.InCycle = true
+		}
+	}
+	for i, component := range components {
+		if !inCycle[i] {
+			continue
+		}
+		sort.Strings(component)
+		for _, id := range component {
+			g.nodes[id].InCycle = true
+		}
+		g.Cycles = append(g.Cycles, component)
+	}
+	sort.Slice(g.Cycles, func(i, j int) bool {
+		return g.Cycles[i][0] < g.Cycles[j][0]
+	})
+}
+
+// sort sorts the nodes by their id and the edges by their nodes and imports.
+func (g *Graph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Import < b.Import
+	})
+}
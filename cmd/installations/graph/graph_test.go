@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata")
+	assert.NoError(t, err)
+
+	builder := Builder{
+		K8sClient: fakeClient,
+	}
+	g, err := builder.Build("graphtest")
+	assert.NoError(t, err)
+
+	nodes := map[string]*Node{}
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+
+	t.Run("Nodes", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"configMap/graphtest/network-config",
+			"dataObject/graphtest/oylmih6vlrbrqkyxeaxcorwib2uyotui",
+			"installation/graphtest/app",
+			"installation/graphtest/cycle-a",
+			"installation/graphtest/cycle-b",
+			"installation/graphtest/database",
+			"installation/graphtest/network",
+			"installation/graphtest/network-subnet",
+			"secret/graphtest/app-credentials",
+			"target/graphtest/cluster",
+		}, nodeIDs(g))
+	})
+
+	t.Run("Exports are connected to imports", func(t *testing.T) {
+		assert.Contains(t, g.Edges, &Edge{From: "installation/graphtest/network", To: "installation/graphtest/database", Import: "vpcId", Ref: "vpc-id"})
+		assert.Contains(t, g.Edges, &Edge{From: "installation/graphtest/database", To: "installation/graphtest/app", Import: "dbUrl", Ref: "db-url"})
+		assert.Contains(t, g.Edges, &Edge{From: "target/graphtest/cluster", To: "installation/graphtest/network", Import: "cluster", Ref: "#cluster"})
+		assert.Contains(t, g.Edges, &Edge{From: "configMap/graphtest/network-config", To: "installation/graphtest/network", Import: "region", Ref: "graphtest/network-config"})
+	})
+
+	t.Run("Imports of parents are connected to sub-installations", func(t *testing.T) {
+		assert.Contains(t, g.Edges, &Edge{From: "installation/graphtest/network", To: "installation/graphtest/network-subnet", Import: "region", Ref: "region"})
+		assert.True(t, nodes["installation/graphtest/network-subnet"].Blocked)
+	})
+
+	t.Run("Missing imports", func(t *testing.T) {
+		assert.True(t, nodes["dataObject/graphtest/oylmih6vlrbrqkyxeaxcorwib2uyotui"].Missing)
+		assert.True(t, nodes["secret/graphtest/app-credentials"].Missing)
+		assert.False(t, nodes["target/graphtest/cluster"].Missing)
+		assert.False(t, nodes["configMap/graphtest/network-config"].Missing)
+	})
+
+	t.Run("Blocked installations", func(t *testing.T) {
+		assert.True(t, nodes["installation/graphtest/app"].Blocked)
+		assert.False(t, nodes["installation/graphtest/database"].Blocked)
+		assert.False(t, nodes["installation/graphtest/network"].Blocked)
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		assert.Equal(t, [][]string{{"installation/graphtest/cycle-a", "installation/graphtest/cycle-b"}}, g.Cycles)
+		assert.True(t, nodes["installation/graphtest/cycle-a"].InCycle)
+		assert.True(t, nodes["installation/graphtest/cycle-b"].InCycle)
+		assert.False(t, nodes["installation/graphtest/app"].InCycle)
+		for _, edge := range g.Edges {
+			assert.Equal(t, edge.From == "installation/graphtest/cycle-a" || edge.From == "installation/graphtest/cycle-b", edge.InCycle, edge.From)
+		}
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		assert.NoError(t, g.WriteDOT(out))
+		assert.Contains(t, out.String(), "digraph installations {")
+		assert.Contains(t, out.String(), `"installation/graphtest/database" -> "installation/graphtest/app" [label="dbUrl: db-url"];`)
+		assert.Contains(t, out.String(), `"installation/graphtest/cycle-a" -> "installation/graphtest/cycle-b" [label="y", color=red];`)
+		assert.Contains(t, out.String(), `"secret/graphtest/app-credentials" [label="[secret] app-credentials\nMISSING", shape=ellipse, color=red, style=dashed];`)
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		assert.NoError(t, g.WriteMermaid(out))
+		assert.Contains(t, out.String(), "flowchart LR\n")
+		assert.Contains(t, out.String(), `n2["[installation] app<br/>phase: Pending<br/>BLOCKED"]`)
+		assert.Contains(t, out.String(), "class n2 blocked\n")
+		assert.Contains(t, out.String(), `n5 -->|"dbUrl: db-url"| n2`)
+	})
+}
+
+func nodeIDs(g *Graph) []string {
+	ids := []string{}
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
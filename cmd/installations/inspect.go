@@ -11,6 +11,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
 	"github.com/gardener/landscapercli/pkg/logger"
@@ -27,6 +28,10 @@ type statusOptions struct {
 	detailMode     bool
 	showExecutions bool
 	showOnlyFailed bool
+	watch          bool
+	showImports    bool
+	showExports    bool
+	showSecrets    bool
 
 	oyaml bool
 	ojson bool
@@ -107,9 +112,17 @@ func (o *statusOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Lo
 		return fmt.Errorf("no more than one output mode may be set: yaml=%v, json=%v, wide=%v", o.oyaml, o.ojson, o.owide)
 	}
 
-	collector := inspect.Collector{
-		K8sClient: k8sClient,
+	if o.watch {
+		if len(o.installationName) == 0 {
+			return fmt.Errorf("the --watch option can only be used when an installation name is provided")
+		}
+		if o.oyaml || o.ojson {
+			return fmt.Errorf("the --watch option cannot be used with yaml or json output")
+		}
+		return o.watchInstallation(ctx, cmd, k8sClient, o.transformer())
 	}
+
+	collector := o.collector(k8sClient)
 	installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
 	if err != nil {
 		return fmt.Errorf("cannot collect installation: %w", err)
@@ -133,15 +146,7 @@ func (o *statusOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Lo
 		return nil
 	}
 
-	transformer := inspect.Transformer{
-		DetailedMode:   o.detailMode,
-		ShowExecutions: o.showExecutions,
-		ShowOnlyFailed: o.showOnlyFailed,
-		ShowNamespaces: o.allNamespaces,
-		WideMode:       o.owide,
-	}
-
-	transformedTrees, err := transformer.TransformToPrintableTrees(installationTrees)
+	transformedTrees, err := o.transformer().TransformToPrintableTrees(installationTrees)
 	if err != nil {
 		return fmt.Errorf("error transforming CR to printable tree: %w", err)
 	}
@@ -152,6 +157,25 @@ func (o *statusOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Lo
 	return nil
 }
 
+func (o *statusOptions) collector(k8sClient client.Client) inspect.Collector {
+	return inspect.Collector{
+		K8sClient:      k8sClient,
+		CollectImports: o.showImports,
+		CollectExports: o.showExports,
+		ShowSecrets:    o.showSecrets,
+	}
+}
+
+func (o *statusOptions) transformer() inspect.Transformer {
+	return inspect.Transformer{
+		DetailedMode:   o.detailMode,
+		ShowExecutions: o.showExecutions,
+		ShowOnlyFailed: o.showOnlyFailed,
+		ShowNamespaces: o.allNamespaces,
+		WideMode:       o.owide,
+	}
+}
+
 func (o *statusOptions) validateArgs(args []string) error {
 	if len(args) == 1 {
 		o.installationName = args[0]
@@ -166,6 +190,10 @@ func (o *statusOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVarP(&o.detailMode, "show-details", "d", false, "show detailed information about installations, executions and deployitems. Similar to kubectl describe installation installation-name.")
 	fs.BoolVarP(&o.showExecutions, "show-executions", "e", false, "show the executions in the tree. By default, the executions are not shown.")
 	fs.BoolVarP(&o.showOnlyFailed, "show-failed", "f", false, "show only items that are in phase 'Failed'. It also prints parent elements to the failed items.")
+	fs.BoolVar(&o.showImports, "show-imports", false, "show the values of the imports of the installations, resolved from data objects, targets, secrets and configmaps.")
+	fs.BoolVar(&o.showExports, "show-exports", false, "show the values of the exports of the installations, resolved from data objects and targets.")
+	fs.BoolVar(&o.showSecrets, "show-secrets", false, "show the values of secrets and target configurations in the imports and exports. By default, they are redacted.")
+	fs.BoolVar(&o.watch, "watch", false, "watch the installation and redraw the tree whenever a phase changes. Exits when the installation has succeeded or failed. An installation name must be given.")
 	fs.BoolVarP(&o.oyaml, "oyaml", "y", false, "output in yaml format. Equivalent to '-o yaml'.")
 	fs.BoolVarP(&o.ojson, "ojson", "j", false, "output in json format. Equivalent to '-o json'.")
 	fs.BoolVarP(&o.owide, "owide", "w", false, "output some additional information. Equivalent to '-o wide'.")
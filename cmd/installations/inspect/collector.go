@@ -11,6 +11,12 @@ import (
 //Collector is responsible for collecting CR (installations, executions, deployItems) from a cluster using the K8sClient.
 type Collector struct {
 	K8sClient client.Client
+	//CollectImports resolves the values of the imports of the installations.
+	CollectImports bool
+	//CollectExports resolves the values of the exports of the installations.
+	CollectExports bool
+	//ShowSecrets disables the redaction of secrets and target configurations in the imports and exports.
+	ShowSecrets bool
 }
 
 //CollectInstallationsInCluster collects a single installation (including all referenced executions and deployitems)
@@ -60,6 +66,13 @@ func (c *Collector) collectInstallationTree(name string, namespace string) (*Ins
 		Installation: &inst,
 	}
 
+	if c.CollectImports {
+		tree.Imports = c.collectImports(&inst)
+	}
+	if c.CollectExports {
+		tree.Exports = c.collectExports(&inst)
+	}
+
 	//resolve all sub installations
 	for _, subInst := range inst.Status.InstallationReferences {
 		subInstTree, err := c.collectInstallationTree(subInst.Reference.Name, namespace)
@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	containerv1alpha1 "github.com/gardener/landscaper/apis/deployer/container/v1alpha1"
+	helmv1alpha1 "github.com/gardener/landscaper/apis/deployer/helm/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gardener/landscapercli/pkg/deployitems"
+)
+
+// DeployItemStatus is the deployer specific summary of a deploy item.
+type DeployItemStatus struct {
+	// Summary contains short lines that describe the configuration and status of the deploy item,
+	// e.g. the helm chart or the state of the container.
+	Summary []string
+	// ManagedResources contains the kubernetes resources that are deployed by the deploy item.
+	ManagedResources []corev1.ObjectReference
+}
+
+// DeployItemStatusFormatter summarizes the deployer specific configuration and provider status of a deploy item.
+type DeployItemStatusFormatter func(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error)
+
+var deployItemStatusFormatters = map[lsv1alpha1.DeployItemType]DeployItemStatusFormatter{}
+
+func init() {
+	RegisterDeployItemStatusFormatter(deployitems.HelmDeployItemType, formatHelmStatus)
+	RegisterDeployItemStatusFormatter(deployitems.ContainerDeployItemType, formatContainerStatus)
+	RegisterDeployItemStatusFormatter(deployitems.ManifestDeployItemType, formatManifestStatus)
+	RegisterDeployItemStatusFormatter(deployitems.MockDeployItemType, formatMockStatus)
+}
+
+// RegisterDeployItemStatusFormatter registers the status formatter for deploy items of the given type.
+// An existing formatter for the type is replaced. Formatters can only be registered by packages that are compiled
+// into the cli, the deploy items of all other types are summarized by formatProviderStatus.
+func RegisterDeployItemStatusFormatter(deployItemType lsv1alpha1.DeployItemType, formatter DeployItemStatusFormatter) {
+	deployItemStatusFormatters[deployItemType] = formatter
+}
+
+// FormatDeployItemStatus returns the deployer specific summary of a deploy item.
+// Deploy items without a registered formatter are summarized by their provider status.
+// It returns nil if no formatter is registered for the type of the deploy item and it has no provider status.
+func FormatDeployItemStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	formatter, ok := deployItemStatusFormatters[deployItem.Spec.Type]
+	if !ok {
+		return formatProviderStatus(deployItem)
+	}
+	return formatter(deployItem)
+}
+
+// formatProviderStatus summarizes the provider status of deploy items of unknown deployers.
+// Every top-level field of the provider status is shown in one line, except for the managed resources,
+// which are returned like for the helm and manifest deployers.
+func formatProviderStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	providerStatus := map[string]interface{}{}
+	if err := decodeProviderStatus(deployItem, &providerStatus); err != nil {
+		return nil, fmt.Errorf("unable to parse provider status: %w", err)
+	}
+	if len(providerStatus) == 0 {
+		return nil, nil
+	}
+
+	status := &DeployItemStatus{}
+	fields := []string{}
+	for field := range providerStatus {
+		switch field {
+		case "apiVersion", "kind", "managedResources":
+		default:
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		value, err := formatProviderStatusValue(providerStatus[field])
+		if err != nil {
+			return nil, fmt.Errorf("unable to format provider status field %s: %w", field, err)
+		}
+		status.Summary = append(status.Summary, fmt.Sprintf("%s: %s", field, value))
+	}
+
+	if _, ok := providerStatus["managedResources"]; ok {
+		managedResources, err := DecodeManagedResources(deployItem)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse provider status: %w", err)
+		}
+		status.ManagedResources = managedResources
+	}
+	return status, nil
+}
+
+// formatProviderStatusValue formats strings as they are and all other values as json.
+func formatProviderStatusValue(value interface{}) (string, error) {
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func formatHelmStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	status := &DeployItemStatus{}
+
+	config := &helmv1alpha1.ProviderConfiguration{}
+	if err := decodeConfiguration(deployItem, config); err != nil {
+		return nil, fmt.Errorf("unable to parse helm config: %w", err)
+	}
+	chartLocation := ""
+	if len(config.Chart.Ref) != 0 {
+		chartLocation = config.Chart.Ref
+	} else if config.Chart.Archive != nil {
+		if len(config.Chart.Archive.Raw) != 0 {
+			chartLocation = "inline (archive)"
+		} else if config.Chart.Archive.Remote != nil {
+			chartLocation = fmt.Sprintf("%s (archive)", config.Chart.Archive.Remote.URL)
+		}
+	} else if config.Chart.FromResource != nil {
+		cd := "inline component descriptor"
+		if config.Chart.FromResource.Reference != nil {
+			cd = fmt.Sprintf("%s:%s", config.Chart.FromResource.Reference.ComponentName, config.Chart.FromResource.Reference.Version)
+		}
+		chartLocation = fmt.Sprintf("resource %q from %s", config.Chart.FromResource.ResourceName, cd)
+	}
+	if len(chartLocation) == 0 {
+		chartLocation = "unknown"
+	}
+	status.Summary = append(status.Summary, fmt.Sprintf("Chart: %s", chartLocation))
+	if len(config.Name) != 0 {
+		status.Summary = append(status.Summary, fmt.Sprintf("Release: %s/%s", config.Namespace, config.Name))
+	}
+
+	managedResources, err := DecodeManagedResources(deployItem)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse helm status: %w", err)
+	}
+	status.ManagedResources = managedResources
+	return status, nil
+}
+
+func formatContainerStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	status := &DeployItemStatus{}
+
+	config := &containerv1alpha1.ProviderConfiguration{}
+	if err := decodeConfiguration(deployItem, config); err != nil {
+		return nil, fmt.Errorf("unable to parse container config: %w", err)
+	}
+	status.Summary = append(status.Summary, fmt.Sprintf("Image: %s", config.Image))
+	if len(config.Command) != 0 {
+		status.Summary = append(status.Summary, fmt.Sprintf("Command: %s", quoteAll(config.Command)))
+	}
+	if len(config.Args) != 0 {
+		status.Summary = append(status.Summary, fmt.Sprintf("Args: %s", quoteAll(config.Args)))
+	}
+
+	providerStatus := &containerv1alpha1.ProviderStatus{}
+	if err := decodeProviderStatus(deployItem, providerStatus); err != nil {
+		return nil, fmt.Errorf("unable to parse container status: %w", err)
+	}
+	if providerStatus.PodStatus != nil {
+		podStatus := providerStatus.PodStatus
+		pod := podStatus.PodName
+		if podStatus.LastRun != nil {
+			pod = fmt.Sprintf("%s (last %s run at %s)", pod, strings.ToLower(providerStatus.LastOperation), podStatus.LastRun.Format("2006-01-02 15:04:05"))
+		}
+		status.Summary = append(status.Summary,
+			fmt.Sprintf("Pod: %s", pod),
+			fmt.Sprintf("Container: %s", formatContainerState(podStatus.ContainerStatus)))
+	}
+	return status, nil
+}
+
+func formatManifestStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	status := &DeployItemStatus{}
+
+	config := &struct {
+		Manifests []json.RawMessage `json:"manifests,omitempty"`
+	}{}
+	if err := decodeConfiguration(deployItem, config); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest config: %w", err)
+	}
+	status.Summary = append(status.Summary, fmt.Sprintf("Manifests: %d", len(config.Manifests)))
+
+	managedResources, err := DecodeManagedResources(deployItem)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest status: %w", err)
+	}
+	status.ManagedResources = managedResources
+	return status, nil
+}
+
+func formatMockStatus(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+	status := &DeployItemStatus{}
+
+	config := &struct {
+		Phase string `json:"phase,omitempty"`
+	}{}
+	if err := decodeConfiguration(deployItem, config); err != nil {
+		return nil, fmt.Errorf("unable to parse mock config: %w", err)
+	}
+	if len(config.Phase) != 0 {
+		status.Summary = append(status.Summary, fmt.Sprintf("Configured phase: %s", config.Phase))
+	}
+	if deployItem.Status.ProviderStatus != nil && len(deployItem.Status.ProviderStatus.Raw) != 0 {
+		status.Summary = append(status.Summary, fmt.Sprintf("Provider status: %s", string(deployItem.Status.ProviderStatus.Raw)))
+	}
+	return status, nil
+}
+
+// managedResource is a resource of the managed resource list of the helm and manifest deployers.
+// Older deployer versions store the object references directly, newer ones in the resource field.
+type managedResource struct {
+	corev1.ObjectReference `json:",inline"`
+	Resource               *corev1.ObjectReference `json:"resource,omitempty"`
+}
+
+// DecodeManagedResources returns the managed resources of the provider status of helm and manifest deployItems.
+// In contrast to FormatDeployItemStatus, the configuration of the deployItem is not decoded.
+func DecodeManagedResources(deployItem *lsv1alpha1.DeployItem) ([]corev1.ObjectReference, error) {
+	providerStatus := &struct {
+		ManagedResources []managedResource `json:"managedResources,omitempty"`
+	}{}
+	if err := decodeProviderStatus(deployItem, providerStatus); err != nil {
+		return nil, err
+	}
+
+	resources := []corev1.ObjectReference{}
+	for _, res := range providerStatus.ManagedResources {
+		if res.Resource != nil {
+			resources = append(resources, *res.Resource)
+		} else {
+			resources = append(resources, res.ObjectReference)
+		}
+	}
+	return resources, nil
+}
+
+func decodeConfiguration(deployItem *lsv1alpha1.DeployItem, config interface{}) error {
+	if deployItem.Spec.Configuration == nil || len(deployItem.Spec.Configuration.Raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(deployItem.Spec.Configuration.Raw, config)
+}
+
+func decodeProviderStatus(deployItem *lsv1alpha1.DeployItem, providerStatus interface{}) error {
+	if deployItem.Status.ProviderStatus == nil || len(deployItem.Status.ProviderStatus.Raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(deployItem.Status.ProviderStatus.Raw, providerStatus)
+}
+
+func formatContainerState(containerStatus containerv1alpha1.ContainerStatus) string {
+	state := containerStatus.State
+	var description string
+	switch {
+	case state.Running != nil:
+		description = "running"
+	case state.Waiting != nil:
+		description = fmt.Sprintf("waiting (%s)", state.Waiting.Reason)
+	case state.Terminated != nil:
+		description = fmt.Sprintf("terminated (%s, exit code %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	default:
+		description = "unknown"
+	}
+	if len(containerStatus.Message) != 0 {
+		description = fmt.Sprintf("%s: %s", description, containerStatus.Message)
+	}
+	return description
+}
+
+func quoteAll(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// formatManagedResource formats a managed resource as apiVersion/kind namespace/name.
+func formatManagedResource(resource corev1.ObjectReference) string {
+	name := resource.Name
+	if len(resource.Namespace) != 0 {
+		name = fmt.Sprintf("%s/%s", resource.Namespace, resource.Name)
+	}
+	return fmt.Sprintf("%s/%s %s", resource.APIVersion, resource.Kind, name)
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"testing"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestFormatDeployItemStatus(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata")
+	assert.NoError(t, err)
+
+	collector := Collector{
+		K8sClient: fakeClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("my-aggregation", "inttest")
+	assert.NoError(t, err)
+	ingress := installationTrees[0].SubInstallations[0].Execution.DeployItems[0].DeployItem
+	server := installationTrees[0].SubInstallations[1].Execution.DeployItems[0].DeployItem
+
+	t.Run("helm", func(t *testing.T) {
+		status, err := FormatDeployItemStatus(ingress)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Chart: eu.gcr.io/gardener-project/landscaper/tutorials/charts/ingress-nginx:v0.1.0",
+			"Release: inttest2/test",
+		}, status.Summary)
+		assert.Equal(t, []corev1.ObjectReference{
+			{APIVersion: "v1", Kind: "Service", Name: "test-ingress-nginx-controller", Namespace: "inttest2"},
+		}, status.ManagedResources)
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		status, err := FormatDeployItemStatus(server)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Manifests: 3"}, status.Summary)
+		assert.Empty(t, status.ManagedResources)
+	})
+
+	t.Run("manifest with policies", func(t *testing.T) {
+		deployItem := &lsv1alpha1.DeployItem{
+			Spec: lsv1alpha1.DeployItemSpec{Type: "landscaper.gardener.cloud/kubernetes-manifest"},
+			Status: lsv1alpha1.DeployItemStatus{
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"managedResources":[
+					{"policy":"manage","resource":{"apiVersion":"apps/v1","kind":"Deployment","name":"echo-server","namespace":"inttest2"}}]}`)},
+			},
+		}
+		status, err := FormatDeployItemStatus(deployItem)
+		assert.NoError(t, err)
+		assert.Equal(t, []corev1.ObjectReference{
+			{APIVersion: "apps/v1", Kind: "Deployment", Name: "echo-server", Namespace: "inttest2"},
+		}, status.ManagedResources)
+	})
+
+	t.Run("container", func(t *testing.T) {
+		deployItem := &lsv1alpha1.DeployItem{
+			Spec: lsv1alpha1.DeployItemSpec{
+				Type:          "landscaper.gardener.cloud/container",
+				Configuration: &runtime.RawExtension{Raw: []byte(`{"image":"alpine:3.15","command":["sh","-c"],"args":["echo hello"]}`)},
+			},
+			Status: lsv1alpha1.DeployItemStatus{
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"lastOperation":"Reconcile","podStatus":{"podName":"my-pod",
+					"containerStatus":{"image":"alpine:3.15","imageID":"","state":{"terminated":{"exitCode":1,"reason":"Error"}}}}}`)},
+			},
+		}
+		status, err := FormatDeployItemStatus(deployItem)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Image: alpine:3.15",
+			`Command: "sh" "-c"`,
+			`Args: "echo hello"`,
+			"Pod: my-pod",
+			"Container: terminated (Error, exit code 1)",
+		}, status.Summary)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		deployItem := &lsv1alpha1.DeployItem{
+			Spec: lsv1alpha1.DeployItemSpec{
+				Type:          "landscaper.gardener.cloud/mock",
+				Configuration: &runtime.RawExtension{Raw: []byte(`{"phase":"Succeeded"}`)},
+			},
+			Status: lsv1alpha1.DeployItemStatus{
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"key":"value"}`)},
+			},
+		}
+		status, err := FormatDeployItemStatus(deployItem)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Configured phase: Succeeded", `Provider status: {"key":"value"}`}, status.Summary)
+	})
+
+	t.Run("unknown types without provider status have no status", func(t *testing.T) {
+		status, err := FormatDeployItemStatus(&lsv1alpha1.DeployItem{Spec: lsv1alpha1.DeployItemSpec{Type: "example.com/unknown"}})
+		assert.NoError(t, err)
+		assert.Nil(t, status)
+	})
+
+	t.Run("unknown types are summarized by their provider status", func(t *testing.T) {
+		deployItem := &lsv1alpha1.DeployItem{
+			Spec: lsv1alpha1.DeployItemSpec{Type: "example.com/unknown"},
+			Status: lsv1alpha1.DeployItemStatus{
+				ProviderStatus: &runtime.RawExtension{Raw: []byte(`{
+  "apiVersion": "example.com/v1alpha1",
+  "kind": "ProviderStatus",
+  "state": "Ready",
+  "replicas": 3,
+  "endpoint": {"host": "example.com", "port": 443},
+  "managedResources": [{"apiVersion": "v1", "kind": "ConfigMap", "name": "config", "namespace": "default"}]
+}`)},
+			},
+		}
+		status, err := FormatDeployItemStatus(deployItem)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{
+				`endpoint: {"host":"example.com","port":443}`,
+				"replicas: 3",
+				"state: Ready",
+			}, status.Summary)
+			assert.Equal(t, []corev1.ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "config", Namespace: "default"}}, status.ManagedResources)
+		}
+	})
+
+	t.Run("custom formatters", func(t *testing.T) {
+		RegisterDeployItemStatusFormatter("example.com/custom", func(deployItem *lsv1alpha1.DeployItem) (*DeployItemStatus, error) {
+			return &DeployItemStatus{Summary: []string{"Custom: " + deployItem.Name}}, nil
+		})
+		defer delete(deployItemStatusFormatters, "example.com/custom")
+
+		deployItem := &lsv1alpha1.DeployItem{Spec: lsv1alpha1.DeployItemSpec{Type: "example.com/custom"}}
+		deployItem.Name = "my-item"
+		deployItem.Spec.Timeout = &lsv1alpha1.Duration{Duration: 5 * time.Minute}
+		deployItem.Status.Deployer = lsv1alpha1.DeployerInformation{Identity: "custom-7d4f", Name: "custom-deployer", Version: "v1.0.0"}
+		assert.Equal(t, `Type: example.com/custom
+Deployer: custom-deployer v1.0.0 (custom-7d4f)
+Timeout: 5m0s
+Custom: my-item`, formatDeployItemDetails(deployItem))
+	})
+
+	t.Run("details with managed resources", func(t *testing.T) {
+		assert.Equal(t, `Type: landscaper.gardener.cloud/helm
+Chart: eu.gcr.io/gardener-project/landscaper/tutorials/charts/ingress-nginx:v0.1.0
+Release: inttest2/test
+Managed resources:
+  v1/Service inttest2/test-ingress-nginx-controller`, formatDeployItemDetails(ingress))
+	})
+}
+
+func TestFormatLastError(t *testing.T) {
+	assert.Equal(t, "Last error: timed out", formatLastError(&lsv1alpha1.Error{Message: "timed out"}))
+	assert.Equal(t, "Last error: timed out (ERR_TIMEOUT, ERR_UNAUTHORIZED)", formatLastError(&lsv1alpha1.Error{
+		Message: "timed out",
+		Codes:   []lsv1alpha1.ErrorCode{lsv1alpha1.ErrorTimeout, lsv1alpha1.ErrorUnauthorized},
+	}))
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"fmt"
+)
+
+const (
+	installationKind = "Installation"
+	executionKind    = "Execution"
+	deployItemKind   = "DeployItem"
+)
+
+//Phases maps the installations, executions and deployItems of installation trees to their phases.
+type Phases map[string]string
+
+func phaseKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+//CollectPhases returns the phases of all installations, executions and deployItems of the installation trees.
+func CollectPhases(installationTrees []*InstallationTree) Phases {
+	phases := Phases{}
+	for _, installationTree := range installationTrees {
+		phases.addInstallation(installationTree)
+	}
+	return phases
+}
+
+func (p Phases) addInstallation(installationTree *InstallationTree) {
+	if installationTree == nil {
+		return
+	}
+	inst := installationTree.Installation
+	p[phaseKey(installationKind, inst.Namespace, inst.Name)] = string(inst.Status.Phase)
+
+	for _, subInst := range installationTree.SubInstallations {
+		p.addInstallation(subInst)
+	}
+
+	if installationTree.Execution != nil {
+		exec := installationTree.Execution.Execution
+		p[phaseKey(executionKind, exec.Namespace, exec.Name)] = string(exec.Status.Phase)
+		for _, deployItem := range installationTree.Execution.DeployItems {
+			p[phaseKey(deployItemKind, deployItem.DeployItem.Namespace, deployItem.DeployItem.Name)] = string(deployItem.DeployItem.Status.Phase)
+		}
+	}
+}
+
+//Equal returns true if both phases contain the same objects in the same phases.
+func (p Phases) Equal(other Phases) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for key, phase := range p {
+		if otherPhase, ok := other[key]; !ok || otherPhase != phase {
+			return false
+		}
+	}
+	return true
+}
+
+//previousPhase returns the phase of an object if it has been in another phase than the given one.
+func (p Phases) previousPhase(kind, namespace, name, phase string) (string, bool) {
+	previous, ok := p[phaseKey(kind, namespace, name)]
+	if !ok || previous == phase {
+		return "", false
+	}
+	return previous, true
+}
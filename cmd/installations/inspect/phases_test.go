@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"testing"
+
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhases(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata")
+	assert.NoError(t, err)
+
+	collector := Collector{
+		K8sClient: fakeClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("my-aggregation", "inttest")
+	assert.NoError(t, err)
+
+	phases := CollectPhases(installationTrees)
+
+	t.Run("Collection of the phases of all objects", func(t *testing.T) {
+		assert.Equal(t, Phases{
+			"Installation/inttest/my-aggregation":           "Succeeded",
+			"Installation/inttest/ingress-hhsjf":            "Succeeded",
+			"Installation/inttest/server-gw64l":             "Succeeded",
+			"Execution/inttest/ingress-hhsjf":               "Succeeded",
+			"Execution/inttest/server-gw64l":                "Succeeded",
+			"DeployItem/inttest/ingress-hhsjf-deploy-kptjl": "Succeeded",
+			"DeployItem/inttest/server-gw64l-deploy-7mhc2":  "Succeeded",
+		}, phases)
+		assert.True(t, phases.Equal(CollectPhases(installationTrees)))
+	})
+
+	t.Run("Highlighting of phase transitions", func(t *testing.T) {
+		previousPhases := CollectPhases(installationTrees)
+		previousPhases["DeployItem/inttest/server-gw64l-deploy-7mhc2"] = "Progressing"
+		assert.False(t, phases.Equal(previousPhases))
+
+		transformer := Transformer{
+			PreviousPhases: previousPhases,
+		}
+		printableTrees, err := transformer.TransformToPrintableTrees(installationTrees)
+		assert.NoError(t, err)
+
+		output := PrintTrees(printableTrees)
+		assert.Contains(t, output.String(), "[Progressing ➜ ✅ Succeeded] DeployItem server-gw64l-deploy-7mhc2")
+		assert.Contains(t, output.String(), "[✅ Succeeded] DeployItem ingress-hhsjf-deploy-kptjl")
+	})
+}
@@ -1,13 +1,12 @@
 package tree
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
-	containerv1alpha1 "github.com/gardener/landscaper/apis/deployer/container/v1alpha1"
-	helmv1alpha1 "github.com/gardener/landscaper/apis/deployer/helm/v1alpha1"
 	"sigs.k8s.io/yaml"
 )
 
@@ -18,6 +17,8 @@ type Transformer struct {
 	ShowOnlyFailed bool
 	ShowNamespaces bool
 	WideMode       bool
+	//PreviousPhases are the phases of an earlier transformation. If set, phase transitions are highlighted.
+	PreviousPhases Phases
 }
 
 //TransformToPrintableTrees transform a []*InstallationTree to []PrintableTreeNodes for the Printer.
@@ -53,7 +54,7 @@ func (t Transformer) transformInstallation(installationTree *InstallationTree) (
 	}
 
 	printableNode.Headline = fmt.Sprintf("[%s] Installation %s%s",
-		formatStatus(string(installationTree.Installation.Status.Phase)), namespaceInfo, installationTree.Installation.Name)
+		t.formatPhase(installationKind, installationTree.Installation.Namespace, installationTree.Installation.Name, string(installationTree.Installation.Status.Phase)), namespaceInfo, installationTree.Installation.Name)
 
 	if t.WideMode {
 		wide := strings.Builder{}
@@ -81,6 +82,13 @@ func (t Transformer) transformInstallation(installationTree *InstallationTree) (
 		printableNode.Description = fmt.Sprintf("Last error: %s", installationTree.Installation.Status.LastError.Message)
 	}
 
+	if values := formatParameterValues(installationTree); values != "" {
+		if printableNode.Description != "" {
+			printableNode.Description += "\n"
+		}
+		printableNode.Description += values
+	}
+
 	if len(installationTree.SubInstallations) > 0 {
 		for _, inst := range installationTree.SubInstallations {
 			transformedInstalaltion, err := t.transformInstallation(inst)
@@ -113,7 +121,7 @@ func (t Transformer) transformExecution(executionTree *ExecutionTree) (*Printabl
 
 	if t.ShowExecutions || t.DetailedMode {
 		printableNode.Headline = fmt.Sprintf("[%s] Execution %s",
-			formatStatus(string(executionTree.Execution.Status.Phase)), executionTree.Execution.Name)
+			t.formatPhase(executionKind, executionTree.Execution.Namespace, executionTree.Execution.Name, string(executionTree.Execution.Status.Phase)), executionTree.Execution.Name)
 	}
 
 	if t.DetailedMode {
@@ -146,68 +154,10 @@ func (t Transformer) transformDeployItem(deployItem *DeployItemLeaf) (*Printable
 	deployItem.DeployItem.SetManagedFields(nil)
 
 	printableNode.Headline = fmt.Sprintf("[%s] DeployItem %s",
-		formatStatus(string(deployItem.DeployItem.Status.Phase)), deployItem.DeployItem.Name)
+		t.formatPhase(deployItemKind, deployItem.DeployItem.Namespace, deployItem.DeployItem.Name, string(deployItem.DeployItem.Status.Phase)), deployItem.DeployItem.Name)
 
 	if t.WideMode {
-		wide := strings.Builder{}
-		diType := deployItem.DeployItem.Spec.Type
-		wide.WriteString(fmt.Sprintf("Type: %s", diType))
-		switch diType {
-		case "landscaper.gardener.cloud/helm":
-			// print helm chart location
-			wide.WriteString("\n")
-			config := &helmv1alpha1.ProviderConfiguration{}
-			err := json.Unmarshal(deployItem.DeployItem.Spec.Configuration.Raw, config)
-			if err != nil {
-				wide.WriteString("unable to parse helm config")
-			} else {
-				chartLocation := ""
-				if len(config.Chart.Ref) != 0 {
-					chartLocation = config.Chart.Ref
-				} else if config.Chart.Archive != nil {
-					if len(config.Chart.Archive.Raw) != 0 {
-						chartLocation = "inline (archive)"
-					} else if config.Chart.Archive.Remote != nil {
-						chartLocation = fmt.Sprintf("%s (archive)", config.Chart.Archive.Remote.URL)
-					}
-				} else if config.Chart.FromResource != nil {
-					cd := "inline component descriptor"
-					if config.Chart.FromResource.Reference != nil {
-						cd = fmt.Sprintf("%s:%s", config.Chart.FromResource.Reference.ComponentName, config.Chart.FromResource.Reference.Version)
-					}
-					chartLocation = fmt.Sprintf("resource %q from %s", config.Chart.FromResource.ResourceName, cd)
-				}
-				if len(chartLocation) == 0 {
-					chartLocation = "unknown"
-				}
-				wide.WriteString("Chart: ")
-				wide.WriteString(chartLocation)
-			}
-		case "landscaper.gardener.cloud/container":
-			// print container image and command
-			wide.WriteString("\n")
-			config := &containerv1alpha1.ProviderConfiguration{}
-			err := json.Unmarshal(deployItem.DeployItem.Spec.Configuration.Raw, config)
-			if err != nil {
-				wide.WriteString("unable to parse container config")
-			} else {
-				wide.WriteString("Image: ")
-				wide.WriteString(config.Image)
-				if len(config.Command) != 0 {
-					wide.WriteString("\nCommand:")
-					for _, e := range config.Command {
-						wide.WriteString(fmt.Sprintf(" %q", e))
-					}
-				}
-				if len(config.Args) != 0 {
-					wide.WriteString("\nArgs:")
-					for _, e := range config.Args {
-						wide.WriteString(fmt.Sprintf(" %q", e))
-					}
-				}
-			}
-		}
-		printableNode.WideData = wide.String()
+		printableNode.WideData = formatDeployItemDetails(deployItem.DeployItem)
 	}
 
 	if t.DetailedMode {
@@ -217,12 +167,106 @@ func (t Transformer) transformDeployItem(deployItem *DeployItemLeaf) (*Printable
 		}
 		printableNode.Description = string(marshaledExecution)
 	} else if deployItem.DeployItem.Status.LastError != nil {
-		printableNode.Description = fmt.Sprintf("Last error: %s", deployItem.DeployItem.Status.LastError.Message)
+		printableNode.Description = formatLastError(deployItem.DeployItem.Status.LastError)
 	}
 
 	return &printableNode, nil
 }
 
+//formatDeployItemDetails formats the deployer and timeout of a deploy item together with its deployer specific status.
+func formatDeployItemDetails(deployItem *lsv1alpha1.DeployItem) string {
+	lines := []string{fmt.Sprintf("Type: %s", deployItem.Spec.Type)}
+	deployer := deployItem.Status.Deployer
+	if len(deployer.Identity) != 0 {
+		lines = append(lines, fmt.Sprintf("Deployer: %s %s (%s)", deployer.Name, deployer.Version, deployer.Identity))
+	}
+	if deployItem.Spec.Timeout != nil {
+		lines = append(lines, fmt.Sprintf("Timeout: %s", deployItem.Spec.Timeout.Duration))
+	}
+	if deployItem.Status.LastReconcileTime != nil {
+		lines = append(lines, fmt.Sprintf("Last reconcile: %s", deployItem.Status.LastReconcileTime.Format("2006-01-02 15:04:05")))
+	}
+
+	status, err := FormatDeployItemStatus(deployItem)
+	if err != nil {
+		lines = append(lines, err.Error())
+	} else if status != nil {
+		lines = append(lines, status.Summary...)
+		if len(status.ManagedResources) != 0 {
+			lines = append(lines, "Managed resources:")
+			for _, resource := range status.ManagedResources {
+				lines = append(lines, fmt.Sprintf("  %s", formatManagedResource(resource)))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+//formatLastError formats the message and the error codes of the last error of a deploy item.
+func formatLastError(lastError *lsv1alpha1.Error) string {
+	if len(lastError.Codes) == 0 {
+		return fmt.Sprintf("Last error: %s", lastError.Message)
+	}
+	codes := []string{}
+	for _, code := range lastError.Codes {
+		codes = append(codes, string(code))
+	}
+	return fmt.Sprintf("Last error: %s (%s)", lastError.Message, strings.Join(codes, ", "))
+}
+
+//formatParameterValues formats the collected imports and exports of an installation.
+func formatParameterValues(installationTree *InstallationTree) string {
+	output := strings.Builder{}
+	for _, section := range []struct {
+		title  string
+		values []*ParameterValue
+	}{
+		{title: "Imports", values: installationTree.Imports},
+		{title: "Exports", values: installationTree.Exports},
+	} {
+		if section.values == nil {
+			// the values have not been collected
+			continue
+		}
+		if output.Len() != 0 {
+			output.WriteString("\n")
+		}
+		if len(section.values) == 0 {
+			output.WriteString(fmt.Sprintf("%s: none", section.title))
+			continue
+		}
+		output.WriteString(fmt.Sprintf("%s:", section.title))
+		for _, value := range section.values {
+			output.WriteString(fmt.Sprintf("\n  %s (%s): %s", value.Name, value.Source, formatParameterValue(value)))
+		}
+	}
+	return output.String()
+}
+
+func formatParameterValue(value *ParameterValue) string {
+	if value.Error != "" {
+		return fmt.Sprintf("error: %s", value.Error)
+	}
+	if value.Value == nil {
+		return "-"
+	}
+	marshaledValue := bytes.Buffer{}
+	encoder := json.NewEncoder(&marshaledValue)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value.Value); err != nil {
+		return fmt.Sprintf("error: %s", err.Error())
+	}
+	return strings.TrimSuffix(marshaledValue.String(), "\n")
+}
+
+//formatPhase formats the phase of an object and highlights the transition from its previous phase.
+func (t Transformer) formatPhase(kind, namespace, name, phase string) string {
+	if previous, ok := t.PreviousPhases.previousPhase(kind, namespace, name, phase); ok {
+		return fmt.Sprintf("%s ➜ %s", previous, formatStatus(phase))
+	}
+	return formatStatus(phase)
+}
+
 func formatStatus(status string) string {
 	if status == string(lsv1alpha1.ComponentPhaseSucceeded) {
 		return "✅ " + status
@@ -10,6 +10,8 @@ type InstallationTree struct {
 	SubInstallations []*InstallationTree      `json:"subInstallations,omitempty"`
 	Execution        *ExecutionTree           `json:"execution,omitempty"`
 	Installation     *lsv1alpha1.Installation `json:"installation,omitempty"`
+	Imports          []*ParameterValue        `json:"imports,omitempty"`
+	Exports          []*ParameterValue        `json:"exports,omitempty"`
 }
 
 func (i *InstallationTree) filterForFailedInstallation() *InstallationTree {
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	lsv1alpha1helper "github.com/gardener/landscaper/apis/core/v1alpha1/helper"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// RedactedValue replaces the values of secrets and target configurations.
+const RedactedValue = "<redacted>"
+
+// ParameterValue is the resolved value of an import or export of an installation.
+type ParameterValue struct {
+	Name string `json:"name"`
+	// Source describes where the value is taken from.
+	Source string `json:"source"`
+	// Value is the resolved value.
+	Value interface{} `json:"value,omitempty"`
+	// Error describes why the value could not be resolved.
+	Error string `json:"error,omitempty"`
+}
+
+// InstallationContext returns the context of the data objects and targets that are imported and exported by an installation.
+// Root installations have an empty context, sub-installations have the context of their parent installation.
+func InstallationContext(inst *lsv1alpha1.Installation) string {
+	for _, owner := range inst.OwnerReferences {
+		if owner.Kind == "Installation" {
+			return lsv1alpha1helper.DataObjectSourceFromInstallationName(owner.Name)
+		}
+	}
+	return ""
+}
+
+// collectImports resolves the values of the data and target imports of an installation.
+func (c *Collector) collectImports(inst *lsv1alpha1.Installation) []*ParameterValue {
+	ctx := InstallationContext(inst)
+	values := []*ParameterValue{}
+
+	for _, imp := range inst.Spec.Imports.Data {
+		value := &ParameterValue{Name: imp.Name}
+		switch {
+		case len(imp.DataRef) != 0:
+			value.Source = fmt.Sprintf("dataRef %s", imp.DataRef)
+			c.resolveDataObject(value, ctx, imp.DataRef, inst.Namespace)
+		case imp.SecretRef != nil:
+			value.Source = fmt.Sprintf("secretRef %s#%s", namespacedName(imp.SecretRef.ObjectReference, inst.Namespace), imp.SecretRef.Key)
+			c.resolveSecret(value, imp.SecretRef, inst.Namespace)
+		case imp.ConfigMapRef != nil:
+			value.Source = fmt.Sprintf("configMapRef %s#%s", namespacedName(imp.ConfigMapRef.ObjectReference, inst.Namespace), imp.ConfigMapRef.Key)
+			c.resolveConfigMap(value, imp.ConfigMapRef, inst.Namespace)
+		}
+		values = append(values, value)
+	}
+
+	for _, imp := range inst.Spec.Imports.Targets {
+		switch {
+		case len(imp.Target) != 0:
+			value := &ParameterValue{Name: imp.Name, Source: fmt.Sprintf("target %s", imp.Target)}
+			c.resolveTarget(value, ctx, imp.Target, inst.Namespace)
+			values = append(values, value)
+		case len(imp.Targets) != 0:
+			for i, target := range imp.Targets {
+				value := &ParameterValue{Name: fmt.Sprintf("%s[%d]", imp.Name, i), Source: fmt.Sprintf("target %s", target)}
+				c.resolveTarget(value, ctx, target, inst.Namespace)
+				values = append(values, value)
+			}
+		case len(imp.TargetListReference) != 0:
+			values = append(values, &ParameterValue{Name: imp.Name, Source: fmt.Sprintf("targetListRef %s", imp.TargetListReference)})
+		}
+	}
+
+	for _, name := range sortedMappingNames(inst.Spec.ImportDataMappings) {
+		value := &ParameterValue{Name: name, Source: "importDataMapping"}
+		if err := decodeJSON(inst.Spec.ImportDataMappings[name].RawMessage, &value.Value); err != nil {
+			value.Error = fmt.Sprintf("cannot decode value: %s", err.Error())
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// collectExports resolves the values of the data and target exports of an installation.
+func (c *Collector) collectExports(inst *lsv1alpha1.Installation) []*ParameterValue {
+	ctx := InstallationContext(inst)
+	values := []*ParameterValue{}
+
+	for _, exp := range inst.Spec.Exports.Data {
+		value := &ParameterValue{Name: exp.Name, Source: fmt.Sprintf("dataRef %s", exp.DataRef)}
+		c.resolveDataObject(value, ctx, exp.DataRef, inst.Namespace)
+		values = append(values, value)
+	}
+	for _, exp := range inst.Spec.Exports.Targets {
+		value := &ParameterValue{Name: exp.Name, Source: fmt.Sprintf("target %s", exp.Target)}
+		c.resolveTarget(value, ctx, exp.Target, inst.Namespace)
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (c *Collector) resolveDataObject(value *ParameterValue, ctx, dataRef, namespace string) {
+	key := client.ObjectKey{Name: lsv1alpha1helper.GenerateDataObjectName(ctx, dataRef), Namespace: namespace}
+	do := &lsv1alpha1.DataObject{}
+	if err := c.K8sClient.Get(context.TODO(), key, do); err != nil {
+		value.Error = getErrorMessage("dataobject", key, err)
+		return
+	}
+	if err := decodeJSON(do.Data.RawMessage, &value.Value); err != nil {
+		value.Error = fmt.Sprintf("cannot decode dataobject %s: %s", key.Name, err.Error())
+	}
+}
+
+func (c *Collector) resolveTarget(value *ParameterValue, ctx, target, namespace string) {
+	key := client.ObjectKey{Name: lsv1alpha1helper.GenerateDataObjectName(ctx, target), Namespace: namespace}
+	t := &lsv1alpha1.Target{}
+	if err := c.K8sClient.Get(context.TODO(), key, t); err != nil {
+		value.Error = getErrorMessage("target", key, err)
+		return
+	}
+
+	// target configurations contain credentials, like kubeconfigs
+	var config interface{} = RedactedValue
+	if c.ShowSecrets {
+		if err := decodeJSON(t.Spec.Configuration.RawMessage, &config); err != nil {
+			value.Error = fmt.Sprintf("cannot decode target %s: %s", key.Name, err.Error())
+			return
+		}
+	}
+	value.Value = map[string]interface{}{
+		"type":   string(t.Spec.Type),
+		"config": config,
+	}
+}
+
+func (c *Collector) resolveSecret(value *ParameterValue, ref *lsv1alpha1.SecretReference, namespace string) {
+	key := client.ObjectKey{Name: ref.Name, Namespace: namespaceOrDefault(ref.Namespace, namespace)}
+	secret := &corev1.Secret{}
+	if err := c.K8sClient.Get(context.TODO(), key, secret); err != nil {
+		value.Error = getErrorMessage("secret", key, err)
+		return
+	}
+	if !c.ShowSecrets {
+		value.Value = RedactedValue
+		return
+	}
+
+	data := map[string]string{}
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	resolveKey(value, data, ref.Key)
+}
+
+func (c *Collector) resolveConfigMap(value *ParameterValue, ref *lsv1alpha1.ConfigMapReference, namespace string) {
+	key := client.ObjectKey{Name: ref.Name, Namespace: namespaceOrDefault(ref.Namespace, namespace)}
+	configMap := &corev1.ConfigMap{}
+	if err := c.K8sClient.Get(context.TODO(), key, configMap); err != nil {
+		value.Error = getErrorMessage("configmap", key, err)
+		return
+	}
+	resolveKey(value, configMap.Data, ref.Key)
+}
+
+// resolveKey sets the value of the key of a secret or configmap. The value is parsed as yaml like in the landscaper.
+// If no key is given, the value is the map of all keys.
+func resolveKey(value *ParameterValue, data map[string]string, key string) {
+	if len(key) == 0 {
+		value.Value = data
+		return
+	}
+	raw, ok := data[key]
+	if !ok {
+		value.Error = fmt.Sprintf("key %s not found", key)
+		return
+	}
+	if err := yaml.Unmarshal([]byte(raw), &value.Value); err != nil {
+		value.Value = raw
+	}
+}
+
+// decodeJSON decodes raw json data. Empty data results in a nil value.
+func decodeJSON(data []byte, value *interface{}) error {
+	if len(data) == 0 {
+		*value = nil
+		return nil
+	}
+	return json.Unmarshal(data, value)
+}
+
+func getErrorMessage(kind string, key client.ObjectKey, err error) string {
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("%s %s not found", kind, key.Name)
+	}
+	return fmt.Sprintf("cannot get %s %s: %s", kind, key.Name, err.Error())
+}
+
+func namespacedName(ref lsv1alpha1.ObjectReference, namespace string) string {
+	return fmt.Sprintf("%s/%s", namespaceOrDefault(ref.Namespace, namespace), ref.Name)
+}
+
+func namespaceOrDefault(namespace, defaultNamespace string) string {
+	if len(namespace) == 0 {
+		return defaultNamespace
+	}
+	return namespace
+}
+
+func sortedMappingNames(mappings map[string]lsv1alpha1.AnyJSON) []string {
+	names := []string{}
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
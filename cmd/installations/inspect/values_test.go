@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tree
+
+import (
+	"testing"
+
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectParameterValues(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata")
+	assert.NoError(t, err)
+
+	collector := Collector{
+		K8sClient:      fakeClient,
+		CollectImports: true,
+		CollectExports: true,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("my-aggregation", "inttest")
+	assert.NoError(t, err)
+	aggregation := installationTrees[0]
+	ingress := aggregation.SubInstallations[0]
+
+	t.Run("Imports of a root installation", func(t *testing.T) {
+		assert.Equal(t, []*ParameterValue{
+			{
+				Name:   "aggNamespace",
+				Source: "configMapRef inttest/my-imports#namespace",
+				Value:  "inttest2",
+			},
+			{
+				Name:   "aggCluster",
+				Source: "target #my-cluster",
+				Value: map[string]interface{}{
+					"type":   "landscaper.gardener.cloud/kubernetes-cluster",
+					"config": RedactedValue,
+				},
+			},
+		}, aggregation.Imports)
+	})
+
+	t.Run("Exports that do not exist yet", func(t *testing.T) {
+		assert.Equal(t, []*ParameterValue{
+			{
+				Name:   "aggIngressClass",
+				Source: "dataRef myAggIngressClass",
+				Error:  "dataobject 2b6qbxj5zrd4keg3rgf5coykfugqisst not found",
+			},
+		}, aggregation.Exports)
+	})
+
+	t.Run("Imports of a sub-installation are resolved in the context of the parent", func(t *testing.T) {
+		assert.Equal(t, []*ParameterValue{
+			{
+				Name:   "namespace",
+				Source: "dataRef aggNamespace",
+				Value:  "inttest2",
+			},
+			{
+				Name:   "cluster",
+				Source: "target aggCluster",
+				Value: map[string]interface{}{
+					"type":   "landscaper.gardener.cloud/kubernetes-cluster",
+					"config": RedactedValue,
+				},
+			},
+		}, ingress.Imports)
+	})
+
+	t.Run("Secrets are shown if requested", func(t *testing.T) {
+		collector.ShowSecrets = true
+		installationTrees, err := collector.CollectInstallationsInCluster("my-aggregation", "inttest")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"type":   "landscaper.gardener.cloud/kubernetes-cluster",
+			"config": map[string]interface{}{"kubeconfig": "secret-kubeconfig"},
+		}, installationTrees[0].Imports[1].Value)
+	})
+
+	t.Run("Values are shown in the tree", func(t *testing.T) {
+		transformer := Transformer{}
+		printableTrees, err := transformer.TransformToPrintableTrees(installationTrees)
+		assert.NoError(t, err)
+
+		assert.Equal(t, `Imports:
+  aggNamespace (configMapRef inttest/my-imports#namespace): "inttest2"
+  aggCluster (target #my-cluster): {"config":"<redacted>","type":"landscaper.gardener.cloud/kubernetes-cluster"}
+Exports:
+  aggIngressClass (dataRef myAggIngressClass): error: dataobject 2b6qbxj5zrd4keg3rgf5coykfugqisst not found`, printableTrees[0].Description)
+	})
+}
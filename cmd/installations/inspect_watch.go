@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchInstallation watches the installations, executions and deployitems in the namespace of the installation
+// and redraws the installation tree whenever the phase of one of its objects changes.
+// It returns when the installation has succeeded and returns an error when it has failed.
+func (o *statusOptions) watchInstallation(ctx context.Context, cmd *cobra.Command, k8sClient client.Client, transformer inspect.Transformer) error {
+	cfg, _, err := util.BuildKubeConfigFromConfigOrCurrentClusterContext(o.kubeconfig)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s config from config or current cluster context: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	informerCache, err := cache.New(cfg, cache.Options{
+		Scheme:    scheme,
+		Namespace: o.namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create informer cache: %w", err)
+	}
+
+	// changed is notified about every event. Events that occur while a redraw is pending are merged.
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify() },
+		UpdateFunc: func(oldObj, newObj interface{}) { notify() },
+		DeleteFunc: func(obj interface{}) { notify() },
+	}
+	for _, obj := range []client.Object{&lsv1alpha1.Installation{}, &lsv1alpha1.Execution{}, &lsv1alpha1.DeployItem{}} {
+		informer, err := informerCache.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("cannot watch %T: %w", obj, err)
+		}
+		informer.AddEventHandler(handler)
+	}
+
+	cacheErr := make(chan error, 1)
+	go func() {
+		cacheErr <- informerCache.Start(ctx)
+	}()
+	if !informerCache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("cannot sync watches for namespace %s", o.namespace)
+	}
+
+	cachedClient, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
+		CacheReader: informerCache,
+		Client:      k8sClient,
+		// imported values are read directly, since they are not watched and may be located in other namespaces
+		UncachedObjects: []client.Object{&lsv1alpha1.DataObject{}, &lsv1alpha1.Target{}, &corev1.Secret{}, &corev1.ConfigMap{}},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot build cached k8s client: %w", err)
+	}
+	collector := o.collector(cachedClient)
+
+	var phases inspect.Phases
+	notify()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-cacheErr:
+			return fmt.Errorf("watching installation %s failed: %w", o.installationName, err)
+		case <-changed:
+		}
+
+		installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
+		if err != nil {
+			// objects that are referenced but not yet known are collected with one of the next events
+			if phases != nil && apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("cannot collect installation: %w", err)
+		}
+
+		newPhases := inspect.CollectPhases(installationTrees)
+		if phases != nil && phases.Equal(newPhases) {
+			continue
+		}
+
+		transformer.PreviousPhases = phases
+		transformedTrees, err := transformer.TransformToPrintableTrees(installationTrees)
+		if err != nil {
+			return fmt.Errorf("error transforming CR to printable tree: %w", err)
+		}
+		phases = newPhases
+
+		cmd.Print(clearScreen)
+		cmd.Printf("Watching installation %s/%s (last change %s)\n\n", o.namespace, o.installationName, time.Now().Format(time.Kitchen))
+		output := inspect.PrintTrees(transformedTrees)
+		cmd.Print(output.String())
+
+		inst := installationTrees[0].Installation
+		if inst.Status.ObservedGeneration != inst.Generation {
+			// the phase belongs to a previous generation of the installation
+			continue
+		}
+		switch inst.Status.Phase {
+		case lsv1alpha1.ComponentPhaseSucceeded:
+			return nil
+		case lsv1alpha1.ComponentPhaseFailed:
+			return fmt.Errorf("installation %s failed", o.installationName)
+		}
+	}
+}
@@ -31,9 +31,18 @@ func NewInstallationsCommand(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewCreateCommand(ctx))
+	cmd.AddCommand(NewApplyCommand(ctx))
 	cmd.AddCommand(NewSetImportParametersCommand(ctx))
 	cmd.AddCommand(NewInspectCommand(ctx))
 	cmd.AddCommand(NewForceDeleteCommand(ctx))
+	cmd.AddCommand(NewWaitCommand(ctx))
+	cmd.AddCommand(NewGraphCommand(ctx))
+	cmd.AddCommand(NewWhyCommand(ctx))
+	cmd.AddCommand(NewLogsCommand(ctx))
+	cmd.AddCommand(NewResourcesCommand(ctx))
+	cmd.AddCommand(NewReconcileCommand(ctx))
+	cmd.AddCommand(NewForceReconcileCommand(ctx))
+	cmd.AddCommand(NewAbortCommand(ctx))
 
 	return cmd
 }
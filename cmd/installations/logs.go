@@ -0,0 +1,344 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/apis/deployer/container"
+	containerv1alpha1 "github.com/gardener/landscaper/apis/deployer/container/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/deployitems"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+const containerStartPollInterval = 2 * time.Second
+
+// containerDeployerContainers are the containers of the pods of the container deployer in the order of their logs.
+var containerDeployerContainers = []string{container.InitContainerName, container.WaitContainerName, container.MainContainerName}
+
+type logsOptions struct {
+	kubeconfig       string
+	hostKubeconfig   string
+	installationName string
+	namespace        string
+
+	deployItemName string
+	follow         bool
+}
+
+// NewLogsCommand shows the logs of the container deploy items of an installation.
+func NewLogsCommand(ctx context.Context) *cobra.Command {
+	opts := &logsOptions{}
+	cmd := &cobra.Command{
+		Use:  "logs [installation-name] [--deployitem name] [--follow] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args: cobra.ExactArgs(1),
+		Example: `landscaper-cli installations logs my-installation
+landscaper-cli installations logs my-installation --deployitem my-installation-deploy-7mhc2 -f`,
+		Short: "Displays the logs of the init, wait and main containers of the pods that the container deployer runs for the container " +
+			"deployItems of an installation and its sub-installations.",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.installationName = args[0]
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *logsOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot collect installation: %w", err)
+	}
+	deployItems, err := containerDeployItems(installationTrees[0], o.deployItemName)
+	if err != nil {
+		return err
+	}
+
+	// the pods of the container deployer run in the host cluster of the deployer
+	hostKubeconfig := o.hostKubeconfig
+	if hostKubeconfig == "" {
+		hostKubeconfig = o.kubeconfig
+	}
+	cfg, _, err := util.BuildKubeConfigFromConfigOrCurrentClusterContext(hostKubeconfig)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s config of the host cluster: %w", err)
+	}
+	hostClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client of the host cluster: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s clientset of the host cluster: %w", err)
+	}
+
+	out := &syncWriter{w: cmd.OutOrStdout()}
+	if !o.follow {
+		for _, di := range deployItems {
+			pod, err := findContainerPod(ctx, hostClient, di)
+			if err != nil {
+				return err
+			}
+			if err := streamContainerLogs(ctx, hostClient, clientset, pod, di.Name, false, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// the logs of all deployItems are followed in parallel
+	var (
+		wg       sync.WaitGroup
+		errMutex sync.Mutex
+		errs     []error
+	)
+	for _, di := range deployItems {
+		pod, err := findContainerPod(ctx, hostClient, di)
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func(pod *corev1.Pod, deployItemName string) {
+			defer wg.Done()
+			if err := streamContainerLogs(ctx, hostClient, clientset, pod, deployItemName, true, out); err != nil {
+				errMutex.Lock()
+				errs = append(errs, err)
+				errMutex.Unlock()
+			}
+		}(pod, di.Name)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// containerDeployItems returns the container deployItems of the installation tree.
+// If a deployItem name is given, only this deployItem is returned.
+func containerDeployItems(installationTree *inspect.InstallationTree, deployItemName string) ([]*lsv1alpha1.DeployItem, error) {
+	deployItems := []*lsv1alpha1.DeployItem{}
+	for _, obj := range flattenInstallationTree(installationTree) {
+		di, ok := obj.object.(*lsv1alpha1.DeployItem)
+		if !ok {
+			continue
+		}
+		if deployItemName != "" && di.Name != deployItemName {
+			continue
+		}
+		if di.Spec.Type != deployitems.ContainerDeployItemType {
+			if deployItemName != "" {
+				return nil, fmt.Errorf("deployItem %s has type %s, but logs are only available for type %s", di.Name, di.Spec.Type, deployitems.ContainerDeployItemType)
+			}
+			continue
+		}
+		deployItems = append(deployItems, di)
+	}
+
+	if len(deployItems) == 0 {
+		if deployItemName != "" {
+			return nil, fmt.Errorf("deployItem %s not found in installation %s", deployItemName, installationTree.Installation.Name)
+		}
+		return nil, fmt.Errorf("installation %s has no deployItems of type %s", installationTree.Installation.Name, deployitems.ContainerDeployItemType)
+	}
+	return deployItems, nil
+}
+
+// findContainerPod returns the pod of a container deployItem. The pods are identified by the labels that the
+// container deployer sets. If there are multiple pods, the pod from the provider status of the deployItem is returned,
+// otherwise the newest one.
+func findContainerPod(ctx context.Context, hostClient client.Client, di *lsv1alpha1.DeployItem) (*corev1.Pod, error) {
+	pods := corev1.PodList{}
+	if err := hostClient.List(ctx, &pods, client.MatchingLabels{
+		container.ContainerDeployerDeployItemNameLabel:      di.Name,
+		container.ContainerDeployerDeployItemNamespaceLabel: di.Namespace,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot list pods of deployItem %s: %w", di.Name, err)
+	}
+
+	podName := ""
+	if di.Status.ProviderStatus != nil && len(di.Status.ProviderStatus.Raw) != 0 {
+		providerStatus := &containerv1alpha1.ProviderStatus{}
+		if err := json.Unmarshal(di.Status.ProviderStatus.Raw, providerStatus); err != nil {
+			return nil, fmt.Errorf("cannot decode provider status of deployItem %s: %w", di.Name, err)
+		}
+		if providerStatus.PodStatus != nil {
+			podName = providerStatus.PodStatus.PodName
+		}
+	}
+
+	if len(pods.Items) == 0 {
+		if podName != "" {
+			return nil, fmt.Errorf("pod %s of deployItem %s not found. It might have already been deleted by the container deployer", podName, di.Name)
+		}
+		return nil, fmt.Errorf("no pod found for deployItem %s. It might not have been picked up by the container deployer yet", di.Name)
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[j].CreationTimestamp.Before(&pods.Items[i].CreationTimestamp)
+	})
+	for i := range pods.Items {
+		if pods.Items[i].Name == podName {
+			return &pods.Items[i], nil
+		}
+	}
+	return &pods.Items[0], nil
+}
+
+// streamContainerLogs writes the logs of the init, wait and main containers of a pod to out.
+// Every line is prefixed with the deployItem and the container. If follow is set, the logs of the containers
+// are streamed in parallel and containers that have not been started yet are awaited.
+func streamContainerLogs(ctx context.Context, hostClient client.Client, clientset kubernetes.Interface, pod *corev1.Pod, deployItemName string, follow bool, out io.Writer) error {
+	if !follow {
+		for _, containerName := range containerDeployerContainers {
+			prefix := fmt.Sprintf("[%s/%s] ", deployItemName, containerName)
+			if !containerStarted(pod, containerName) {
+				fmt.Fprintf(out, "%scontainer has not been started yet\n", prefix)
+				continue
+			}
+			if err := streamLogs(ctx, clientset, pod, containerName, prefix, false, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errMutex sync.Mutex
+		errs     []error
+	)
+	for _, containerName := range containerDeployerContainers {
+		wg.Add(1)
+		go func(containerName string) {
+			defer wg.Done()
+			prefix := fmt.Sprintf("[%s/%s] ", deployItemName, containerName)
+			err := waitForContainerStart(ctx, hostClient, pod, containerName)
+			if err == nil {
+				err = streamLogs(ctx, clientset, pod, containerName, prefix, true, out)
+			}
+			if err != nil {
+				errMutex.Lock()
+				errs = append(errs, err)
+				errMutex.Unlock()
+			}
+		}(containerName)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func streamLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, containerName, prefix string, follow bool, out io.Writer) error {
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    follow,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get logs of container %s of pod %s/%s: %w", containerName, pod.Namespace, pod.Name, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(out, "%s%s\n", prefix, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read logs of container %s of pod %s/%s: %w", containerName, pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// waitForContainerStart waits until the container of the pod has been started.
+func waitForContainerStart(ctx context.Context, hostClient client.Client, pod *corev1.Pod, containerName string) error {
+	current := pod
+	return wait.PollImmediateUntil(containerStartPollInterval, func() (bool, error) {
+		if containerStarted(current, containerName) {
+			return true, nil
+		}
+		current = &corev1.Pod{}
+		if err := hostClient.Get(ctx, client.ObjectKeyFromObject(pod), current); err != nil {
+			return false, fmt.Errorf("cannot get pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		return containerStarted(current, containerName), nil
+	}, ctx.Done())
+}
+
+// containerStarted returns true if the container of the pod is running or has been terminated, i.e. if it has logs.
+func containerStarted(pod *corev1.Pod, containerName string) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == containerName {
+			return status.State.Running != nil || status.State.Terminated != nil || status.LastTerminationState.Terminated != nil
+		}
+	}
+	return false
+}
+
+// syncWriter serializes the writes of the parallel log streams.
+type syncWriter struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.w.Write(p)
+}
+
+func (o *logsOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVar(&o.hostKubeconfig, "host-kubeconfig", "", "path to the kubeconfig for the cluster in which the container deployer runs its pods. Defaults to --kubeconfig.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation. Required if --kubeconfig is used.")
+	fs.StringVar(&o.deployItemName, "deployitem", "", "name of the deployItem. If not set, the logs of all container deployItems of the installation are displayed.")
+	fs.BoolVarP(&o.follow, "follow", "f", false, "follow the logs until the containers have terminated")
+}
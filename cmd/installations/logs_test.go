@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/apis/deployer/container"
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+)
+
+func TestContainerDeployItems(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata/logs")
+	assert.NoError(t, err)
+
+	collector := inspect.Collector{
+		K8sClient: fakeClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("root", "logstest")
+	assert.NoError(t, err)
+	installationTree := installationTrees[0]
+
+	deployItems, err := containerDeployItems(installationTree, "")
+	assert.NoError(t, err)
+	assert.Len(t, deployItems, 1)
+	assert.Equal(t, "job", deployItems[0].Name)
+
+	deployItems, err = containerDeployItems(installationTree, "job")
+	assert.NoError(t, err)
+	assert.Len(t, deployItems, 1)
+
+	_, err = containerDeployItems(installationTree, "chart")
+	assert.EqualError(t, err, "deployItem chart has type landscaper.gardener.cloud/helm, but logs are only available for type landscaper.gardener.cloud/container")
+
+	_, err = containerDeployItems(installationTree, "unknown")
+	assert.EqualError(t, err, "deployItem unknown not found in installation root")
+}
+
+func TestFindContainerPod(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata/logs")
+	assert.NoError(t, err)
+	di := &lsv1alpha1.DeployItem{}
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "job", Namespace: "logstest"}, di))
+
+	t.Run("no pods", func(t *testing.T) {
+		hostClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := findContainerPod(ctx, hostClient, di)
+		assert.EqualError(t, err, "no pod found for deployItem job. It might not have been picked up by the container deployer yet")
+	})
+
+	// the pods of the container deployer are created in the host cluster
+	podLabels := map[string]string{
+		container.ContainerDeployerDeployItemNameLabel:      "job",
+		container.ContainerDeployerDeployItemNamespaceLabel: "logstest",
+	}
+	hostClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "job-old", Namespace: "ls-system", Labels: podLabels, CreationTimestamp: v1.NewTime(now.Add(-time.Hour))}},
+		&corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "job-new", Namespace: "ls-system", Labels: podLabels, CreationTimestamp: v1.NewTime(now)}},
+	).Build()
+
+	t.Run("newest pod", func(t *testing.T) {
+		pod, err := findContainerPod(ctx, hostClient, di)
+		assert.NoError(t, err)
+		assert.Equal(t, "job-new", pod.Name)
+	})
+
+	t.Run("pod from the provider status", func(t *testing.T) {
+		di := di.DeepCopy()
+		di.Status.ProviderStatus = &runtime.RawExtension{Raw: []byte(`{"podStatus":{"podName":"job-old"}}`)}
+		pod, err := findContainerPod(ctx, hostClient, di)
+		assert.NoError(t, err)
+		assert.Equal(t, "job-old", pod.Name)
+		assert.Equal(t, "ls-system", pod.Namespace)
+	})
+}
+
+func TestContainerStarted(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: container.InitContainerName, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: container.MainContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: container.WaitContainerName, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+			},
+		},
+	}
+	assert.True(t, containerStarted(pod, container.InitContainerName))
+	assert.True(t, containerStarted(pod, container.MainContainerName))
+	assert.False(t, containerStarted(pod, container.WaitContainerName))
+	assert.False(t, containerStarted(pod, "unknown"))
+}
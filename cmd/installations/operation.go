@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	lsv1alpha1helper "github.com/gardener/landscaper/apis/core/v1alpha1/helper"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+const operationPollInterval = time.Second
+
+type operationOptions struct {
+	kubeconfig       string
+	installationName string
+	namespace        string
+
+	operation lsv1alpha1.Operation
+	recursive bool
+	timeout   time.Duration
+}
+
+// NewReconcileCommand triggers the reconciliation of an installation.
+func NewReconcileCommand(ctx context.Context) *cobra.Command {
+	return newOperationCommand(ctx, lsv1alpha1.ReconcileOperation,
+		"Triggers the reconciliation of an installation.")
+}
+
+// NewForceReconcileCommand triggers the reconciliation of an installation without waiting for running children.
+func NewForceReconcileCommand(ctx context.Context) *cobra.Command {
+	return newOperationCommand(ctx, lsv1alpha1.ForceReconcileOperation,
+		"Triggers the reconciliation of an installation without waiting for its sub-installations and executions to be completed.")
+}
+
+// NewAbortCommand aborts the running operations of an installation.
+func NewAbortCommand(ctx context.Context) *cobra.Command {
+	return newOperationCommand(ctx, lsv1alpha1.AbortOperation,
+		"Aborts an installation and all its currently running children.")
+}
+
+func newOperationCommand(ctx context.Context, operation lsv1alpha1.Operation, short string) *cobra.Command {
+	opts := &operationOptions{
+		operation: operation,
+	}
+	cmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s [installation-name] [--recursive] [--namespace namespace] [--kubeconfig kubeconfig.yaml]", operation),
+		Args:    cobra.ExactArgs(1),
+		Example: fmt.Sprintf("landscaper-cli installations %s my-installation --recursive", operation),
+		Short:   operationShortDescription(short, operation),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.validateArgs(args); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// operationShortDescription returns the short description of the command for the operation.
+func operationShortDescription(short string, operation lsv1alpha1.Operation) string {
+	if operation == lsv1alpha1.AbortOperation {
+		return short + fmt.Sprintf(" Sets the annotation %s=%s and waits until the landscaper has started to abort, i.e. until the phase or the last reconcile time of the objects has changed.",
+			lsv1alpha1.OperationAnnotation, operation)
+	}
+	return short + fmt.Sprintf(" Sets the annotation %s=%s and waits until the operation has been picked up by the landscaper.",
+		lsv1alpha1.OperationAnnotation, operation)
+}
+
+func (o *operationOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot collect installation: %w", err)
+	}
+
+	objects := operationObjects(installationTrees[0], o.recursive)
+	for _, obj := range objects {
+		if err := setOperation(ctx, k8sClient, obj.object, o.operation); err != nil {
+			return fmt.Errorf("cannot set operation %s on %s %s: %w", o.operation, obj.kind, obj.object.GetName(), err)
+		}
+		cmd.Printf("- set operation %s on %s %s\n", o.operation, obj.kind, obj.object.GetName())
+	}
+
+	if o.timeout == 0 {
+		return nil
+	}
+	return waitForOperation(ctx, cmd, k8sClient, objects, o.operation, o.timeout)
+}
+
+// operationObjects returns the objects of the installation tree that get the operation annotation:
+// the installation and, if recursive is set, all its sub-installations and deployItems.
+func operationObjects(installationTree *inspect.InstallationTree, recursive bool) []treeObject {
+	objects := flattenInstallationTree(installationTree)
+	if !recursive {
+		// the installation itself is the last object of the flattened tree
+		return objects[len(objects)-1:]
+	}
+
+	filtered := []treeObject{}
+	for _, obj := range objects {
+		// executions get the operations from their installations
+		if obj.kind != "execution" {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+// setOperation sets the operation annotation on the object.
+// DeployItems additionally get the timestamp annotation that is used to detect timeouts of the operation.
+func setOperation(ctx context.Context, k8sClient client.Client, obj client.Object, operation lsv1alpha1.Operation) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+
+	switch o := obj.(type) {
+	case *lsv1alpha1.Installation:
+		lsv1alpha1helper.SetOperation(&o.ObjectMeta, operation)
+	case *lsv1alpha1.DeployItem:
+		if operation == lsv1alpha1.AbortOperation {
+			lsv1alpha1helper.SetAbortOperationAndTimestamp(&o.ObjectMeta)
+		} else {
+			lsv1alpha1helper.SetOperation(&o.ObjectMeta, operation)
+			lsv1alpha1helper.SetTimestampAnnotationNow(&o.ObjectMeta, lsv1alpha1helper.ReconcileTimestamp)
+		}
+	default:
+		return fmt.Errorf("operations cannot be set on objects of type %T", obj)
+	}
+
+	return k8sClient.Patch(ctx, obj, patch)
+}
+
+// waitForOperation waits until the landscaper has picked up the operation on all objects.
+// The objects must contain the status from the time the operation was set, which is used to detect aborts.
+func waitForOperation(ctx context.Context, cmd *cobra.Command, k8sClient client.Client, objects []treeObject, operation lsv1alpha1.Operation, timeout time.Duration) error {
+	pending := objects
+	err := wait.PollImmediate(operationPollInterval, timeout, func() (bool, error) {
+		stillPending := []treeObject{}
+		for _, obj := range pending {
+			picked, err := operationPickedUp(ctx, k8sClient, obj.object, operation)
+			if err != nil {
+				return false, fmt.Errorf("cannot get %s %s: %w", obj.kind, obj.object.GetName(), err)
+			}
+			if picked {
+				cmd.Printf("- operation %s picked up by %s %s\n", operation, obj.kind, obj.object.GetName())
+			} else {
+				stillPending = append(stillPending, obj)
+			}
+		}
+		pending = stillPending
+		return len(pending) == 0, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		names := []string{}
+		for _, obj := range pending {
+			names = append(names, fmt.Sprintf("%s %s", obj.kind, obj.object.GetName()))
+		}
+		return fmt.Errorf("operation %s has not been picked up within %s by: %s", operation, timeout, strings.Join(names, ", "))
+	}
+	return err
+}
+
+// operationPickedUp returns true if the operation annotation has been removed from the object.
+// The landscaper keeps the abort annotation, so aborts are detected by a changed status instead.
+func operationPickedUp(ctx context.Context, k8sClient client.Client, obj client.Object, operation lsv1alpha1.Operation) (bool, error) {
+	// the object is read into a new instance, since the removed annotation would be kept when decoding into obj
+	current := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if current.GetAnnotations()[lsv1alpha1.OperationAnnotation] != string(operation) {
+		return true, nil
+	}
+	if operation == lsv1alpha1.AbortOperation {
+		return abortPickedUp(obj, current), nil
+	}
+	return false, nil
+}
+
+// abortPickedUp returns true if the status of the object has changed since the abort operation has been set.
+// Installations are picked up when they are aborted or change their phase,
+// deployItems when they change their phase or last reconcile time.
+func abortPickedUp(before, current client.Object) bool {
+	switch b := before.(type) {
+	case *lsv1alpha1.Installation:
+		c := current.(*lsv1alpha1.Installation)
+		return c.Status.Phase == lsv1alpha1.ComponentPhaseAborted || c.Status.Phase != b.Status.Phase
+	case *lsv1alpha1.DeployItem:
+		c := current.(*lsv1alpha1.DeployItem)
+		return c.Status.Phase != b.Status.Phase || !reflect.DeepEqual(c.Status.LastReconcileTime, b.Status.LastReconcileTime)
+	}
+	return false
+}
+
+func (o *operationOptions) validateArgs(args []string) error {
+	o.installationName = args[0]
+
+	if o.timeout < 0 {
+		return fmt.Errorf("the timeout must not be negative")
+	}
+	return nil
+}
+
+func (o *operationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation. Required if --kubeconfig is used.")
+	fs.BoolVarP(&o.recursive, "recursive", "r", false, "set the operation also on all sub-installations and deployItems of the installation.")
+	fs.DurationVar(&o.timeout, "timeout", 2*time.Minute, "the maximum time to wait until the operation has been picked up. If set to 0, the command does not wait.")
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+)
+
+func TestOperation(t *testing.T) {
+	ctx := context.Background()
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./inspect/testdata")
+	assert.NoError(t, err)
+
+	collector := inspect.Collector{
+		K8sClient: fakeClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("my-aggregation", "inttest")
+	assert.NoError(t, err)
+
+	t.Run("only the installation is annotated by default", func(t *testing.T) {
+		objects := operationObjects(installationTrees[0], false)
+		assert.Len(t, objects, 1)
+		assert.Equal(t, "my-aggregation", objects[0].object.GetName())
+	})
+
+	t.Run("sub-installations and deploy items are annotated recursively", func(t *testing.T) {
+		objects := operationObjects(installationTrees[0], true)
+		names := []string{}
+		for _, obj := range objects {
+			names = append(names, obj.kind+" "+obj.object.GetName())
+		}
+		assert.Equal(t, []string{
+			"deployItem ingress-hhsjf-deploy-kptjl",
+			"installation ingress-hhsjf",
+			"deployItem server-gw64l-deploy-7mhc2",
+			"installation server-gw64l",
+			"installation my-aggregation",
+		}, names)
+	})
+
+	t.Run("operation annotations are set and picked up", func(t *testing.T) {
+		objects := operationObjects(installationTrees[0], true)
+		for _, obj := range objects {
+			assert.NoError(t, setOperation(ctx, fakeClient, obj.object, lsv1alpha1.AbortOperation))
+		}
+
+		deployItem := &lsv1alpha1.DeployItem{}
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "server-gw64l-deploy-7mhc2", Namespace: "inttest"}, deployItem))
+		assert.Equal(t, "abort", deployItem.Annotations[lsv1alpha1.OperationAnnotation])
+		assert.Contains(t, deployItem.Annotations, lsv1alpha1.AbortTimestampAnnotation)
+
+		output := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(output)
+		err := waitForOperation(ctx, cmd, fakeClient, objects[len(objects)-1:], lsv1alpha1.AbortOperation, time.Millisecond)
+		assert.EqualError(t, err, "operation abort has not been picked up within 1ms by: installation my-aggregation")
+
+		// the landscaper keeps the abort annotation and only changes the status
+		installation := &lsv1alpha1.Installation{}
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "my-aggregation", Namespace: "inttest"}, installation))
+		installation.Status.Phase = lsv1alpha1.ComponentPhaseAborted
+		assert.NoError(t, fakeClient.Update(ctx, installation))
+
+		assert.NoError(t, waitForOperation(ctx, cmd, fakeClient, objects[len(objects)-1:], lsv1alpha1.AbortOperation, time.Second))
+		assert.Equal(t, "- operation abort picked up by installation my-aggregation\n", output.String())
+
+		output.Reset()
+		deployItems := objects[2:3]
+		assert.Equal(t, "server-gw64l-deploy-7mhc2", deployItems[0].object.GetName())
+		err = waitForOperation(ctx, cmd, fakeClient, deployItems, lsv1alpha1.AbortOperation, time.Millisecond)
+		assert.EqualError(t, err, "operation abort has not been picked up within 1ms by: deployItem server-gw64l-deploy-7mhc2")
+
+		deployItem = &lsv1alpha1.DeployItem{}
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "server-gw64l-deploy-7mhc2", Namespace: "inttest"}, deployItem))
+		now := metav1.Now()
+		deployItem.Status.LastReconcileTime = &now
+		assert.NoError(t, fakeClient.Update(ctx, deployItem))
+
+		assert.NoError(t, waitForOperation(ctx, cmd, fakeClient, deployItems, lsv1alpha1.AbortOperation, time.Second))
+		assert.Equal(t, "- operation abort picked up by deployItem server-gw64l-deploy-7mhc2\n", output.String())
+	})
+
+	t.Run("reconcile is picked up when the annotation is removed", func(t *testing.T) {
+		installation := &lsv1alpha1.Installation{}
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "ingress-hhsjf", Namespace: "inttest"}, installation))
+		assert.NoError(t, setOperation(ctx, fakeClient, installation, lsv1alpha1.ReconcileOperation))
+		objects := []treeObject{{kind: "installation", object: installation}}
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(&bytes.Buffer{})
+		err := waitForOperation(ctx, cmd, fakeClient, objects, lsv1alpha1.ReconcileOperation, time.Millisecond)
+		assert.EqualError(t, err, "operation reconcile has not been picked up within 1ms by: installation ingress-hhsjf")
+
+		current := &lsv1alpha1.Installation{}
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "ingress-hhsjf", Namespace: "inttest"}, current))
+		delete(current.Annotations, lsv1alpha1.OperationAnnotation)
+		assert.NoError(t, fakeClient.Update(ctx, current))
+		assert.NoError(t, waitForOperation(ctx, cmd, fakeClient, objects, lsv1alpha1.ReconcileOperation, time.Second))
+	})
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/deployitems"
+	"github.com/gardener/landscapercli/pkg/health"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+type resourcesOptions struct {
+	kubeconfig       string
+	installationName string
+	namespace        string
+}
+
+// NewResourcesCommand shows the resources that are deployed by an installation together with their health.
+func NewResourcesCommand(ctx context.Context) *cobra.Command {
+	opts := &resourcesOptions{}
+	cmd := &cobra.Command{
+		Use:     "resources [installation-name] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli installations resources my-installation",
+		Short: "Displays the kubernetes resources that are managed by the helm and manifest deployItems of an installation and its sub-installations. " +
+			"The resources are read from the target clusters of the deployItems and checked for their existence and readiness.",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.installationName = args[0]
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *resourcesOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot collect installation: %w", err)
+	}
+
+	checker := newResourceChecker(k8sClient)
+	statuses := checker.checkInstallationTree(ctx, installationTrees[0])
+	if len(statuses) == 0 {
+		cmd.Printf("Installation %s has no resources that are managed by helm or manifest deployItems\n", o.installationName)
+		return nil
+	}
+	return printResourceStatuses(cmd.OutOrStdout(), statuses)
+}
+
+// resourceStatus is the health of a resource that is managed by a deployItem.
+type resourceStatus struct {
+	deployItem string
+	resource   corev1.ObjectReference
+	health.Result
+}
+
+// resourceChecker checks the resources of deployItems in their target clusters.
+type resourceChecker struct {
+	k8sClient client.Client
+	// newTargetClient creates a client for the cluster of a kubeconfig.
+	newTargetClient func(kubeconfig []byte) (client.Client, error)
+	// targetClients caches the clients by target.
+	targetClients map[client.ObjectKey]client.Client
+}
+
+func newResourceChecker(k8sClient client.Client) *resourceChecker {
+	return &resourceChecker{
+		k8sClient: k8sClient,
+		newTargetClient: func(kubeconfig []byte) (client.Client, error) {
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				return nil, err
+			}
+			return client.New(cfg, client.Options{})
+		},
+		targetClients: map[client.ObjectKey]client.Client{},
+	}
+}
+
+// checkInstallationTree checks the resources of all helm and manifest deployItems of the installation tree.
+// Resources whose target cluster cannot be accessed are reported with an unknown status.
+func (c *resourceChecker) checkInstallationTree(ctx context.Context, installationTree *inspect.InstallationTree) []*resourceStatus {
+	statuses := []*resourceStatus{}
+	for _, obj := range flattenInstallationTree(installationTree) {
+		di, ok := obj.object.(*lsv1alpha1.DeployItem)
+		if !ok || (di.Spec.Type != deployitems.HelmDeployItemType && di.Spec.Type != deployitems.ManifestDeployItemType) {
+			continue
+		}
+		statuses = append(statuses, c.checkDeployItem(ctx, di)...)
+	}
+	return statuses
+}
+
+func (c *resourceChecker) checkDeployItem(ctx context.Context, di *lsv1alpha1.DeployItem) []*resourceStatus {
+	managedResources, err := inspect.DecodeManagedResources(di)
+	if err != nil {
+		err = fmt.Errorf("unable to parse provider status: %w", err)
+		return []*resourceStatus{{deployItem: di.Name, Result: health.Result{Status: health.Unknown, Message: err.Error()}}}
+	}
+	if len(managedResources) == 0 {
+		return nil
+	}
+
+	statuses := []*resourceStatus{}
+	targetClient, err := c.targetClient(ctx, di)
+	for _, resource := range managedResources {
+		resourceStatus := &resourceStatus{deployItem: di.Name, resource: resource}
+		if err != nil {
+			resourceStatus.Result = health.Result{Status: health.Unknown, Message: err.Error()}
+		} else {
+			resourceStatus.Result = checkResource(ctx, targetClient, resource)
+		}
+		statuses = append(statuses, resourceStatus)
+	}
+	return statuses
+}
+
+// targetClient returns the client for the target cluster of a deployItem.
+func (c *resourceChecker) targetClient(ctx context.Context, di *lsv1alpha1.DeployItem) (client.Client, error) {
+	if di.Spec.Target == nil {
+		return nil, fmt.Errorf("deployItem %s has no target", di.Name)
+	}
+	key := client.ObjectKey{Name: di.Spec.Target.Name, Namespace: di.Spec.Target.Namespace}
+	if len(key.Namespace) == 0 {
+		key.Namespace = di.Namespace
+	}
+	if targetClient, ok := c.targetClients[key]; ok {
+		return targetClient, nil
+	}
+
+	target := &lsv1alpha1.Target{}
+	if err := c.k8sClient.Get(ctx, key, target); err != nil {
+		return nil, fmt.Errorf("cannot get target %s: %w", key.Name, err)
+	}
+	kubeconfig, err := util.GetKubeconfigFromTarget(ctx, c.k8sClient, target)
+	if err != nil {
+		return nil, err
+	}
+	targetClient, err := c.newTargetClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build client for target %s: %w", key.Name, err)
+	}
+	c.targetClients[key] = targetClient
+	return targetClient, nil
+}
+
+// checkResource reads a resource from the target cluster and checks its readiness.
+func checkResource(ctx context.Context, targetClient client.Client, resource corev1.ObjectReference) health.Result {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(resource.APIVersion)
+	obj.SetKind(resource.Kind)
+	if err := targetClient.Get(ctx, client.ObjectKey{Name: resource.Name, Namespace: resource.Namespace}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return health.Result{Status: health.Missing}
+		}
+		return health.Result{Status: health.Unknown, Message: err.Error()}
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		return health.Result{Status: health.NotReady, Message: "resource is being deleted"}
+	}
+	return health.Check(obj)
+}
+
+func printResourceStatuses(w io.Writer, statuses []*resourceStatus) error {
+	counts := map[health.Status]int{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEPLOYITEM\tRESOURCE\tSTATUS\tMESSAGE")
+	for _, status := range statuses {
+		resource := "-"
+		if len(status.resource.Kind) != 0 {
+			resource = fmt.Sprintf("%s/%s %s", status.resource.APIVersion, status.resource.Kind, namespacedResourceName(status.resource))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", status.deployItem, resource, status.Status, status.Message)
+		counts[status.Status]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d resources: %d ready, %d not ready, %d missing, %d unknown\n",
+		len(statuses), counts[health.Ready], counts[health.NotReady], counts[health.Missing], counts[health.Unknown])
+	return err
+}
+
+func namespacedResourceName(resource corev1.ObjectReference) string {
+	if len(resource.Namespace) == 0 {
+		return resource.Name
+	}
+	return fmt.Sprintf("%s/%s", resource.Namespace, resource.Name)
+}
+
+func (o *resourcesOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation. Required if --kubeconfig is used.")
+}
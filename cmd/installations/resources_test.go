@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/health"
+)
+
+func TestCheckInstallationResources(t *testing.T) {
+	ctx := context.Background()
+
+	k8sClient, _, err := envtest.NewFakeClientFromPath("./testdata/resources")
+	assert.NoError(t, err)
+
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("root", "resourcestest")
+	assert.NoError(t, err)
+
+	replicas := int32(2)
+	targetClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "config", Namespace: "default"}},
+		&appsv1.Deployment{
+			ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 1},
+		},
+	).Build()
+
+	checker := newResourceChecker(k8sClient)
+	checker.newTargetClient = func(kubeconfig []byte) (client.Client, error) {
+		assert.Equal(t, "target-kubeconfig", string(kubeconfig))
+		return targetClient, nil
+	}
+
+	statuses := checker.checkInstallationTree(ctx, installationTrees[0])
+	assert.Len(t, statuses, 5)
+
+	assert.Equal(t, "manifest", statuses[0].deployItem)
+	assert.Equal(t, "config", statuses[0].resource.Name)
+	assert.Equal(t, health.Ready, statuses[0].Status)
+
+	assert.Equal(t, "deleted", statuses[1].resource.Name)
+	assert.Equal(t, health.Missing, statuses[1].Status)
+
+	// the resources are checked although the helm config of the deployItem cannot be decoded
+	assert.Equal(t, "chart", statuses[2].deployItem)
+	assert.Equal(t, health.Result{Status: health.NotReady, Message: "1 of 2 replicas available"}, statuses[2].Result)
+
+	assert.Equal(t, health.Result{Status: health.Unknown, Message: "deployItem no-target has no target"}, statuses[3].Result)
+
+	assert.Equal(t, "unknown-target", statuses[4].deployItem)
+	assert.Equal(t, health.Unknown, statuses[4].Status)
+	assert.Contains(t, statuses[4].Message, "cannot get target unknown")
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printResourceStatuses(out, statuses))
+	assert.Contains(t, out.String(), "chart           apps/v1/Deployment default/app  NotReady  1 of 2 replicas available")
+	assert.Contains(t, out.String(), "5 resources: 1 ready, 1 not ready, 1 missing, 2 unknown")
+}
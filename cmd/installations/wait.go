@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+const (
+	WAIT_FOR_SUCCEEDED = "succeeded"
+	WAIT_FOR_DELETED   = "deleted"
+
+	waitPollInterval = 5 * time.Second
+)
+
+type waitOptions struct {
+	kubeconfig       string
+	installationName string
+	namespace        string
+
+	waitFor string
+	timeout time.Duration
+}
+
+func NewWaitCommand(ctx context.Context) *cobra.Command {
+	opts := &waitOptions{}
+	cmd := &cobra.Command{
+		Use:     "wait [installation-name] [--for succeeded|deleted] [--timeout 10m] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli installations wait my-installation --for=succeeded --timeout 10m",
+		Short: "Waits until an installation and all its sub-installations, executions and deployItems have succeeded " +
+			"or until the installation has been deleted. Fails as soon as one of them fails.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.validateArgs(args); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+			cmd.Printf("Installation %s %s\n", opts.installationName, opts.waitFor)
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *waitOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	key := client.ObjectKey{Name: o.installationName, Namespace: o.namespace}
+	return waitForInstallation(ctx, cmd, k8sClient, key, o.waitFor, o.timeout)
+}
+
+// waitForInstallation polls the installation tree until the installation has reached the awaited state
+// and reports the progress. It fails as soon as one of the objects of the tree fails.
+func waitForInstallation(ctx context.Context, cmd *cobra.Command, k8sClient client.Client, key client.ObjectKey, waitFor string, timeout time.Duration) error {
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	lastProgress := ""
+
+	err := wait.PollImmediate(waitPollInterval, timeout, func() (bool, error) {
+		if err := k8sClient.Get(ctx, key, &lsv1alpha1.Installation{}); err != nil {
+			if apierrors.IsNotFound(err) && waitFor == WAIT_FOR_DELETED {
+				return true, nil
+			}
+			return false, fmt.Errorf("cannot get installation %s: %w", key.Name, err)
+		}
+
+		installationTrees, err := collector.CollectInstallationsInCluster(key.Name, key.Namespace)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// objects of the tree are created or deleted in the meantime
+				return false, nil
+			}
+			return false, fmt.Errorf("cannot collect installation: %w", err)
+		}
+
+		done, progress, err := checkInstallationTree(installationTrees[0], waitFor)
+		if err != nil {
+			return false, err
+		}
+		if progress != lastProgress {
+			cmd.Printf("Waiting for installation %s to be %s: %s\n", key.Name, waitFor, progress)
+			lastProgress = progress
+		}
+		return done, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("timed out after %s waiting for installation %s to be %s", timeout, key.Name, waitFor)
+	}
+	return err
+}
+
+// treeObject is an installation, execution or deployItem of an installation tree.
+type treeObject struct {
+	kind      string
+	object    client.Object
+	phase     string
+	lastError *lsv1alpha1.Error
+	// upToDate is true if the status belongs to the current generation of the object.
+	upToDate bool
+}
+
+// flattenInstallationTree returns the deployItems, executions and installations of an installation tree.
+// Objects deeper in the tree come first, so that the root cause of a failure is found first.
+func flattenInstallationTree(installationTree *inspect.InstallationTree) []treeObject {
+	objects := []treeObject{}
+	if installationTree == nil {
+		return objects
+	}
+
+	if installationTree.Execution != nil {
+		for _, di := range installationTree.Execution.DeployItems {
+			objects = append(objects, treeObject{
+				kind:      "deployItem",
+				object:    di.DeployItem,
+				phase:     string(di.DeployItem.Status.Phase),
+				lastError: di.DeployItem.Status.LastError,
+				upToDate:  di.DeployItem.Status.ObservedGeneration == di.DeployItem.Generation,
+			})
+		}
+		exec := installationTree.Execution.Execution
+		objects = append(objects, treeObject{
+			kind:      "execution",
+			object:    exec,
+			phase:     string(exec.Status.Phase),
+			lastError: exec.Status.LastError,
+			upToDate:  exec.Status.ObservedGeneration == exec.Generation,
+		})
+	}
+
+	for _, subInst := range installationTree.SubInstallations {
+		objects = append(objects, flattenInstallationTree(subInst)...)
+	}
+
+	inst := installationTree.Installation
+	objects = append(objects, treeObject{
+		kind:      "installation",
+		object:    inst,
+		phase:     string(inst.Status.Phase),
+		lastError: inst.Status.LastError,
+		upToDate:  inst.Status.ObservedGeneration == inst.Generation,
+	})
+	return objects
+}
+
+// checkInstallationTree checks whether all objects of an installation tree have reached the awaited state.
+// It returns a description of the progress and an error if one of the objects has failed.
+func checkInstallationTree(installationTree *inspect.InstallationTree, waitFor string) (bool, string, error) {
+	objects := flattenInstallationTree(installationTree)
+
+	for _, obj := range objects {
+		if obj.phase != string(lsv1alpha1.ComponentPhaseFailed) || !obj.upToDate {
+			continue
+		}
+		if waitFor == WAIT_FOR_DELETED && obj.object.GetDeletionTimestamp() == nil {
+			// only the failures of the deletion are of interest
+			continue
+		}
+		if obj.lastError != nil {
+			return false, "", fmt.Errorf("%s %s failed: %s", obj.kind, obj.object.GetName(), obj.lastError.Message)
+		}
+		return false, "", fmt.Errorf("%s %s failed", obj.kind, obj.object.GetName())
+	}
+
+	if waitFor == WAIT_FOR_DELETED {
+		return false, fmt.Sprintf("%d objects remaining", len(objects)), nil
+	}
+
+	succeeded := 0
+	for _, obj := range objects {
+		if obj.phase == string(lsv1alpha1.ComponentPhaseSucceeded) && obj.upToDate {
+			succeeded++
+		}
+	}
+	return succeeded == len(objects), fmt.Sprintf("%d/%d objects succeeded", succeeded, len(objects)), nil
+}
+
+func (o *waitOptions) validateArgs(args []string) error {
+	o.installationName = args[0]
+
+	if o.waitFor != WAIT_FOR_SUCCEEDED && o.waitFor != WAIT_FOR_DELETED {
+		return fmt.Errorf("invalid option for '--for' flag: %q. Valid values are %s and %s", o.waitFor, WAIT_FOR_SUCCEEDED, WAIT_FOR_DELETED)
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("the timeout must be greater than zero")
+	}
+	return nil
+}
+
+func (o *waitOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation. Required if --kubeconfig is used.")
+	fs.StringVar(&o.waitFor, "for", WAIT_FOR_SUCCEEDED, fmt.Sprintf("the state to wait for. Valid values are %s and %s.", WAIT_FOR_SUCCEEDED, WAIT_FOR_DELETED))
+	fs.DurationVar(&o.timeout, "timeout", 10*time.Minute, "the maximum time to wait.")
+}
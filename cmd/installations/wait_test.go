@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"testing"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+)
+
+func TestCheckInstallationTree(t *testing.T) {
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata/wait")
+	assert.NoError(t, err)
+
+	collector := inspect.Collector{
+		K8sClient: fakeClient,
+	}
+	// collectTree collects the succeeded installation tree of the testdata and sets the phases of
+	// the installations and of the execution and deployItem.
+	collectTree := func(instPhase lsv1alpha1.ComponentInstallationPhase, diPhase lsv1alpha1.ExecutionPhase, diError *lsv1alpha1.Error) *inspect.InstallationTree {
+		installationTrees, err := collector.CollectInstallationsInCluster("root", "waittest")
+		assert.NoError(t, err)
+		tree := installationTrees[0]
+		sub := tree.SubInstallations[0]
+		tree.Installation.Status.Phase = instPhase
+		sub.Installation.Status.Phase = instPhase
+		sub.Execution.Execution.Status.Phase = diPhase
+		sub.Execution.DeployItems[0].DeployItem.Status.Phase = diPhase
+		sub.Execution.DeployItems[0].DeployItem.Status.LastError = diError
+		return tree
+	}
+
+	t.Run("succeeded tree", func(t *testing.T) {
+		done, progress, err := checkInstallationTree(collectTree(lsv1alpha1.ComponentPhaseSucceeded, lsv1alpha1.ExecutionPhaseSucceeded, nil), WAIT_FOR_SUCCEEDED)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, "4/4 objects succeeded", progress)
+	})
+
+	t.Run("progressing tree", func(t *testing.T) {
+		done, progress, err := checkInstallationTree(collectTree(lsv1alpha1.ComponentPhaseProgressing, lsv1alpha1.ExecutionPhaseSucceeded, nil), WAIT_FOR_SUCCEEDED)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, "2/4 objects succeeded", progress)
+	})
+
+	t.Run("outdated status", func(t *testing.T) {
+		tree := collectTree(lsv1alpha1.ComponentPhaseSucceeded, lsv1alpha1.ExecutionPhaseSucceeded, nil)
+		tree.Installation.Generation = 3
+		done, _, err := checkInstallationTree(tree, WAIT_FOR_SUCCEEDED)
+		assert.NoError(t, err)
+		assert.False(t, done)
+	})
+
+	t.Run("failed deploy item", func(t *testing.T) {
+		diError := &lsv1alpha1.Error{Message: "chart not found"}
+		_, _, err := checkInstallationTree(collectTree(lsv1alpha1.ComponentPhaseFailed, lsv1alpha1.ExecutionPhaseFailed, diError), WAIT_FOR_SUCCEEDED)
+		assert.EqualError(t, err, "deployItem sub-di failed: chart not found")
+	})
+
+	t.Run("failures are ignored until the deletion", func(t *testing.T) {
+		tree := collectTree(lsv1alpha1.ComponentPhaseDeleting, lsv1alpha1.ExecutionPhaseFailed, nil)
+		done, progress, err := checkInstallationTree(tree, WAIT_FOR_DELETED)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, "4 objects remaining", progress)
+
+		now := v1.Now()
+		tree.SubInstallations[0].Execution.DeployItems[0].DeployItem.DeletionTimestamp = &now
+		_, _, err = checkInstallationTree(tree, WAIT_FOR_DELETED)
+		assert.EqualError(t, err, "deployItem sub-di failed")
+	})
+}
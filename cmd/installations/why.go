@@ -0,0 +1,432 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	lsv1alpha1helper "github.com/gardener/landscaper/apis/core/v1alpha1/helper"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+	"github.com/gardener/landscapercli/pkg/logger"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+type whyOptions struct {
+	kubeconfig       string
+	installationName string
+	namespace        string
+}
+
+// NewWhyCommand explains why an installation is not progressing.
+func NewWhyCommand(ctx context.Context) *cobra.Command {
+	opts := &whyOptions{}
+	cmd := &cobra.Command{
+		Use:     "why [installation-name] [--namespace namespace] [--kubeconfig kubeconfig.yaml]",
+		Args:    cobra.ExactArgs(1),
+		Example: "landscaper-cli installations why my-installation",
+		Short: "Explains why an installation is not progressing. Analyses the installation, its sub-installations, executions and deployItems " +
+			"together with their imports and prints what they are waiting for, e.g. exports of sibling installations or deployers.",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.installationName = args[0]
+
+			if err := opts.run(ctx, cmd, logger.Log); err != nil {
+				cmd.PrintErr(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+
+	opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *whyOptions) run(ctx context.Context, cmd *cobra.Command, log logr.Logger) error {
+	k8sClient, namespace, err := util.BuildKubeClientFromConfigOrCurrentClusterContext(o.kubeconfig, scheme)
+	if err != nil {
+		return fmt.Errorf("cannot build k8s client from config or current cluster context: %w", err)
+	}
+
+	if namespace != "" && o.namespace == "" {
+		o.namespace = namespace
+	}
+
+	if o.namespace == "" {
+		return fmt.Errorf("namespace was not defined. Use --namespace to specify a namespace")
+	}
+
+	collector := inspect.Collector{
+		K8sClient: k8sClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster(o.installationName, o.namespace)
+	if err != nil {
+		return fmt.Errorf("cannot collect installation: %w", err)
+	}
+
+	d, err := newDiagnoser(ctx, k8sClient, o.namespace)
+	if err != nil {
+		return err
+	}
+	if err := d.diagnoseInstallation(ctx, installationTrees[0]); err != nil {
+		return err
+	}
+
+	printDiagnoses(cmd, installationTrees[0].Installation, d.diagnoses)
+	return nil
+}
+
+// diagnosis contains the reasons why an object of an installation tree is not progressing.
+type diagnosis struct {
+	kind    string
+	name    string
+	phase   string
+	reasons []string
+}
+
+// diagnoser analyses installation trees.
+type diagnoser struct {
+	k8sClient client.Client
+	// installations contains all installations of the namespace. They are used to find the producers of imports.
+	installations []lsv1alpha1.Installation
+	// deployItemTypes contains the types of all deployer registrations. It is nil if there are no registrations,
+	// since deployers can also be installed without registration.
+	deployItemTypes map[lsv1alpha1.DeployItemType]bool
+
+	diagnoses []*diagnosis
+}
+
+func newDiagnoser(ctx context.Context, k8sClient client.Client, namespace string) (*diagnoser, error) {
+	installations := lsv1alpha1.InstallationList{}
+	if err := k8sClient.List(ctx, &installations, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("cannot list installations for namespace %s: %w", namespace, err)
+	}
+
+	d := &diagnoser{
+		k8sClient:     k8sClient,
+		installations: installations.Items,
+	}
+
+	// deployer registrations are cluster scoped and might not be readable, so they are only used if available
+	registrations := lsv1alpha1.DeployerRegistrationList{}
+	if err := k8sClient.List(ctx, &registrations); err == nil && len(registrations.Items) != 0 {
+		d.deployItemTypes = map[lsv1alpha1.DeployItemType]bool{}
+		for _, registration := range registrations.Items {
+			for _, deployItemType := range registration.Spec.DeployItemTypes {
+				d.deployItemTypes[deployItemType] = true
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *diagnoser) add(kind, name, phase string, reasons []string) {
+	if len(reasons) == 0 {
+		return
+	}
+	d.diagnoses = append(d.diagnoses, &diagnosis{kind: kind, name: name, phase: phase, reasons: reasons})
+}
+
+// diagnoseInstallation analyses the installation and, depth first, its sub-installations, execution and deployItems.
+func (d *diagnoser) diagnoseInstallation(ctx context.Context, installationTree *inspect.InstallationTree) error {
+	inst := installationTree.Installation
+	if !isSucceeded(string(inst.Status.Phase), inst.Status.ObservedGeneration, inst.Generation) {
+		reasons := installationReasons(inst)
+		importReasons, err := d.importReasons(ctx, inst)
+		if err != nil {
+			return err
+		}
+		d.add("installation", inst.Name, string(inst.Status.Phase), append(reasons, importReasons...))
+	}
+
+	for _, subInst := range installationTree.SubInstallations {
+		if err := d.diagnoseInstallation(ctx, subInst); err != nil {
+			return err
+		}
+	}
+
+	if installationTree.Execution != nil {
+		d.diagnoseExecution(installationTree.Execution)
+	}
+	return nil
+}
+
+// installationReasons returns the reasons found in the status of the installation.
+func installationReasons(inst *lsv1alpha1.Installation) []string {
+	reasons := []string{}
+	if operation, ok := inst.Annotations[lsv1alpha1.OperationAnnotation]; ok {
+		reasons = append(reasons, fmt.Sprintf("operation %s has not yet been picked up by the landscaper", operation))
+	}
+	if inst.Status.ObservedGeneration != inst.Generation {
+		reasons = append(reasons, fmt.Sprintf("the landscaper has not yet processed the latest changes (generation %d, observed generation %d)",
+			inst.Generation, inst.Status.ObservedGeneration))
+	}
+	reasons = append(reasons, errorReasons(string(inst.Status.Phase), inst.Status.LastError)...)
+	for _, condition := range inst.Status.Conditions {
+		if condition.Status == lsv1alpha1.ConditionFalse {
+			reasons = append(reasons, fmt.Sprintf("condition %s is False: %s", condition.Type, conditionMessage(condition)))
+		}
+	}
+	return reasons
+}
+
+// importReasons returns the reasons why the imports of an installation are not satisfied.
+func (d *diagnoser) importReasons(ctx context.Context, inst *lsv1alpha1.Installation) ([]string, error) {
+	reasons := []string{}
+	resolved := map[string]bool{}
+	for _, imp := range inst.Status.Imports {
+		resolved[imp.Name] = true
+	}
+
+	for _, imp := range inst.Spec.Imports.Data {
+		var (
+			importReasons []string
+			err           error
+		)
+		switch {
+		case len(imp.DataRef) != 0:
+			importReasons, err = d.referenceReasons(ctx, inst, imp.Name, "dataobject", imp.DataRef, &lsv1alpha1.DataObject{})
+		case imp.SecretRef != nil:
+			importReasons, err = d.objectReasons(ctx, inst, imp.Name, "secret", imp.SecretRef.ObjectReference, &corev1.Secret{})
+		case imp.ConfigMapRef != nil:
+			importReasons, err = d.objectReasons(ctx, inst, imp.Name, "configmap", imp.ConfigMapRef.ObjectReference, &corev1.ConfigMap{})
+		}
+		if err != nil {
+			return nil, err
+		}
+		reasons = append(reasons, importReasons...)
+		if len(importReasons) == 0 && !resolved[imp.Name] {
+			reasons = append(reasons, fmt.Sprintf("import %s has not yet been resolved by the landscaper", imp.Name))
+		}
+	}
+
+	for _, imp := range inst.Spec.Imports.Targets {
+		refs := imp.Targets
+		if len(imp.Target) != 0 {
+			refs = []string{imp.Target}
+		}
+		importReasons := []string{}
+		for _, ref := range refs {
+			targetReasons, err := d.referenceReasons(ctx, inst, imp.Name, "target", ref, &lsv1alpha1.Target{})
+			if err != nil {
+				return nil, err
+			}
+			importReasons = append(importReasons, targetReasons...)
+		}
+		reasons = append(reasons, importReasons...)
+		if len(importReasons) == 0 && !resolved[imp.Name] {
+			reasons = append(reasons, fmt.Sprintf("import %s has not yet been resolved by the landscaper", imp.Name))
+		}
+	}
+	return reasons, nil
+}
+
+// referenceReasons checks an imported data object or target and the installation that exports it.
+func (d *diagnoser) referenceReasons(ctx context.Context, inst *lsv1alpha1.Installation, importName, kind, ref string, obj client.Object) ([]string, error) {
+	instCtx := inspect.InstallationContext(inst)
+	key := client.ObjectKey{Name: lsv1alpha1helper.GenerateDataObjectName(instCtx, ref), Namespace: inst.Namespace}
+	exists := true
+	if err := d.k8sClient.Get(ctx, key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("cannot get %s %s: %w", kind, key.Name, err)
+		}
+		exists = false
+	}
+
+	if sibling := d.exportingSibling(inst, kind, ref); sibling != nil {
+		if !isSucceeded(string(sibling.Status.Phase), sibling.Status.ObservedGeneration, sibling.Generation) {
+			return []string{fmt.Sprintf("waiting for export %s from sibling %s which is %s",
+				ref, sibling.Name, phaseWithError(string(sibling.Status.Phase), sibling.Status.LastError))}, nil
+		}
+		if !exists {
+			return []string{fmt.Sprintf("export %s of sibling %s does not exist although %s has succeeded", ref, sibling.Name, sibling.Name)}, nil
+		}
+		return nil, nil
+	}
+
+	if exists {
+		return nil, nil
+	}
+	if parent := d.importingParent(inst, kind, ref); parent != nil {
+		return []string{fmt.Sprintf("waiting for import %s of parent %s which is %s",
+			ref, parent.Name, phaseWithError(string(parent.Status.Phase), parent.Status.LastError))}, nil
+	}
+	return []string{fmt.Sprintf("import %s references %s %s which does not exist and is not exported by any installation", importName, kind, ref)}, nil
+}
+
+// objectReasons checks an imported secret or configmap.
+func (d *diagnoser) objectReasons(ctx context.Context, inst *lsv1alpha1.Installation, importName, kind string, ref lsv1alpha1.ObjectReference, obj client.Object) ([]string, error) {
+	key := client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}
+	if len(key.Namespace) == 0 {
+		key.Namespace = inst.Namespace
+	}
+	if err := d.k8sClient.Get(ctx, key, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("cannot get %s %s: %w", kind, key.Name, err)
+		}
+		return []string{fmt.Sprintf("import %s references %s %s/%s which does not exist", importName, kind, key.Namespace, key.Name)}, nil
+	}
+	return nil, nil
+}
+
+// exportingSibling returns the installation in the same context that exports the data object or target.
+func (d *diagnoser) exportingSibling(inst *lsv1alpha1.Installation, kind, ref string) *lsv1alpha1.Installation {
+	instCtx := inspect.InstallationContext(inst)
+	for i := range d.installations {
+		sibling := &d.installations[i]
+		if sibling.Name == inst.Name || inspect.InstallationContext(sibling) != instCtx {
+			continue
+		}
+		if kind == "target" {
+			for _, exp := range sibling.Spec.Exports.Targets {
+				if exp.Target == ref {
+					return sibling
+				}
+			}
+			continue
+		}
+		for _, exp := range sibling.Spec.Exports.Data {
+			if exp.DataRef == ref {
+				return sibling
+			}
+		}
+	}
+	return nil
+}
+
+// importingParent returns the parent installation if it imports the data object or target for its sub-installations.
+func (d *diagnoser) importingParent(inst *lsv1alpha1.Installation, kind, ref string) *lsv1alpha1.Installation {
+	for i := range d.installations {
+		parent := &d.installations[i]
+		if inspect.InstallationContext(inst) != lsv1alpha1helper.DataObjectSourceFromInstallationName(parent.Name) {
+			continue
+		}
+		if kind == "target" {
+			for _, imp := range parent.Spec.Imports.Targets {
+				if imp.Name == ref {
+					return parent
+				}
+			}
+			return nil
+		}
+		for _, imp := range parent.Spec.Imports.Data {
+			if imp.Name == ref {
+				return parent
+			}
+		}
+		if _, ok := parent.Spec.ImportDataMappings[ref]; ok {
+			return parent
+		}
+		return nil
+	}
+	return nil
+}
+
+func (d *diagnoser) diagnoseExecution(executionTree *inspect.ExecutionTree) {
+	exec := executionTree.Execution
+	if !isSucceeded(string(exec.Status.Phase), exec.Status.ObservedGeneration, exec.Generation) {
+		reasons := []string{}
+		if exec.Status.ObservedGeneration != exec.Generation {
+			reasons = append(reasons, fmt.Sprintf("the landscaper has not yet processed the latest changes (generation %d, observed generation %d)",
+				exec.Generation, exec.Status.ObservedGeneration))
+		}
+		reasons = append(reasons, errorReasons(string(exec.Status.Phase), exec.Status.LastError)...)
+		d.add("execution", exec.Name, string(exec.Status.Phase), reasons)
+	}
+
+	for _, di := range executionTree.DeployItems {
+		d.diagnoseDeployItem(di.DeployItem)
+	}
+}
+
+func (d *diagnoser) diagnoseDeployItem(di *lsv1alpha1.DeployItem) {
+	if isSucceeded(string(di.Status.Phase), di.Status.ObservedGeneration, di.Generation) {
+		return
+	}
+
+	reasons := []string{}
+	switch {
+	case len(di.Status.Deployer.Identity) == 0 && d.deployItemTypes != nil && !d.deployItemTypes[di.Spec.Type]:
+		reasons = append(reasons, fmt.Sprintf("no deployer registered for type %s", di.Spec.Type))
+	case len(di.Status.Deployer.Identity) == 0:
+		reasons = append(reasons, fmt.Sprintf("not yet picked up by a deployer for type %s", di.Spec.Type))
+	case di.Status.ObservedGeneration != di.Generation:
+		reasons = append(reasons, fmt.Sprintf("deployer %s has not yet processed the latest changes (generation %d, observed generation %d)",
+			di.Status.Deployer.Name, di.Generation, di.Status.ObservedGeneration))
+	case di.Status.Phase != lsv1alpha1.ExecutionPhaseFailed:
+		reasons = append(reasons, fmt.Sprintf("being processed by deployer %s %s", di.Status.Deployer.Name, di.Status.Deployer.Version))
+	}
+	reasons = append(reasons, errorReasons(string(di.Status.Phase), di.Status.LastError)...)
+	d.add("deployItem", di.Name, string(di.Status.Phase), reasons)
+}
+
+// errorReasons returns the last error of an object that has not succeeded.
+func errorReasons(phase string, lastError *lsv1alpha1.Error) []string {
+	if lastError == nil {
+		if phase == string(lsv1alpha1.ComponentPhaseFailed) {
+			return []string{"failed"}
+		}
+		return nil
+	}
+	if phase == string(lsv1alpha1.ComponentPhaseFailed) {
+		return []string{fmt.Sprintf("failed: %s", lastError.Message)}
+	}
+	return []string{fmt.Sprintf("last error in operation %s: %s", lastError.Operation, lastError.Message)}
+}
+
+func isSucceeded(phase string, observedGeneration, generation int64) bool {
+	return phase == string(lsv1alpha1.ComponentPhaseSucceeded) && observedGeneration == generation
+}
+
+func phaseWithError(phase string, lastError *lsv1alpha1.Error) string {
+	if len(phase) == 0 {
+		phase = "not yet processed"
+	}
+	if lastError == nil {
+		return phase
+	}
+	return fmt.Sprintf("%s: %s", phase, lastError.Message)
+}
+
+func conditionMessage(condition lsv1alpha1.Condition) string {
+	if len(condition.Message) == 0 {
+		return condition.Reason
+	}
+	return condition.Message
+}
+
+func printDiagnoses(cmd *cobra.Command, inst *lsv1alpha1.Installation, diagnoses []*diagnosis) {
+	if len(diagnoses) == 0 {
+		if isSucceeded(string(inst.Status.Phase), inst.Status.ObservedGeneration, inst.Generation) {
+			cmd.Printf("Installation %s has succeeded\n", inst.Name)
+		} else {
+			cmd.Printf("No reason found why installation %s is %s\n", inst.Name, phaseWithError(string(inst.Status.Phase), nil))
+		}
+		return
+	}
+
+	for _, diag := range diagnoses {
+		cmd.Printf("%s %s (%s):\n", diag.kind, diag.name, phaseWithError(diag.phase, nil))
+		for _, reason := range diag.reasons {
+			cmd.Printf("  - %s\n", reason)
+		}
+	}
+}
+
+func (o *whyOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig for the cluster. Required if the cluster is not the same as the current-context of kubectl.")
+	fs.StringVarP(&o.namespace, "namespace", "n", "", "namespace of the installation. Required if --kubeconfig is used.")
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package installations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/test/utils/envtest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	inspect "github.com/gardener/landscapercli/cmd/installations/inspect"
+)
+
+func TestWhy(t *testing.T) {
+	ctx := context.Background()
+	fakeClient, _, err := envtest.NewFakeClientFromPath("./testdata/why")
+	assert.NoError(t, err)
+
+	collector := inspect.Collector{
+		K8sClient: fakeClient,
+	}
+	installationTrees, err := collector.CollectInstallationsInCluster("app", "whytest")
+	assert.NoError(t, err)
+
+	diagnose := func() string {
+		d, err := newDiagnoser(ctx, fakeClient, "whytest")
+		assert.NoError(t, err)
+		assert.NoError(t, d.diagnoseInstallation(ctx, installationTrees[0]))
+
+		out := &bytes.Buffer{}
+		cmd := &cobra.Command{}
+		cmd.SetOut(out)
+		printDiagnoses(cmd, installationTrees[0].Installation, d.diagnoses)
+		return out.String()
+	}
+
+	t.Run("without deployer registrations", func(t *testing.T) {
+		assert.Equal(t, `installation app (PendingDependencies):
+  - waiting for export db-url from sibling database which is Failed: cannot connect to cloud provider
+  - import apiKey references dataobject api-key which does not exist and is not exported by any installation
+  - import credentials references secret whytest/app-credentials which does not exist
+installation app-web (Init):
+  - waiting for import domain of parent app which is PendingDependencies
+deployItem app-chart (Init):
+  - not yet picked up by a deployer for type landscaper.gardener.cloud/helm
+deployItem app-job (Progressing):
+  - being processed by deployer container-deployer v0.24.0
+  - last error in operation Reconcile: pod app-job-x8k2 is not ready
+`, diagnose())
+	})
+
+	t.Run("with deployer registrations", func(t *testing.T) {
+		registration := &lsv1alpha1.DeployerRegistration{
+			ObjectMeta: v1.ObjectMeta{Name: "container"},
+			Spec: lsv1alpha1.DeployerRegistrationSpec{
+				DeployItemTypes: []lsv1alpha1.DeployItemType{"landscaper.gardener.cloud/container"},
+			},
+		}
+		assert.NoError(t, fakeClient.Create(ctx, registration))
+
+		assert.Contains(t, diagnose(), `deployItem app-chart (Init):
+  - no deployer registered for type landscaper.gardener.cloud/helm
+`)
+	})
+}
+
+func TestPrintDiagnosesOfSucceededInstallation(t *testing.T) {
+	out := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOut(out)
+
+	inst := &lsv1alpha1.Installation{
+		ObjectMeta: v1.ObjectMeta{Name: "root", Generation: 2},
+		Status:     lsv1alpha1.InstallationStatus{Phase: lsv1alpha1.ComponentPhaseSucceeded, ObservedGeneration: 2},
+	}
+	printDiagnoses(cmd, inst, nil)
+	assert.Equal(t, "Installation root has succeeded\n", out.String())
+}
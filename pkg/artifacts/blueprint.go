@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/apis/mediatype"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscaper/pkg/utils/tar"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+// BlueprintLayerMediaType is the media type of the layer that contains the gzipped blueprint directory.
+var BlueprintLayerMediaType = mediatype.NewBuilder(mediatype.BlueprintArtifactsLayerMediaTypeV1).Compression(mediatype.GZipCompression).String()
+
+// BlueprintArtifact is a blueprint in the oci artifact format that is uploaded to a registry.
+// The config contains the blueprint definition and the only layer contains the gzipped blueprint directory.
+type BlueprintArtifact struct {
+	// Manifest is the oci manifest of the artifact.
+	Manifest *ocispecv1.Manifest
+	// ManifestData is the serialized manifest whose digest identifies the artifact.
+	ManifestData []byte
+	// Config is the config blob.
+	Config []byte
+	// Layer is the blueprint layer.
+	Layer []byte
+}
+
+// BuildBlueprint creates the artifact for the blueprint directory at path.
+// The layer is built reproducibly, so the same blueprint files always result in the same manifest digest.
+func BuildBlueprint(fs vfs.FileSystem, path string) (*BlueprintArtifact, error) {
+	data, err := vfs.ReadFile(fs, filepath.Join(path, lsv1alpha1.BlueprintFileName))
+	if err != nil {
+		return nil, err
+	}
+	blueprint := &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+		return nil, err
+	}
+	config, err := json.Marshal(blueprint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode blueprint config: %w", err)
+	}
+
+	var layer bytes.Buffer
+	if err := buildTarGzip(fs, path, &layer); err != nil {
+		return nil, fmt.Errorf("unable to build blueprint layer: %w", err)
+	}
+
+	manifest := &ocispecv1.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config: ocispecv1.Descriptor{
+			MediaType: mediatype.BlueprintArtifactsConfigMediaTypeV1,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+		Layers: []ocispecv1.Descriptor{
+			{
+				MediaType: BlueprintLayerMediaType,
+				Digest:    digest.FromBytes(layer.Bytes()),
+				Size:      int64(layer.Len()),
+			},
+		},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode manifest: %w", err)
+	}
+
+	return &BlueprintArtifact{
+		Manifest:     manifest,
+		ManifestData: manifestData,
+		Config:       config,
+		Layer:        layer.Bytes(),
+	}, nil
+}
+
+// NewBlueprintArtifact creates a blueprint artifact from a serialized manifest.
+// The config and the first layer are read with the given function and verified against the digests of the manifest.
+// Like landscaper, it accepts artifacts of other tools with other media types or further layers.
+// Use Validate to check that the artifact has the format that is built by BuildBlueprint.
+func NewBlueprintArtifact(manifestData []byte, readBlob func(desc ocispecv1.Descriptor) ([]byte, error)) (*BlueprintArtifact, error) {
+	manifest := &ocispecv1.Manifest{}
+	if err := json.Unmarshal(manifestData, manifest); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest: %w", err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("the blueprint artifact has no layer")
+	}
+
+	artifact := &BlueprintArtifact{
+		Manifest:     manifest,
+		ManifestData: manifestData,
+	}
+	var err error
+	if artifact.Config, err = readVerifiedBlob(manifest.Config, readBlob); err != nil {
+		return nil, err
+	}
+	if artifact.Layer, err = readVerifiedBlob(manifest.Layers[0], readBlob); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// Validate checks that the artifact has the config media type of blueprints and exactly one layer.
+func (a *BlueprintArtifact) Validate() error {
+	if a.Manifest.Config.MediaType != mediatype.BlueprintArtifactsConfigMediaTypeV1 {
+		return fmt.Errorf("the artifact is no blueprint: expected config media type %s but got %s",
+			mediatype.BlueprintArtifactsConfigMediaTypeV1, a.Manifest.Config.MediaType)
+	}
+	if len(a.Manifest.Layers) != 1 {
+		return fmt.Errorf("a blueprint artifact must have exactly one layer but has %d", len(a.Manifest.Layers))
+	}
+	return nil
+}
+
+func readVerifiedBlob(desc ocispecv1.Descriptor, readBlob func(desc ocispecv1.Descriptor) ([]byte, error)) ([]byte, error) {
+	data, err := readBlob(desc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read blob %s: %w", desc.Digest, err)
+	}
+	if err := VerifyBlob(desc, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// VerifyBlob checks that the data matches the digest and size of the descriptor.
+func VerifyBlob(desc ocispecv1.Descriptor, data []byte) error {
+	if int64(len(data)) != desc.Size {
+		return fmt.Errorf("size of blob %s does not match: expected %d but got %d", desc.Digest, desc.Size, len(data))
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(data); actual != desc.Digest {
+		return fmt.Errorf("digest of blob does not match: expected %s but got %s", desc.Digest, actual)
+	}
+	return nil
+}
+
+// ManifestDescriptor returns the descriptor of the manifest.
+func (a *BlueprintArtifact) ManifestDescriptor() ocispecv1.Descriptor {
+	return ocispecv1.Descriptor{
+		MediaType: ocispecv1.MediaTypeImageManifest,
+		Digest:    digest.FromBytes(a.ManifestData),
+		Size:      int64(len(a.ManifestData)),
+	}
+}
+
+// Get returns the config or layer blob of the given descriptor.
+// It implements the store of the oci client so that the artifact can be pushed.
+func (a *BlueprintArtifact) Get(desc ocispecv1.Descriptor) (io.ReadCloser, error) {
+	switch desc.Digest {
+	case a.Manifest.Config.Digest:
+		return ioutil.NopCloser(bytes.NewReader(a.Config)), nil
+	case a.Manifest.Layers[0].Digest:
+		return ioutil.NopCloser(bytes.NewReader(a.Layer)), nil
+	default:
+		return nil, fmt.Errorf("blob %s is not part of the blueprint artifact", desc.Digest)
+	}
+}
+
+// Extract writes the files of the blueprint layer to the directory path.
+// Like landscaper, it skips tar entries that are neither directories nor regular files.
+func (a *BlueprintArtifact) Extract(ctx context.Context, fs vfs.FileSystem, path string) error {
+	return tar.ExtractTarGzip(ctx, bytes.NewReader(a.Layer), fs, tar.ToPath(path))
+}
+
+// ExtractStrict writes the files of the blueprint layer to the directory path.
+// In contrast to Extract, it fails on tar entries that are neither directories nor regular files.
+func (a *BlueprintArtifact) ExtractStrict(ctx context.Context, fs vfs.FileSystem, path string) error {
+	return extractTarGzip(ctx, bytes.NewReader(a.Layer), fs, path)
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/opencontainers/go-digest"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/artifacts"
+)
+
+const testBlueprintPath = "./testdata/blueprint"
+
+func TestBuildBlueprintIsReproducible(t *testing.T) {
+	fs1 := copyTestBlueprint(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 0644)
+	fs2 := copyTestBlueprint(t, time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC), 0600)
+
+	artifact1, err := artifacts.BuildBlueprint(fs1, "/blueprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	artifact2, err := artifacts.BuildBlueprint(fs2, "/blueprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, artifact1.ManifestDescriptor().Digest, artifact2.ManifestDescriptor().Digest)
+	assert.Equal(t, artifact1.Layer, artifact2.Layer)
+	assert.Equal(t, artifacts.BlueprintLayerMediaType, artifact1.Manifest.Layers[0].MediaType)
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	artifact, err := artifacts.BuildBlueprint(osfs.New(), testBlueprintPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	archive1, archive2 := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.NoError(t, artifact.WriteArchive(archive1))
+	assert.NoError(t, artifact.WriteArchive(archive2))
+	assert.Equal(t, archive1.Bytes(), archive2.Bytes(), "expect reproducible archives")
+
+	read, err := artifacts.ReadArchive(context.TODO(), archive1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, artifact.ManifestData, read.ManifestData)
+	assert.Equal(t, artifact.Config, read.Config)
+
+	fs := memoryfs.New()
+	if !assert.NoError(t, read.ExtractStrict(context.TODO(), fs, "/out")) {
+		return
+	}
+	for _, file := range []string{"blueprint.yaml", "deploy-execution.yaml", "schemas/config.json"} {
+		expected, err := os.ReadFile(filepath.Join(testBlueprintPath, file))
+		if !assert.NoError(t, err) {
+			return
+		}
+		actual, err := vfs.ReadFile(fs, filepath.Join("/out", file))
+		if assert.NoError(t, err, file) {
+			assert.Equal(t, string(expected), string(actual), file)
+		}
+	}
+}
+
+func TestReadLayoutVerifiesDigests(t *testing.T) {
+	artifact, err := artifacts.BuildBlueprint(osfs.New(), testBlueprintPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fs := memoryfs.New()
+	if !assert.NoError(t, artifact.WriteLayout(fs, "/layout")) {
+		return
+	}
+
+	layer := artifact.Manifest.Layers[0].Digest
+	layerPath := filepath.Join("/layout", "blobs", layer.Algorithm().String(), layer.Encoded())
+	tampered := append([]byte{}, artifact.Layer...)
+	tampered[len(tampered)-1] ^= 0xff
+	if !assert.NoError(t, vfs.WriteFile(fs, layerPath, tampered, 0644)) {
+		return
+	}
+
+	_, err = artifacts.ReadLayout(fs, "/layout")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "digest of blob does not match")
+	}
+}
+
+// copyTestBlueprint copies the test blueprint into a memory filesystem with the given modification time and file mode.
+func copyTestBlueprint(t *testing.T, modTime time.Time, mode os.FileMode) vfs.FileSystem {
+	fs := memoryfs.New()
+	err := filepath.Walk(testBlueprintPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(testBlueprintPath, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join("/blueprint", relPath)
+		if info.IsDir() {
+			if err := fs.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		} else {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := vfs.WriteFile(fs, target, data, mode); err != nil {
+				return err
+			}
+		}
+		return fs.Chtimes(target, modTime, modTime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestAddToLayout(t *testing.T) {
+	artifact, err := artifacts.BuildBlueprint(osfs.New(), testBlueprintPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fs := memoryfs.New()
+	for _, ref := range []string{"example.com/blueprint:v1", "example.com/blueprint:v2", "example.com/blueprint:v1"} {
+		if !assert.NoError(t, artifact.AddToLayout(fs, "/layout", ref)) {
+			return
+		}
+	}
+
+	data, err := vfs.ReadFile(fs, "/layout/index.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	index := &ocispecv1.Index{}
+	if !assert.NoError(t, json.Unmarshal(data, index)) {
+		return
+	}
+	if assert.Len(t, index.Manifests, 2) {
+		assert.Equal(t, "example.com/blueprint:v2", index.Manifests[0].Annotations[ocispecv1.AnnotationRefName])
+		assert.Equal(t, "example.com/blueprint:v1", index.Manifests[1].Annotations[ocispecv1.AnnotationRefName])
+		assert.Equal(t, artifact.ManifestDescriptor().Digest, index.Manifests[1].Digest)
+	}
+}
+
+func TestAddToCTF(t *testing.T) {
+	artifact, err := artifacts.BuildBlueprint(osfs.New(), testBlueprintPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fs := memoryfs.New()
+	for _, component := range []string{"example.com/a", "example.com/b", "example.com/a"} {
+		err := artifact.AddToCTF(fs, "/transport.tar", artifacts.CTFResource{
+			ComponentName:    component,
+			ComponentVersion: "v1.0.0",
+			ResourceName:     "blueprint",
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	archive, err := ctf.NewCTF(fs, "/transport.tar")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer archive.Close()
+	components := []string{}
+	err = archive.Walk(func(ca *ctf.ComponentArchive) error {
+		components = append(components, ca.ComponentDescriptor.GetName())
+		if assert.Len(t, ca.ComponentDescriptor.Resources, 1) {
+			res := ca.ComponentDescriptor.Resources[0]
+			assert.Equal(t, "blueprint", res.GetName())
+			var buf bytes.Buffer
+			if _, err := ca.Resolve(context.TODO(), res, &buf); assert.NoError(t, err) {
+				assert.Equal(t, artifact.Layer, buf.Bytes())
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"example.com/a", "example.com/b"}, components)
+
+	_, _, err = artifacts.ParseComponentReference("example.com/a")
+	assert.Error(t, err)
+}
+
+func TestNewBlueprintArtifactAcceptsForeignArtifacts(t *testing.T) {
+	var layer bytes.Buffer
+	gw := gzip.NewWriter(&layer)
+	tw := tar.NewWriter(gw)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "blueprint.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err := tw.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "link.yaml", Typeflag: tar.TypeSymlink, Linkname: "blueprint.yaml"}))
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+
+	config := []byte("{}")
+	other := []byte("other")
+	blobs := map[digest.Digest][]byte{
+		digest.FromBytes(config):        config,
+		digest.FromBytes(layer.Bytes()): layer.Bytes(),
+		digest.FromBytes(other):         other,
+	}
+	manifest := &ocispecv1.Manifest{
+		Config: ocispecv1.Descriptor{MediaType: "application/json", Digest: digest.FromBytes(config), Size: int64(len(config))},
+		Layers: []ocispecv1.Descriptor{
+			{MediaType: "application/tar+gzip", Digest: digest.FromBytes(layer.Bytes()), Size: int64(layer.Len())},
+			{MediaType: "application/octet-stream", Digest: digest.FromBytes(other), Size: int64(len(other))},
+		},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	artifact, err := artifacts.NewBlueprintArtifact(manifestData, func(desc ocispecv1.Descriptor) ([]byte, error) {
+		return blobs[desc.Digest], nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualError(t, artifact.Validate(), "the artifact is no blueprint: expected config media type application/vnd.gardener.landscaper.blueprint.config.v1 but got application/json")
+
+	fs := memoryfs.New()
+	assert.NoError(t, artifact.Extract(context.TODO(), fs, "/"))
+	data, err := vfs.ReadFile(fs, "/blueprint.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	_, err = fs.Stat("/link.yaml")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.EqualError(t, artifact.ExtractStrict(context.TODO(), memoryfs.New(), "/"), `unsupported type of tar entry "link.yaml"`)
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	cdvalidation "github.com/gardener/component-spec/bindings-go/apis/v2/validation"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	"github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+)
+
+// CTFResource identifies the blueprint resource in a component of a ctf archive.
+type CTFResource struct {
+	// ComponentName is the name of the component that contains the blueprint.
+	ComponentName string
+	// ComponentVersion is the version of the component that contains the blueprint.
+	ComponentVersion string
+	// ResourceName is the name of the blueprint resource.
+	ResourceName string
+}
+
+// ParseComponentReference parses a component reference of the form "name:version".
+func ParseComponentReference(ref string) (name, version string, err error) {
+	i := strings.LastIndex(ref, ":")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", fmt.Errorf("invalid component reference %q: expected <component name>:<version>", ref)
+	}
+	return ref[:i], ref[i+1:], nil
+}
+
+// AddToCTF adds the blueprint as local blob resource to a component archive of the ctf archive at ctfPath.
+// The ctf archive is the transport format of the component-cli, i.e. a tar that contains a tar for every component archive.
+// The archive and the component are created if they do not exist yet, and an existing resource with the same name is replaced.
+func (a *BlueprintArtifact) AddToCTF(fs vfs.FileSystem, ctfPath string, res CTFResource) error {
+	entries, err := readCTF(fs, ctfPath)
+	if err != nil {
+		return fmt.Errorf("unable to read ctf archive %s: %w", ctfPath, err)
+	}
+
+	var (
+		ca       *ctf.ComponentArchive
+		filename string
+	)
+	for name, data := range entries {
+		archive, err := ctf.NewComponentArchiveFromTarReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("unable to read component archive %s of ctf archive %s: %w", name, ctfPath, err)
+		}
+		if archive.ComponentDescriptor.GetName() == res.ComponentName && archive.ComponentDescriptor.GetVersion() == res.ComponentVersion {
+			ca, filename = archive, name
+			break
+		}
+	}
+	if ca == nil {
+		cd, err := newComponentDescriptor(res.ComponentName, res.ComponentVersion)
+		if err != nil {
+			return err
+		}
+		ca = ctf.NewComponentArchive(cd, memoryfs.New())
+	}
+
+	layerDesc := a.Manifest.Layers[0]
+	resource := &cdv2.Resource{
+		IdentityObjectMeta: cdv2.IdentityObjectMeta{
+			Name:    res.ResourceName,
+			Version: res.ComponentVersion,
+			Type:    v1alpha1.BlueprintResourceType,
+		},
+		Relation: cdv2.LocalRelation,
+	}
+	info := ctf.BlobInfo{
+		MediaType: layerDesc.MediaType,
+		Digest:    layerDesc.Digest.String(),
+		Size:      layerDesc.Size,
+	}
+	if err := ca.AddResource(resource, info, bytes.NewReader(a.Layer)); err != nil {
+		return fmt.Errorf("unable to add blueprint resource to component archive: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ca.WriteTar(&buf); err != nil {
+		return fmt.Errorf("unable to write component archive: %w", err)
+	}
+	newFilename, err := ca.Digest()
+	if err != nil {
+		return err
+	}
+	delete(entries, filename)
+	entries[newFilename] = buf.Bytes()
+
+	return writeCTF(fs, ctfPath, entries)
+}
+
+func newComponentDescriptor(name, version string) (*cdv2.ComponentDescriptor, error) {
+	repoCtx, err := cdv2.NewUnstructured(cdv2.NewOCIRegistryRepository("", ""))
+	if err != nil {
+		return nil, err
+	}
+	cd := &cdv2.ComponentDescriptor{
+		Metadata: cdv2.Metadata{
+			Version: cdv2.SchemaVersion,
+		},
+		ComponentSpec: cdv2.ComponentSpec{
+			ObjectMeta: cdv2.ObjectMeta{
+				Name:    name,
+				Version: version,
+			},
+			RepositoryContexts:  []*cdv2.UnstructuredTypedObject{&repoCtx},
+			Provider:            cdv2.InternalProvider,
+			Sources:             []cdv2.Source{},
+			ComponentReferences: []cdv2.ComponentReference{},
+			Resources:           []cdv2.Resource{},
+		},
+	}
+	if err := cdvalidation.Validate(cd); err != nil {
+		return nil, fmt.Errorf("invalid component descriptor: %w", err)
+	}
+	return cd, nil
+}
+
+// readCTF reads the component archives of a ctf archive by their file name.
+// A ctf archive that does not exist is treated as empty archive.
+func readCTF(fs vfs.FileSystem, ctfPath string) (map[string][]byte, error) {
+	entries := map[string][]byte{}
+	file, err := fs.Open(ctfPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		entries[header.Name] = data
+	}
+}
+
+// writeCTF writes the component archives as ctf archive with sorted entries and fixed modification times.
+func writeCTF(fs vfs.FileSystem, ctfPath string, entries map[string][]byte) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     int64(len(entries[name])),
+			Mode:     0644,
+			ModTime:  fixedModTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(entries[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return vfs.WriteFile(fs, ctfPath, buf.Bytes(), 0644)
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// blobsDir is the directory of an oci image layout that contains the blobs.
+const blobsDir = "blobs"
+
+// WriteLayout writes the artifact as oci image layout into the directory root.
+// See https://github.com/opencontainers/image-spec/blob/main/image-layout.md.
+func (a *BlueprintArtifact) WriteLayout(fs vfs.FileSystem, root string) error {
+	if err := a.writeBlobs(fs, root); err != nil {
+		return err
+	}
+	return writeIndex(fs, root, &ocispecv1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispecv1.Descriptor{a.ManifestDescriptor()},
+	})
+}
+
+// AddToLayout adds the artifact to the oci image layout in the directory root.
+// The layout is created if it does not exist. The manifest is referenced in the index with the given reference
+// as "org.opencontainers.image.ref.name" annotation and replaces a manifest that has been added with the same reference.
+func (a *BlueprintArtifact) AddToLayout(fs vfs.FileSystem, root, ref string) error {
+	index := &ocispecv1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+	}
+	data, err := vfs.ReadFile(fs, filepath.Join(root, "index.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to read the index of the oci image layout: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, index); err != nil {
+			return fmt.Errorf("unable to decode the index of the oci image layout: %w", err)
+		}
+	}
+
+	manifests := []ocispecv1.Descriptor{}
+	for _, desc := range index.Manifests {
+		if desc.Annotations[ocispecv1.AnnotationRefName] != ref {
+			manifests = append(manifests, desc)
+		}
+	}
+	desc := a.ManifestDescriptor()
+	desc.Annotations = map[string]string{
+		ocispecv1.AnnotationRefName: ref,
+	}
+	index.Manifests = append(manifests, desc)
+
+	if err := a.writeBlobs(fs, root); err != nil {
+		return err
+	}
+	return writeIndex(fs, root, index)
+}
+
+// writeBlobs writes the oci-layout file and the blobs of the artifact into the directory root.
+func (a *BlueprintArtifact) writeBlobs(fs vfs.FileSystem, root string) error {
+	layout, err := json.Marshal(ocispecv1.ImageLayout{Version: ocispecv1.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(root, os.ModePerm); err != nil {
+		return err
+	}
+	if err := vfs.WriteFile(fs, filepath.Join(root, ocispecv1.ImageLayoutFile), layout, 0644); err != nil {
+		return err
+	}
+	for _, blob := range []struct {
+		desc ocispecv1.Descriptor
+		data []byte
+	}{
+		{a.ManifestDescriptor(), a.ManifestData},
+		{a.Manifest.Config, a.Config},
+		{a.Manifest.Layers[0], a.Layer},
+	} {
+		blobPath := blobPath(root, blob.desc)
+		if err := fs.MkdirAll(filepath.Dir(blobPath), os.ModePerm); err != nil {
+			return err
+		}
+		if err := vfs.WriteFile(fs, blobPath, blob.data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeIndex(fs vfs.FileSystem, root string, index *ocispecv1.Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return vfs.WriteFile(fs, filepath.Join(root, "index.json"), data, 0644)
+}
+
+// ReadLayout reads the blueprint artifact from the oci image layout in the directory root.
+// The layout must contain exactly one manifest.
+func ReadLayout(fs vfs.FileSystem, root string) (*BlueprintArtifact, error) {
+	data, err := vfs.ReadFile(fs, filepath.Join(root, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the index of the oci image layout: %w", err)
+	}
+	index := &ocispecv1.Index{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("unable to decode the index of the oci image layout: %w", err)
+	}
+	if len(index.Manifests) != 1 {
+		return nil, fmt.Errorf("the oci image layout must contain exactly one manifest but contains %d", len(index.Manifests))
+	}
+
+	readBlob := func(desc ocispecv1.Descriptor) ([]byte, error) {
+		return vfs.ReadFile(fs, blobPath(root, desc))
+	}
+	manifestData, err := readVerifiedBlob(index.Manifests[0], readBlob)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := NewBlueprintArtifact(manifestData, readBlob)
+	if err != nil {
+		return nil, err
+	}
+	if err := artifact.Validate(); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+// WriteArchive writes the artifact as reproducible gzipped tar of its oci image layout.
+func (a *BlueprintArtifact) WriteArchive(w io.Writer) error {
+	fs := memoryfs.New()
+	if err := a.WriteLayout(fs, "/"); err != nil {
+		return err
+	}
+	return buildTarGzip(fs, "/", w)
+}
+
+// ReadArchive reads a blueprint artifact from a gzipped tar of an oci image layout.
+func ReadArchive(ctx context.Context, r io.Reader) (*BlueprintArtifact, error) {
+	fs := memoryfs.New()
+	if err := extractTarGzip(ctx, r, fs, "/"); err != nil {
+		return nil, fmt.Errorf("unable to extract archive: %w", err)
+	}
+	return ReadLayout(fs, "/")
+}
+
+func blobPath(root string, desc ocispecv1.Descriptor) string {
+	return filepath.Join(root, blobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mandelsoft/vfs/pkg/vfs"
+)
+
+// fixedModTime is the modification time of all tar entries so that tarballs do not depend on the time they are built.
+var fixedModTime = time.Unix(0, 0).UTC()
+
+// buildTarGzip writes a reproducible gzipped tar of the directory root.
+// The entries are sorted by path and all metadata that depends on the machine, like modification times,
+// owners and umask, is normalized so that the same files always result in the same tarball.
+func buildTarGzip(fs vfs.FileSystem, root string, w io.Writer) error {
+	type entry struct {
+		path    string
+		relPath string
+		info    os.FileInfo
+	}
+	entries := []entry{}
+	err := vfs.Walk(fs, root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return fmt.Errorf("%s: only regular files and directories are supported", relPath)
+		}
+		entries = append(entries, entry{path: filePath, relPath: filepath.ToSlash(relPath), info: info})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].relPath < entries[j].relPath
+	})
+
+	// the gzip header does not contain a name or modification time by default
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:    e.relPath,
+			ModTime: fixedModTime,
+			Mode:    0644,
+			Format:  tar.FormatPAX,
+		}
+		if e.info.IsDir() {
+			header.Typeflag = tar.TypeDir
+			header.Name += "/"
+			header.Mode = 0755
+		} else {
+			header.Typeflag = tar.TypeReg
+			header.Size = e.info.Size()
+			if e.info.Mode()&0111 != 0 {
+				header.Mode = 0755
+			}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if e.info.IsDir() {
+			continue
+		}
+		if err := copyFile(fs, e.path, tw); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func copyFile(fs vfs.FileSystem, filePath string, w io.Writer) error {
+	file, err := fs.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// extractTarGzip extracts a gzipped tar into the directory root.
+// Entries that would be written outside of the directory are rejected.
+func extractTarGzip(ctx context.Context, r io.Reader, fs vfs.FileSystem, root string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, elem := range strings.Split(header.Name, "/") {
+			if elem == ".." {
+				return fmt.Errorf("invalid tar entry %q: parent directory references are not allowed", header.Name)
+			}
+		}
+		name := path.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		target := filepath.Join(root, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := fs.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := fs.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			file, err := fs.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode)&os.ModePerm)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported type of tar entry %q", header.Name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	cdsignatures "github.com/gardener/component-spec/bindings-go/apis/v2/signatures"
+	"github.com/spf13/pflag"
+)
+
+// VerificationOptions configures the signature verification of the component descriptor that references a blueprint.
+type VerificationOptions struct {
+	// PublicKeyPath is the path to the rsa public key that the signature of the component descriptor is verified with.
+	// The signature is not verified if no public key is given.
+	PublicKeyPath string
+	// SignatureName is the name of the signature in the component descriptor that is verified.
+	SignatureName string
+}
+
+// AddFlags adds the flags of the verification options to the flag set.
+func (o *VerificationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.PublicKeyPath, "public-key", "", "path to the rsa public key (PEM) that the signature of the component descriptor is verified with")
+	fs.StringVar(&o.SignatureName, "signature-name", "", "name of the signature in the component descriptor that is verified")
+}
+
+// Validate validates the verification options.
+func (o *VerificationOptions) Validate() error {
+	if len(o.PublicKeyPath) != 0 && len(o.SignatureName) == 0 {
+		return errors.New("a signature name must be defined to verify the signature of the component descriptor")
+	}
+	return nil
+}
+
+// VerifySignatureEnabled returns whether the signature of the component descriptor should be verified.
+func (o *VerificationOptions) VerifySignatureEnabled() bool {
+	return len(o.PublicKeyPath) != 0
+}
+
+// VerifySignature verifies the signature of the component descriptor and that the signed digest matches the
+// normalised component descriptor. As the normalised component descriptor contains the digests of all resources,
+// a verified signature together with a verified resource digest ensures that the resource has not been tampered with.
+func (o *VerificationOptions) VerifySignature(cd *cdv2.ComponentDescriptor) error {
+	verifier, err := cdsignatures.CreateRSAVerifierFromKeyFile(o.PublicKeyPath)
+	if err != nil {
+		return fmt.Errorf("unable to create verifier from public key %s: %w", o.PublicKeyPath, err)
+	}
+	if err := cdsignatures.VerifySignedComponentDescriptor(cd, verifier, o.SignatureName); err != nil {
+		return fmt.Errorf("signature %q of component descriptor %s:%s is invalid: %w", o.SignatureName, cd.GetName(), cd.GetVersion(), err)
+	}
+	return nil
+}
+
+// VerifyResourceDigest checks that a blueprint matches the digest of its resource in the component descriptor.
+// Depending on the normalisation algorithm of the digest, the digest is computed from the oci manifest of
+// the blueprint artifact (resources with access type ociRegistry) or from the blueprint blob (local blobs).
+// The manifest is nil if the blueprint has not been fetched as oci artifact.
+func VerifyResourceDigest(res cdv2.Resource, manifestData, blob []byte) error {
+	if res.Digest == nil {
+		return fmt.Errorf("resource %s has no digest", res.GetName())
+	}
+	var data []byte
+	switch cdv2.NormalisationAlgorithm(res.Digest.NormalisationAlgorithm) {
+	case cdv2.OciArtifactDigestV1:
+		if manifestData == nil {
+			return fmt.Errorf("the digest of resource %s is an oci artifact digest, but the blueprint is no oci artifact", res.GetName())
+		}
+		data = manifestData
+	case cdv2.GenericBlobDigestV1:
+		data = blob
+	case cdv2.ExcludeFromSignature:
+		return fmt.Errorf("resource %s is excluded from the signature and has no digest", res.GetName())
+	default:
+		return fmt.Errorf("unsupported normalisation algorithm %q of the digest of resource %s", res.Digest.NormalisationAlgorithm, res.GetName())
+	}
+
+	hasher, err := cdsignatures.HasherForName(res.Digest.HashAlgorithm)
+	if err != nil {
+		return err
+	}
+	if hasher.HashFunction == nil {
+		return fmt.Errorf("resource %s has no digest", res.GetName())
+	}
+	if _, err := hasher.HashFunction.Write(data); err != nil {
+		return fmt.Errorf("unable to calculate digest: %w", err)
+	}
+	if actual := hex.EncodeToString(hasher.HashFunction.Sum(nil)); actual != res.Digest.Value {
+		return fmt.Errorf("digest of resource %s does not match: expected %s:%s but got %s:%s",
+			res.GetName(), hasher.AlgorithmName, res.Digest.Value, hasher.AlgorithmName, actual)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	cdsignatures "github.com/gardener/component-spec/bindings-go/apis/v2/signatures"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/artifacts"
+)
+
+func TestVerifyResourceDigest(t *testing.T) {
+	artifact, err := artifacts.BuildBlueprint(osfs.New(), testBlueprintPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ociRes := blueprintResource(cdv2.OciArtifactDigestV1, artifact.ManifestData)
+	assert.NoError(t, artifacts.VerifyResourceDigest(ociRes, artifact.ManifestData, artifact.Layer))
+	assert.Error(t, artifacts.VerifyResourceDigest(ociRes, nil, artifact.Layer), "expect an error if the blueprint is no oci artifact")
+
+	blobRes := blueprintResource(cdv2.GenericBlobDigestV1, artifact.Layer)
+	assert.NoError(t, artifacts.VerifyResourceDigest(blobRes, nil, artifact.Layer))
+
+	tampered := append([]byte{}, artifact.Layer...)
+	tampered[len(tampered)-1] ^= 0xff
+	err = artifacts.VerifyResourceDigest(blobRes, nil, tampered)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "digest of resource blueprint does not match")
+	}
+
+	blobRes.Digest = nil
+	assert.Error(t, artifacts.VerifyResourceDigest(blobRes, nil, artifact.Layer), "expect an error if the resource has no digest")
+}
+
+func TestVerifySignature(t *testing.T) {
+	privateKeyPath, publicKeyPath := writeRSAKeys(t)
+	signer, err := cdsignatures.CreateRSASignerFromKeyFile(privateKeyPath, cdv2.MediaTypeRSASignature)
+	if !assert.NoError(t, err) {
+		return
+	}
+	hasher, err := cdsignatures.HasherForName(cdsignatures.SHA256)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cd := &cdv2.ComponentDescriptor{
+		Metadata: cdv2.Metadata{Version: cdv2.SchemaVersion},
+		ComponentSpec: cdv2.ComponentSpec{
+			ObjectMeta: cdv2.ObjectMeta{
+				Name:    "example.com/component",
+				Version: "v1.0.0",
+			},
+			Provider:  cdv2.InternalProvider,
+			Resources: []cdv2.Resource{blueprintResource(cdv2.GenericBlobDigestV1, []byte("blueprint"))},
+		},
+	}
+	if !assert.NoError(t, cdsignatures.SignComponentDescriptor(cd, signer, *hasher, "test")) {
+		return
+	}
+
+	opts := artifacts.VerificationOptions{PublicKeyPath: publicKeyPath, SignatureName: "test"}
+	assert.NoError(t, opts.Validate())
+	assert.NoError(t, opts.VerifySignature(cd))
+
+	cd.Resources[0].Digest.Value = hex.EncodeToString(make([]byte, sha256.Size))
+	assert.Error(t, opts.VerifySignature(cd), "expect an error if a resource digest has been changed")
+
+	opts.SignatureName = ""
+	assert.Error(t, opts.Validate())
+}
+
+func blueprintResource(algorithm cdv2.NormalisationAlgorithm, data []byte) cdv2.Resource {
+	sum := sha256.Sum256(data)
+	access, _ := cdv2.NewUnstructured(cdv2.NewLocalFilesystemBlobAccess("sha256:"+hex.EncodeToString(sum[:]), artifacts.BlueprintLayerMediaType))
+	return cdv2.Resource{
+		IdentityObjectMeta: cdv2.IdentityObjectMeta{
+			Name:    "blueprint",
+			Version: "v1.0.0",
+			Type:    "blueprint",
+		},
+		Relation: cdv2.LocalRelation,
+		Access:   &access,
+		Digest: &cdv2.DigestSpec{
+			HashAlgorithm:          cdsignatures.SHA256,
+			NormalisationAlgorithm: string(algorithm),
+			Value:                  hex.EncodeToString(sum[:]),
+		},
+	}
+}
+
+// writeRSAKeys generates a rsa key pair and writes it PEM encoded into a temporary directory.
+func writeRSAKeys(t *testing.T) (privateKeyPath, publicKeyPath string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privateKeyPath = filepath.Join(dir, "private.pem")
+	publicKeyPath = filepath.Join(dir, "public.pem")
+	if err := os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privateKeyPath, publicKeyPath
+}
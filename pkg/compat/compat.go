@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package compat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscaper/pkg/landscaper/jsonschema"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"github.com/gardener/landscapercli/pkg/executions"
+	"github.com/gardener/landscapercli/pkg/schemas"
+)
+
+// Severity describes the impact of a change on existing installations.
+type Severity string
+
+const (
+	// Breaking changes break existing installations or cause a redeployment.
+	Breaking Severity = "breaking"
+	// Warning changes could not be checked completely.
+	Warning Severity = "warning"
+	// Info changes are compatible.
+	Info Severity = "info"
+)
+
+// Kinds of the changed elements of a blueprint.
+const (
+	ImportKind     = "import"
+	ExportKind     = "export"
+	DeployItemKind = "deployItem"
+)
+
+// Change describes a difference between two versions of a blueprint.
+type Change struct {
+	// Severity is the impact of the change.
+	Severity Severity `json:"severity"`
+	// Kind is the kind of the changed element, e.g. import, export or deployItem.
+	Kind string `json:"kind"`
+	// Name is the name of the changed element.
+	Name string `json:"name"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// Blueprint is a version of a blueprint that is compared.
+type Blueprint struct {
+	// Blueprint is the decoded blueprint.
+	Blueprint *lsv1alpha1.Blueprint
+	// Fs is the filesystem of the blueprint directory.
+	Fs vfs.FileSystem
+}
+
+// ReadBlueprint reads the blueprint of a blueprint directory.
+func ReadBlueprint(fs vfs.FileSystem) (*Blueprint, error) {
+	data, err := vfs.ReadFile(fs, lsv1alpha1.BlueprintFileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	blueprint := &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	return &Blueprint{Blueprint: blueprint, Fs: fs}, nil
+}
+
+// CountBreakingChanges returns the number of breaking changes.
+func CountBreakingChanges(changes []Change) int {
+	count := 0
+	for _, change := range changes {
+		if change.Severity == Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare compares an old and a new version of a blueprint and returns the changes
+// that affect installations of the old version, sorted by severity, kind and name.
+func Compare(oldBlueprint, newBlueprint *Blueprint) ([]Change, error) {
+	changes := compareImports(oldBlueprint, newBlueprint)
+	changes = append(changes, compareExports(oldBlueprint, newBlueprint)...)
+
+	deployItemChanges, err := compareDeployItems(oldBlueprint, newBlueprint)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, deployItemChanges...)
+
+	severityOrder := map[Severity]int{Breaking: 0, Warning: 1, Info: 2}
+	kindOrder := map[string]int{ImportKind: 0, ExportKind: 1, DeployItemKind: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Severity != b.Severity {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.Name < b.Name
+	})
+	return changes, nil
+}
+
+// importDefinition is an import definition together with the import its condition depends on.
+type importDefinition struct {
+	lsv1alpha1.ImportDefinition
+	condition string
+}
+
+// required returns whether the import has to be set by installations.
+// Conditional imports and imports with a default value are never required.
+func (i importDefinition) required() bool {
+	return (i.Required == nil || *i.Required) && len(i.condition) == 0 && i.Default.Value.RawMessage == nil
+}
+
+// flattenImports returns the imports including the conditional imports by their name.
+func flattenImports(imports lsv1alpha1.ImportDefinitionList, condition string, result map[string]importDefinition) map[string]importDefinition {
+	for _, imp := range imports {
+		result[imp.Name] = importDefinition{ImportDefinition: imp, condition: condition}
+		flattenImports(imp.ConditionalImports, imp.Name, result)
+	}
+	return result
+}
+
+func compareImports(oldBlueprint, newBlueprint *Blueprint) []Change {
+	oldImports := flattenImports(oldBlueprint.Blueprint.Imports, "", map[string]importDefinition{})
+	newImports := flattenImports(newBlueprint.Blueprint.Imports, "", map[string]importDefinition{})
+	oldResolver := newReferenceResolver(oldBlueprint)
+	newResolver := newReferenceResolver(newBlueprint)
+
+	var changes []Change
+	for name, oldImp := range oldImports {
+		newImp, ok := newImports[name]
+		if !ok {
+			changes = append(changes, Change{Breaking, ImportKind, name, "the import has been removed"})
+			continue
+		}
+		oldType, newType := importType(oldImp.ImportDefinition), importType(newImp.ImportDefinition)
+		if oldType != newType {
+			changes = append(changes, Change{Breaking, ImportKind, name, fmt.Sprintf("the type has been changed from %s to %s", oldType, newType)})
+			continue
+		}
+		if oldImp.TargetType != newImp.TargetType {
+			changes = append(changes, Change{Breaking, ImportKind, name, fmt.Sprintf("the target type has been changed from %s to %s", oldImp.TargetType, newImp.TargetType)})
+		}
+		if newImp.required() && !oldImp.required() {
+			changes = append(changes, Change{Breaking, ImportKind, name, "the import has become required"})
+		} else if !newImp.required() && oldImp.required() {
+			changes = append(changes, Change{Info, ImportKind, name, "the import has become optional"})
+		}
+		if newType == lsv1alpha1.ImportTypeData {
+			changes = append(changes, compareImportSchemas(name, oldImp, newImp, oldResolver, newResolver)...)
+		}
+	}
+	for name, newImp := range newImports {
+		if _, ok := oldImports[name]; ok {
+			continue
+		}
+		if newImp.required() {
+			changes = append(changes, Change{Breaking, ImportKind, name, "a required import has been added"})
+		} else {
+			changes = append(changes, Change{Info, ImportKind, name, "an optional import has been added"})
+		}
+	}
+	return changes
+}
+
+// compareImportSchemas reports schema changes that reject values which have been valid for the old schema.
+func compareImportSchemas(name string, oldImp, newImp importDefinition, oldResolver, newResolver *jsonschema.ReferenceResolver) []Change {
+	oldSchema, err := resolveSchema(oldResolver, oldImp.Schema)
+	if err != nil {
+		return []Change{{Warning, ImportKind, name, fmt.Sprintf("the old schema could not be resolved: %s", err.Error())}}
+	}
+	newSchema, err := resolveSchema(newResolver, newImp.Schema)
+	if err != nil {
+		return []Change{{Warning, ImportKind, name, fmt.Sprintf("the new schema could not be resolved: %s", err.Error())}}
+	}
+
+	comparison := &schemaComparison{}
+	comparison.compare(oldSchema, newSchema, "")
+	var changes []Change
+	for _, narrowed := range comparison.narrowed {
+		changes = append(changes, Change{Breaking, ImportKind, name, "the schema has been narrowed: " + narrowed})
+	}
+	for _, unchecked := range comparison.unchecked {
+		changes = append(changes, Change{Warning, ImportKind, name, "the schema could not be compared: " + unchecked})
+	}
+	return changes
+}
+
+func compareExports(oldBlueprint, newBlueprint *Blueprint) []Change {
+	newExports := map[string]lsv1alpha1.ExportDefinition{}
+	for _, exp := range newBlueprint.Blueprint.Exports {
+		newExports[exp.Name] = exp
+	}
+
+	var changes []Change
+	oldExports := map[string]bool{}
+	for _, oldExp := range oldBlueprint.Blueprint.Exports {
+		oldExports[oldExp.Name] = true
+		newExp, ok := newExports[oldExp.Name]
+		if !ok {
+			changes = append(changes, Change{Breaking, ExportKind, oldExp.Name, "the export has been removed"})
+			continue
+		}
+		oldType, newType := exportType(oldExp), exportType(newExp)
+		if oldType != newType {
+			changes = append(changes, Change{Breaking, ExportKind, oldExp.Name, fmt.Sprintf("the type has been changed from %s to %s", oldType, newType)})
+			continue
+		}
+		if oldExp.TargetType != newExp.TargetType {
+			changes = append(changes, Change{Breaking, ExportKind, oldExp.Name, fmt.Sprintf("the target type has been changed from %s to %s", oldExp.TargetType, newExp.TargetType)})
+		}
+	}
+	for _, newExp := range newBlueprint.Blueprint.Exports {
+		if !oldExports[newExp.Name] {
+			changes = append(changes, Change{Info, ExportKind, newExp.Name, "the export has been added"})
+		}
+	}
+	return changes
+}
+
+// deployItemNames renders the deploy executions with example values of the imports and returns the execution of
+// every deploy item by its name. Executions that cannot be rendered with example values are returned with their error.
+func deployItemNames(blueprint *Blueprint) (map[string]string, map[string]error, error) {
+	generator := &schemas.ValuesGenerator{Fs: blueprint.Fs}
+	imports, err := generator.GenerateImports()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate example values: %w", err)
+	}
+	deployItems, errs := executions.RenderDeployItems(blueprint.Fs, blueprint.Blueprint, imports)
+	names := map[string]string{}
+	for _, di := range deployItems {
+		names[di.Name] = di.Execution
+	}
+	return names, errs, nil
+}
+
+func compareDeployItems(oldBlueprint, newBlueprint *Blueprint) ([]Change, error) {
+	oldNames, oldErrs, err := deployItemNames(oldBlueprint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render deploy executions of the old blueprint: %w", err)
+	}
+	newNames, newErrs, err := deployItemNames(newBlueprint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render deploy executions of the new blueprint: %w", err)
+	}
+
+	// the deploy items of executions that cannot be rendered in one of the versions cannot be compared
+	var changes []Change
+	unchecked := map[string]bool{}
+	for _, errs := range []map[string]error{oldErrs, newErrs} {
+		for execution, err := range errs {
+			if !unchecked[execution] {
+				unchecked[execution] = true
+				changes = append(changes, Change{Warning, DeployItemKind, execution,
+					fmt.Sprintf("the deploy execution could not be rendered with example values, its deploy items have not been compared: %s", err.Error())})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	for name, execution := range oldNames {
+		if unchecked[execution] {
+			delete(oldNames, name)
+		}
+	}
+	for name, execution := range newNames {
+		if unchecked[execution] {
+			delete(newNames, name)
+		}
+	}
+
+	var removed, added []string
+	for name := range oldNames {
+		if _, ok := newNames[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	for name := range newNames {
+		if _, ok := oldNames[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	// deploy items are identified by their name, so a renamed deploy item is deleted and created again
+	addedInExecution := map[string][]string{}
+	for _, name := range added {
+		addedInExecution[newNames[name]] = append(addedInExecution[newNames[name]], name)
+	}
+	for _, name := range removed {
+		message := "the deploy item has been removed, it will be deleted"
+		if candidates := addedInExecution[oldNames[name]]; len(candidates) != 0 {
+			message = fmt.Sprintf("the deploy item has been renamed to %s in execution %s, it will be deleted and deployed again",
+				strings.Join(candidates, " or "), oldNames[name])
+		}
+		changes = append(changes, Change{Breaking, DeployItemKind, name, message})
+	}
+	for _, name := range added {
+		changes = append(changes, Change{Info, DeployItemKind, name, fmt.Sprintf("the deploy item has been added in execution %s", newNames[name])})
+	}
+	return changes, nil
+}
+
+// newReferenceResolver creates a resolver for the schema references of a blueprint.
+// References to the component descriptor cannot be resolved as no component descriptor is known.
+func newReferenceResolver(blueprint *Blueprint) *jsonschema.ReferenceResolver {
+	return jsonschema.NewReferenceResolver(&jsonschema.ReferenceContext{
+		LocalTypes:  blueprint.Blueprint.LocalTypes,
+		BlueprintFs: blueprint.Fs,
+		// an empty component descriptor is needed to report cyclic references
+		ComponentDescriptor: &cdv2.ComponentDescriptor{},
+	})
+}
+
+func resolveSchema(resolver *jsonschema.ReferenceResolver, schema *lsv1alpha1.JSONSchemaDefinition) (interface{}, error) {
+	if schema == nil || schema.RawMessage == nil {
+		return map[string]interface{}{}, nil
+	}
+	return resolver.Resolve(schema.RawMessage)
+}
+
+func importType(imp lsv1alpha1.ImportDefinition) lsv1alpha1.ImportType {
+	if len(imp.Type) != 0 {
+		return imp.Type
+	}
+	if len(imp.TargetType) != 0 {
+		return lsv1alpha1.ImportTypeTarget
+	}
+	return lsv1alpha1.ImportTypeData
+}
+
+func exportType(exp lsv1alpha1.ExportDefinition) lsv1alpha1.ExportType {
+	if len(exp.Type) != 0 {
+		return exp.Type
+	}
+	if len(exp.TargetType) != 0 {
+		return lsv1alpha1.ExportTypeTarget
+	}
+	return lsv1alpha1.ExportTypeData
+}
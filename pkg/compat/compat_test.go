@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package compat_test
+
+import (
+	"testing"
+
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/compat"
+)
+
+func TestCompare(t *testing.T) {
+	oldBlueprint := readTestBlueprint(t, "./testdata/old")
+	newBlueprint := readTestBlueprint(t, "./testdata/new")
+	if oldBlueprint == nil || newBlueprint == nil {
+		return
+	}
+
+	changes, err := compat.Compare(oldBlueprint, newBlueprint)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []compat.Change{
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "cluster", Message: "the target type has been changed from landscaper.gardener.cloud/kubernetes-cluster to landscaper.gardener.cloud/gardener-cluster"},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "config", Message: "the schema has been narrowed: property replicas has become required"},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "config", Message: `the schema has been narrowed: value "debug" is not allowed anymore at .logLevel`},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "config", Message: "the schema has been narrowed: maximum has been lowered to 5 at .replicas"},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "ingress", Message: "the import has become required"},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "legacy", Message: "the import has been removed"},
+		{Severity: compat.Breaking, Kind: compat.ImportKind, Name: "region", Message: "a required import has been added"},
+		{Severity: compat.Breaking, Kind: compat.ExportKind, Name: "legacy", Message: "the export has been removed"},
+		{Severity: compat.Breaking, Kind: compat.DeployItemKind, Name: "app", Message: "the deploy item has been renamed to application in execution default, it will be deleted and deployed again"},
+		{Severity: compat.Info, Kind: compat.ImportKind, Name: "labels", Message: "an optional import has been added"},
+		{Severity: compat.Info, Kind: compat.ExportKind, Name: "endpoint", Message: "the export has been added"},
+		{Severity: compat.Info, Kind: compat.DeployItemKind, Name: "application", Message: "the deploy item has been added in execution default"},
+	}, changes)
+	assert.Equal(t, 9, compat.CountBreakingChanges(changes))
+
+	changes, err = compat.Compare(oldBlueprint, oldBlueprint)
+	if assert.NoError(t, err) {
+		assert.Empty(t, changes)
+	}
+}
+
+func readTestBlueprint(t *testing.T, path string) *compat.Blueprint {
+	fs, err := projectionfs.New(osfs.New(), path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	blueprint, err := compat.ReadBlueprint(fs)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return blueprint
+}
+
+func TestCompareUnrenderableDeployExecution(t *testing.T) {
+	fs := memoryfs.New()
+	assert.NoError(t, vfs.WriteFile(fs, "blueprint.yaml", []byte(`
+apiVersion: landscaper.gardener.cloud/v1alpha1
+kind: Blueprint
+deployExecutions:
+- name: default
+  type: GoTemplate
+  template: |
+    deployItems:
+    - name: {{ .cd.component.name }}
+      type: landscaper.gardener.cloud/helm
+`), 0644))
+	blueprint, err := compat.ReadBlueprint(fs)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	changes, err := compat.Compare(blueprint, blueprint)
+	if !assert.NoError(t, err) || !assert.Len(t, changes, 1) {
+		return
+	}
+	assert.Equal(t, compat.Warning, changes[0].Severity)
+	assert.Equal(t, "default", changes[0].Name)
+	assert.Contains(t, changes[0].Message, "the deploy execution could not be rendered with example values")
+}
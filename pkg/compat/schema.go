@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// schemaComparison collects the differences between an old and a new json schema.
+type schemaComparison struct {
+	// narrowed are the changes that reject values which have been valid for the old schema.
+	narrowed []string
+	// unchecked are the changes whose impact could not be determined.
+	unchecked []string
+}
+
+func (c *schemaComparison) narrow(path, format string, args ...interface{}) {
+	c.narrowed = append(c.narrowed, withPath(path, fmt.Sprintf(format, args...)))
+}
+
+func (c *schemaComparison) uncheck(path, format string, args ...interface{}) {
+	c.unchecked = append(c.unchecked, withPath(path, fmt.Sprintf(format, args...)))
+}
+
+func withPath(path, message string) string {
+	if len(path) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s at %s", message, path)
+}
+
+// lowerBounds are the keywords whose increase narrows a schema.
+var lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+
+// upperBounds are the keywords whose decrease narrows a schema.
+var upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+
+// compare compares the old and the new schema at the given path.
+// Properties that are added to the new schema are not considered as narrowing,
+// although they could reject values of properties that have not been defined by the old schema.
+func (c *schemaComparison) compare(oldValue, newValue interface{}, path string) {
+	if newBool, ok := newValue.(bool); ok {
+		if !newBool && oldValue != false {
+			c.narrow(path, "no value is allowed anymore")
+		}
+		return
+	}
+	newSchema, ok := newValue.(map[string]interface{})
+	if !ok {
+		return
+	}
+	oldSchema, ok := oldValue.(map[string]interface{})
+	if !ok {
+		// the old schema allows every value
+		oldSchema = map[string]interface{}{}
+	}
+
+	oldTypes, newTypes := schemaTypes(oldSchema), schemaTypes(newSchema)
+	if len(newTypes) != 0 {
+		if len(oldTypes) == 0 {
+			c.narrow(path, "the type has been restricted to %s", joinKeys(newTypes))
+		} else {
+			for _, t := range sortedKeys(oldTypes) {
+				if !newTypes[t] && !(t == "integer" && newTypes["number"]) {
+					c.narrow(path, "type %s is not allowed anymore", t)
+				}
+			}
+		}
+	}
+
+	if newEnum, ok := newSchema["enum"].([]interface{}); ok {
+		oldEnum, ok := oldSchema["enum"].([]interface{})
+		if !ok {
+			c.narrow(path, "the values have been restricted to %s", jsonString(newEnum))
+		} else {
+			allowed := map[string]bool{}
+			for _, value := range newEnum {
+				allowed[jsonString(value)] = true
+			}
+			for _, value := range oldEnum {
+				if !allowed[jsonString(value)] {
+					c.narrow(path, "value %s is not allowed anymore", jsonString(value))
+				}
+			}
+		}
+	}
+	if newConst, ok := newSchema["const"]; ok {
+		if oldConst, ok := oldSchema["const"]; !ok || jsonString(oldConst) != jsonString(newConst) {
+			c.narrow(path, "the value has been restricted to %s", jsonString(newConst))
+		}
+	}
+
+	for _, key := range lowerBounds {
+		newBound, ok := number(newSchema[key])
+		if !ok {
+			continue
+		}
+		if oldBound, ok := number(oldSchema[key]); !ok || newBound > oldBound {
+			c.narrow(path, "%s has been raised to %v", key, newSchema[key])
+		}
+	}
+	for _, key := range upperBounds {
+		newBound, ok := number(newSchema[key])
+		if !ok {
+			continue
+		}
+		if oldBound, ok := number(oldSchema[key]); !ok || newBound < oldBound {
+			c.narrow(path, "%s has been lowered to %v", key, newSchema[key])
+		}
+	}
+	for _, key := range []string{"pattern", "format", "multipleOf"} {
+		newKeyword, ok := newSchema[key]
+		if !ok {
+			continue
+		}
+		if oldKeyword, ok := oldSchema[key]; !ok || jsonString(oldKeyword) != jsonString(newKeyword) {
+			c.narrow(path, "%s %s is required", key, jsonString(newKeyword))
+		}
+	}
+
+	oldRequired := stringSet(oldSchema["required"])
+	for _, name := range sortedKeys(stringSet(newSchema["required"])) {
+		if !oldRequired[name] {
+			c.narrow(path, "property %s has become required", name)
+		}
+	}
+
+	oldProperties, _ := oldSchema["properties"].(map[string]interface{})
+	newProperties, _ := newSchema["properties"].(map[string]interface{})
+	newAdditional, hasNewAdditional := newSchema["additionalProperties"]
+	for _, name := range sortedKeys(oldProperties) {
+		newProperty, ok := newProperties[name]
+		if !ok {
+			if hasNewAdditional {
+				c.compare(oldProperties[name], newAdditional, path+"."+name)
+			}
+			continue
+		}
+		c.compare(oldProperties[name], newProperty, path+"."+name)
+	}
+	if hasNewAdditional {
+		oldAdditional, ok := oldSchema["additionalProperties"]
+		if !ok {
+			oldAdditional = true
+		}
+		if newAdditional == false && oldAdditional != false {
+			c.narrow(path, "additional properties are not allowed anymore")
+		} else {
+			c.compare(oldAdditional, newAdditional, path+".*")
+		}
+	}
+
+	if newItems, ok := newSchema["items"]; ok {
+		c.compare(oldSchema["items"], newItems, path+"[]")
+	}
+
+	for _, key := range []string{"allOf", "anyOf", "oneOf", "not", "if", "then", "else", "dependencies", "patternProperties", "$ref"} {
+		newKeyword, ok := newSchema[key]
+		if !ok {
+			continue
+		}
+		if oldKeyword, ok := oldSchema[key]; !ok || jsonString(oldKeyword) != jsonString(newKeyword) {
+			c.uncheck(path, "%s has been changed", key)
+		}
+	}
+}
+
+// schemaTypes returns the allowed types of a schema.
+func schemaTypes(schema map[string]interface{}) map[string]bool {
+	types := map[string]bool{}
+	switch typed := schema["type"].(type) {
+	case string:
+		types[typed] = true
+	case []interface{}:
+		for _, t := range typed {
+			if s, ok := t.(string); ok {
+				types[s] = true
+			}
+		}
+	}
+	return types
+}
+
+func stringSet(value interface{}) map[string]bool {
+	set := map[string]bool{}
+	list, _ := value.([]interface{})
+	for _, elem := range list {
+		if s, ok := elem.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func number(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case json.Number:
+		f, err := typed.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func jsonString(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func joinKeys(set map[string]bool) string {
+	return jsonString(sortedKeys(set))
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch typed := m.(type) {
+	case map[string]interface{}:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]bool:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package deployitems
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gardener/component-cli/ociclient"
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	helmv1alpha1 "github.com/gardener/landscaper/apis/deployer/helm/v1alpha1"
+	"github.com/gardener/landscaper/apis/deployer/manifest"
+	manifestinstall "github.com/gardener/landscaper/apis/deployer/manifest/install"
+	"github.com/gardener/landscaper/pkg/deployer/helm/chartresolver"
+	"github.com/gardener/landscaper/pkg/landscaper/installations"
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart"
+	chartloader "helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// HelmDeployItemType is the type of deploy items handled by the helm deployer.
+	HelmDeployItemType lsv1alpha1.DeployItemType = "landscaper.gardener.cloud/helm"
+	// ManifestDeployItemType is the type of deploy items handled by the manifest deployer.
+	ManifestDeployItemType lsv1alpha1.DeployItemType = "landscaper.gardener.cloud/kubernetes-manifest"
+	// ContainerDeployItemType is the type of deploy items handled by the container deployer.
+	ContainerDeployItemType lsv1alpha1.DeployItemType = "landscaper.gardener.cloud/container"
+	// MockDeployItemType is the type of deploy items handled by the mock deployer.
+	MockDeployItemType lsv1alpha1.DeployItemType = "landscaper.gardener.cloud/mock"
+)
+
+var manifestScheme = runtime.NewScheme()
+
+func init() {
+	manifestinstall.Install(manifestScheme)
+}
+
+// Expander expands manifest and helm deploy items into the plain kubernetes objects
+// that would be applied to the target cluster by the respective deployer.
+type Expander struct {
+	log logr.Logger
+	// ociClient is used to fetch helm charts from oci registries.
+	ociClient ociclient.Client
+	// componentResolver is used to resolve helm charts that are referenced as component descriptor resource.
+	componentResolver ctf.ComponentResolver
+}
+
+// NewExpander creates a new deploy item expander.
+// The oci client and the component resolver are only needed to fetch helm charts and may be nil.
+func NewExpander(log logr.Logger, ociClient ociclient.Client, componentResolver ctf.ComponentResolver) *Expander {
+	return &Expander{
+		log:               log,
+		ociClient:         ociClient,
+		componentResolver: componentResolver,
+	}
+}
+
+// CanExpand returns whether the deploy item can be expanded into kubernetes objects.
+func CanExpand(di *lsv1alpha1.DeployItem) bool {
+	return di.Spec.Type == ManifestDeployItemType || di.Spec.Type == HelmDeployItemType
+}
+
+// Expand returns the kubernetes objects that are deployed by the given deploy item.
+func (e *Expander) Expand(ctx context.Context, di *lsv1alpha1.DeployItem) ([]*unstructured.Unstructured, error) {
+	if di.Spec.Configuration == nil {
+		return nil, fmt.Errorf("deploy item %s has no configuration", di.Name)
+	}
+
+	switch di.Spec.Type {
+	case ManifestDeployItemType:
+		return e.expandManifests(di)
+	case HelmDeployItemType:
+		return e.expandHelmChart(ctx, di)
+	default:
+		return nil, fmt.Errorf("deploy items of type %s cannot be expanded", di.Spec.Type)
+	}
+}
+
+func (e *Expander) expandManifests(di *lsv1alpha1.DeployItem) ([]*unstructured.Unstructured, error) {
+	config := &manifest.ProviderConfiguration{}
+	if _, _, err := serializer.NewCodecFactory(manifestScheme).UniversalDecoder().Decode(di.Spec.Configuration.Raw, nil, config); err != nil {
+		return nil, fmt.Errorf("unable to decode manifest configuration of deploy item %s: %w", di.Name, err)
+	}
+
+	objects := make([]*unstructured.Unstructured, 0, len(config.Manifests))
+	for i, m := range config.Manifests {
+		if m.Manifest == nil {
+			continue
+		}
+		obj, err := decodeObject(m.Manifest.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode manifest %d of deploy item %s: %w", i, di.Name, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func (e *Expander) expandHelmChart(ctx context.Context, di *lsv1alpha1.DeployItem) ([]*unstructured.Unstructured, error) {
+	config := &helmv1alpha1.ProviderConfiguration{}
+	if err := yaml.Unmarshal(di.Spec.Configuration.Raw, config); err != nil {
+		return nil, fmt.Errorf("unable to decode helm configuration of deploy item %s: %w", di.Name, err)
+	}
+
+	ch, err := e.getChart(ctx, &config.Chart)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get helm chart of deploy item %s: %w", di.Name, err)
+	}
+
+	values := map[string]interface{}{}
+	if len(config.Values) != 0 {
+		if err := yaml.Unmarshal(config.Values, &values); err != nil {
+			return nil, fmt.Errorf("unable to decode helm values of deploy item %s: %w", di.Name, err)
+		}
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      config.Name,
+		Namespace: config.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(ch, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build helm values of deploy item %s: %w", di.Name, err)
+	}
+
+	files, err := engine.Render(ch, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("unable to template helm chart of deploy item %s: %w", di.Name, err)
+	}
+
+	objects := []*unstructured.Unstructured{}
+	crds := ch.CRDObjects()
+	sort.Slice(crds, func(i, j int) bool { return crds[i].Filename < crds[j].Filename })
+	for _, crd := range crds {
+		crdObjects, err := decodeManifests(crd.File.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode crd %s of deploy item %s: %w", crd.Filename, di.Name, err)
+		}
+		objects = append(objects, crdObjects...)
+	}
+
+	hooks, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultCapabilities.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sort manifests of deploy item %s: %w", di.Name, err)
+	}
+	for _, m := range manifests {
+		manifestObjects, err := decodeManifests([]byte(m.Content))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode manifest %s of deploy item %s: %w", m.Name, di.Name, err)
+		}
+		objects = append(objects, manifestObjects...)
+	}
+	// hooks are added after the other objects, they keep their helm.sh/hook annotations
+	for _, hook := range hooks {
+		hookObjects, err := decodeManifests([]byte(hook.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode hook %s of deploy item %s: %w", hook.Path, di.Name, err)
+		}
+		objects = append(objects, hookObjects...)
+	}
+
+	return objects, nil
+}
+
+// getChart fetches the helm chart from an inline archive, an oci registry or a component descriptor resource.
+func (e *Expander) getChart(ctx context.Context, chartConfig *helmv1alpha1.Chart) (*chart.Chart, error) {
+	if chartConfig.FromResource != nil {
+		return e.getChartFromResource(ctx, chartConfig.FromResource)
+	}
+	if chartConfig.HelmChartRepo != nil {
+		return nil, fmt.Errorf("charts from helm chart repositories are not supported")
+	}
+	if len(chartConfig.Ref) != 0 && e.ociClient == nil {
+		return nil, fmt.Errorf("no oci client configured to fetch chart %s", chartConfig.Ref)
+	}
+	return chartresolver.GetChart(ctx, e.log, e.ociClient, nil, chartConfig)
+}
+
+func (e *Expander) getChartFromResource(ctx context.Context, ref *helmv1alpha1.RemoteChartReference) (*chart.Chart, error) {
+	if e.componentResolver == nil {
+		return nil, fmt.Errorf("no component resolver configured to fetch chart from resource %q", ref.ResourceName)
+	}
+
+	var (
+		cd           *cdv2.ComponentDescriptor
+		blobResolver ctf.BlobResolver
+	)
+	if ref.Inline != nil {
+		cd = ref.Inline
+		var err error
+		_, blobResolver, err = e.componentResolver.ResolveWithBlobResolver(ctx, cd.GetEffectiveRepositoryContext(), cd.GetName(), cd.GetVersion())
+		if err != nil {
+			return nil, fmt.Errorf("unable to get blob resolver for component %q: %w", cd.GetName(), err)
+		}
+	} else {
+		cdRef := installations.GetReferenceFromComponentDescriptorDefinition(&ref.ComponentDescriptorDefinition)
+		if cdRef == nil {
+			return nil, fmt.Errorf("no component descriptor reference found for %q", ref.ResourceName)
+		}
+		var err error
+		cd, blobResolver, err = e.componentResolver.ResolveWithBlobResolver(ctx, cdRef.RepositoryContext, cdRef.ComponentName, cdRef.Version)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get component descriptor for %q: %w", cdRef.ComponentName, err)
+		}
+	}
+
+	resources, err := cd.GetResourcesByName(ref.ResourceName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find resource with name %q in component descriptor", ref.ResourceName)
+	}
+	if len(resources) != 1 {
+		return nil, fmt.Errorf("resource with name %q cannot be uniquely identified", ref.ResourceName)
+	}
+	res := resources[0]
+
+	helmResolver := chartresolver.NewHelmResolver(e.ociClient)
+	if e.ociClient != nil && helmResolver.CanResolve(res) {
+		blobResolver = helmResolver
+	}
+
+	var buf bytes.Buffer
+	if _, err := blobResolver.Resolve(ctx, res, &buf); err != nil {
+		return nil, fmt.Errorf("unable to resolve chart from resource %q: %w", ref.ResourceName, err)
+	}
+
+	ch, err := chartloader.LoadArchive(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load chart from archive: %w", err)
+	}
+	return ch, nil
+}
+
+// decodeManifests decodes a multi document yaml into kubernetes objects in the order of the documents.
+// Empty documents are skipped.
+func decodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	docs := releaseutil.SplitManifests(string(data))
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	objects := []*unstructured.Unstructured{}
+	for _, key := range keys {
+		obj, err := decodeObject([]byte(docs[key]))
+		if err != nil {
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// decodeObject decodes a yaml or json document into a kubernetes object.
+// Numbers are decoded as int64 or float64 like it is done by the kubernetes api machinery.
+func decodeObject(data []byte) (*unstructured.Unstructured, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := utiljson.Unmarshal(jsonData, &obj.Object); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package deployitems_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	chartloader "helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/gardener/landscapercli/pkg/deployitems"
+)
+
+func TestExpandManifestDeployItem(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name: "v1alpha1",
+			config: `{
+				"apiVersion": "manifest.deployer.landscaper.gardener.cloud/v1alpha1",
+				"kind": "ProviderConfiguration",
+				"manifests": [
+					{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "test"}},
+					{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "test"}}
+				]
+			}`,
+		},
+		{
+			name: "v1alpha2",
+			config: `{
+				"apiVersion": "manifest.deployer.landscaper.gardener.cloud/v1alpha2",
+				"kind": "ProviderConfiguration",
+				"manifests": [
+					{"policy": "manage", "manifest": {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "test"}}},
+					{"policy": "manage", "manifest": {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config", "namespace": "test"}}}
+				]
+			}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			di := newDeployItem(deployitems.ManifestDeployItemType, []byte(test.config))
+			objects, err := deployitems.NewExpander(logr.Discard(), nil, nil).Expand(context.TODO(), di)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Namespace/test", "ConfigMap/test/config"}, objectKeys(objects))
+		})
+	}
+}
+
+func TestExpandHelmDeployItem(t *testing.T) {
+	ch, err := chartloader.LoadDir("./testdata/chart")
+	assert.NoError(t, err)
+	tmpDir, err := ioutil.TempDir("", "chart")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	archivePath, err := chartutil.Save(ch, tmpDir)
+	assert.NoError(t, err)
+	archive, err := ioutil.ReadFile(archivePath)
+	assert.NoError(t, err)
+
+	config, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "helm.deployer.landscaper.gardener.cloud/v1alpha1",
+		"kind":       "ProviderConfiguration",
+		"name":       "my-release",
+		"namespace":  "my-namespace",
+		"chart": map[string]interface{}{
+			"archive": map[string]interface{}{
+				"raw": base64.StdEncoding.EncodeToString(archive),
+			},
+		},
+		"values": map[string]interface{}{
+			"replicas": 3,
+		},
+	})
+	assert.NoError(t, err)
+
+	di := newDeployItem(deployitems.HelmDeployItemType, config)
+	objects, err := deployitems.NewExpander(logr.Discard(), nil, nil).Expand(context.TODO(), di)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"CustomResourceDefinition/tests.example.com",
+		"CustomResourceDefinition/examples.example.com",
+		"ServiceAccount/my-namespace/my-release",
+		"Deployment/my-namespace/my-release",
+		"ConfigMap/my-namespace/my-release-pre-install",
+		"Pod/my-namespace/my-release-test",
+	}, objectKeys(objects), "expect the documents in their order and the hooks at the end")
+	assert.Equal(t, "test", objects[5].GetAnnotations()["helm.sh/hook"])
+
+	replicas, _, err := unstructured.NestedInt64(objects[3].Object, "spec", "replicas")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), replicas)
+}
+
+func TestExpandUnsupportedDeployItem(t *testing.T) {
+	di := newDeployItem(deployitems.ContainerDeployItemType, []byte(`{}`))
+	assert.False(t, deployitems.CanExpand(di))
+	_, err := deployitems.NewExpander(logr.Discard(), nil, nil).Expand(context.TODO(), di)
+	assert.Error(t, err)
+}
+
+func newDeployItem(diType lsv1alpha1.DeployItemType, config []byte) *lsv1alpha1.DeployItem {
+	di := &lsv1alpha1.DeployItem{}
+	di.Name = "test"
+	di.Spec.Type = diType
+	di.Spec.Configuration = &runtime.RawExtension{Raw: config}
+	return di
+}
+
+func objectKeys(objects []*unstructured.Unstructured) []string {
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		key := obj.GetKind() + "/"
+		if len(obj.GetNamespace()) != 0 {
+			key += obj.GetNamespace() + "/"
+		}
+		keys = append(keys, key+obj.GetName())
+	}
+	return keys
+}
@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscaper/pkg/landscaper/jsonschema"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/landscapercli/pkg/executions"
+)
+
+// Document describes a blueprint for its documentation.
+type Document struct {
+	// Name is the name of the blueprint.
+	Name string
+	// Imports are the imports of the blueprint including the conditional imports.
+	Imports []Parameter
+	// Exports are the exports of the blueprint.
+	Exports []Parameter
+	// DeployExecutions are the deploy executions of the blueprint.
+	DeployExecutions []Execution
+	// DeployItems are the rendered deploy items.
+	// They are only known if the blueprint has been rendered with import values.
+	DeployItems []DeployItem
+	// Subinstallations are the static subinstallations of the blueprint.
+	Subinstallations []Subinstallation
+	// SubinstallationExecutions are the executions that render further subinstallations.
+	SubinstallationExecutions []Execution
+}
+
+// Parameter describes an import or export.
+type Parameter struct {
+	// Name is the name of the import or export.
+	Name string
+	// Type is the type of the import or export, e.g. data or target.
+	Type string
+	// TargetType is the type of an imported or exported target.
+	TargetType string
+	// Required defines whether an import is required. It is always true for exports.
+	Required bool
+	// Condition is the name of the import a conditional import depends on.
+	Condition string
+	// Default is the json encoded default value of an import.
+	Default string
+	// Description is the description of the json schema.
+	Description string
+	// Schema is the json schema with all references resolved.
+	Schema string
+	// SchemaError describes why the references of the json schema could not be resolved.
+	// The schema contains the unresolved schema in this case.
+	SchemaError string
+}
+
+// Execution describes a deploy or subinstallation execution.
+type Execution struct {
+	// Name is the name of the execution.
+	Name string
+	// TemplateType is the template type of the execution.
+	TemplateType string
+	// File is the file containing the template, relative to the blueprint directory.
+	File string
+	// DeployerTypes are the deploy item types of a deploy execution.
+	DeployerTypes []string
+	// DeployerTypesGuessed is true if the deployer types have been searched in the template text
+	// instead of being taken from the rendered deploy items.
+	// Such a guess misses templated types and may contain other keys named type.
+	DeployerTypesGuessed bool
+}
+
+// DeployItem describes a rendered deploy item.
+type DeployItem struct {
+	// Installation is the path of the installation that contains the deploy item.
+	Installation string
+	// Name is the name of the deploy item.
+	Name string
+	// Type is the deployer type of the deploy item.
+	Type string
+	// Execution is the name of the deploy execution that rendered the deploy item.
+	Execution string
+}
+
+// Subinstallation describes a static subinstallation.
+type Subinstallation struct {
+	// Name is the name of the subinstallation.
+	Name string
+	// Blueprint describes the blueprint of the subinstallation, which is either a reference or inline.
+	Blueprint string
+	// File is the file that defines the subinstallation, relative to the blueprint directory.
+	File string
+	// Imports are the names of the imported data objects and targets.
+	Imports []string
+	// Exports are the names of the exported data objects and targets.
+	Exports []string
+}
+
+// deployerTypeRegexp matches the type of deploy items in a template, e.g. "type: landscaper.gardener.cloud/helm".
+var deployerTypeRegexp = regexp.MustCompile(`(?m)^[\s-]*type:\s*["']?([A-Za-z0-9.\-]+/[A-Za-z0-9.\-]+)["']?\s*$`)
+
+// Generator generates the documentation of a blueprint directory.
+type Generator struct {
+	// Fs is the filesystem of the blueprint directory.
+	Fs vfs.FileSystem
+	// ComponentDescriptor is the optional component descriptor of the blueprint.
+	// Schema references to the component descriptor are only resolved if it is set.
+	ComponentDescriptor *cdv2.ComponentDescriptor
+	// ComponentResolver resolves referenced component descriptors.
+	ComponentResolver ctf.ComponentResolver
+}
+
+// Generate creates the document of the blueprint with the given name.
+func (g *Generator) Generate(name string) (*Document, error) {
+	data, err := vfs.ReadFile(g.Fs, lsv1alpha1.BlueprintFileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	blueprint := &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+
+	resolver := jsonschema.NewReferenceResolver(&jsonschema.ReferenceContext{
+		LocalTypes:          blueprint.LocalTypes,
+		BlueprintFs:         g.Fs,
+		ComponentDescriptor: g.ComponentDescriptor,
+		ComponentResolver:   g.ComponentResolver,
+	})
+
+	doc := &Document{
+		Name: name,
+	}
+	g.addImports(doc, resolver, blueprint.Imports, "")
+	for _, exp := range blueprint.Exports {
+		param := Parameter{
+			Name:       exp.Name,
+			Type:       string(exp.Type),
+			TargetType: exp.TargetType,
+			Required:   true,
+		}
+		if len(param.Type) == 0 {
+			param.Type = defaultType(exp.TargetType)
+		}
+		setSchema(&param, resolver, exp.Schema)
+		doc.Exports = append(doc.Exports, param)
+	}
+
+	sources, err := executions.LocateExecutions(g.Fs)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		exec := Execution{
+			Name:         source.Name,
+			TemplateType: string(source.Type),
+			File:         source.File,
+		}
+		switch source.Kind {
+		case executions.DeployExecution:
+			exec.DeployerTypes = deployerTypes(source.Template)
+			exec.DeployerTypesGuessed = true
+			doc.DeployExecutions = append(doc.DeployExecutions, exec)
+		case executions.SubinstallationExecution:
+			doc.SubinstallationExecutions = append(doc.SubinstallationExecutions, exec)
+		}
+	}
+
+	for _, subinst := range blueprint.Subinstallations {
+		file := lsv1alpha1.BlueprintFileName
+		tmpl := subinst.InstallationTemplate
+		if len(subinst.File) != 0 {
+			file = strings.TrimPrefix(subinst.File, "/")
+			data, err := vfs.ReadFile(g.Fs, subinst.File)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read subinstallation file %s: %w", subinst.File, err)
+			}
+			tmpl = &lsv1alpha1.InstallationTemplate{}
+			if err := yaml.Unmarshal(data, tmpl); err != nil {
+				return nil, fmt.Errorf("unable to decode subinstallation file %s: %w", subinst.File, err)
+			}
+		}
+		if tmpl == nil {
+			continue
+		}
+		doc.Subinstallations = append(doc.Subinstallations, newSubinstallation(tmpl, file))
+	}
+	return doc, nil
+}
+
+// addImports adds the imports and recursively their conditional imports to the document.
+func (g *Generator) addImports(doc *Document, resolver *jsonschema.ReferenceResolver, imports lsv1alpha1.ImportDefinitionList, condition string) {
+	for _, imp := range imports {
+		param := Parameter{
+			Name:       imp.Name,
+			Type:       string(imp.Type),
+			TargetType: imp.TargetType,
+			Required:   imp.Required == nil || *imp.Required,
+			Condition:  condition,
+		}
+		if len(param.Type) == 0 {
+			param.Type = defaultType(imp.TargetType)
+		}
+		if imp.Default.Value.RawMessage != nil {
+			param.Default = string(imp.Default.Value.RawMessage)
+		}
+		setSchema(&param, resolver, imp.Schema)
+		doc.Imports = append(doc.Imports, param)
+		g.addImports(doc, resolver, imp.ConditionalImports, imp.Name)
+	}
+}
+
+// defaultType returns the type of an import or export that does not define its type explicitly.
+func defaultType(targetType string) string {
+	if len(targetType) != 0 {
+		return string(lsv1alpha1.ImportTypeTarget)
+	}
+	return string(lsv1alpha1.ImportTypeData)
+}
+
+// setSchema resolves the references of the schema and sets the schema and its description.
+// The unresolved schema is used if the references cannot be resolved.
+func setSchema(param *Parameter, resolver *jsonschema.ReferenceResolver, schema *lsv1alpha1.JSONSchemaDefinition) {
+	if schema == nil || schema.RawMessage == nil {
+		return
+	}
+	var resolved interface{}
+	resolved, err := resolver.Resolve(schema.RawMessage)
+	if err != nil {
+		param.SchemaError = err.Error()
+		if err := json.Unmarshal(schema.RawMessage, &resolved); err != nil {
+			param.Schema = string(schema.RawMessage)
+			return
+		}
+	}
+	if obj, ok := resolved.(map[string]interface{}); ok {
+		if description, ok := obj["description"].(string); ok {
+			param.Description = description
+		}
+	}
+	data, err := json.MarshalIndent(resolved, "", "  ")
+	if err != nil {
+		param.Schema = string(schema.RawMessage)
+		return
+	}
+	param.Schema = string(data)
+}
+
+// SetDeployItems sets the rendered deploy items. The deployer types of the deploy executions are
+// replaced by the types of the deploy items that the executions have rendered for the installation rootPath.
+func (d *Document) SetDeployItems(rootPath string, items []DeployItem) {
+	d.DeployItems = items
+	types := map[string]map[string]bool{}
+	for _, item := range items {
+		if item.Installation != rootPath {
+			continue
+		}
+		if types[item.Execution] == nil {
+			types[item.Execution] = map[string]bool{}
+		}
+		types[item.Execution][item.Type] = true
+	}
+	for i := range d.DeployExecutions {
+		exec := &d.DeployExecutions[i]
+		exec.DeployerTypes = sortedKeys(types[exec.Name])
+		exec.DeployerTypesGuessed = false
+	}
+}
+
+// deployerTypes returns the sorted deploy item types that are found in the template.
+func deployerTypes(template string) []string {
+	types := map[string]bool{}
+	for _, match := range deployerTypeRegexp.FindAllStringSubmatch(template, -1) {
+		// kubernetes objects like secrets use the same key for their own types
+		if strings.Contains(match[1], "kubernetes.io/") {
+			continue
+		}
+		types[match[1]] = true
+	}
+	return sortedKeys(types)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func newSubinstallation(tmpl *lsv1alpha1.InstallationTemplate, file string) Subinstallation {
+	subinst := Subinstallation{
+		Name: tmpl.Name,
+		File: file,
+	}
+	switch {
+	case len(tmpl.Blueprint.Ref) != 0:
+		subinst.Blueprint = tmpl.Blueprint.Ref
+	case tmpl.Blueprint.Filesystem.RawMessage != nil:
+		subinst.Blueprint = "inline"
+	}
+	for _, imp := range tmpl.Imports.Data {
+		subinst.Imports = append(subinst.Imports, imp.Name)
+	}
+	for _, imp := range tmpl.Imports.Targets {
+		subinst.Imports = append(subinst.Imports, imp.Name)
+	}
+	for _, exp := range tmpl.Exports.Data {
+		subinst.Exports = append(subinst.Exports, exp.Name)
+	}
+	for _, exp := range tmpl.Exports.Targets {
+		subinst.Exports = append(subinst.Exports, exp.Name)
+	}
+	return subinst
+}
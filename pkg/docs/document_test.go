@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package docs_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/docs"
+)
+
+func TestGenerate(t *testing.T) {
+	doc := generateTestDocument(t)
+	if doc == nil {
+		return
+	}
+
+	if assert.Len(t, doc.Imports, 5) {
+		cluster := doc.Imports[0]
+		assert.Equal(t, "cluster", cluster.Name)
+		assert.Equal(t, "target", cluster.Type)
+		assert.Equal(t, "landscaper.gardener.cloud/kubernetes-cluster", cluster.TargetType)
+		assert.True(t, cluster.Required)
+
+		namespace := doc.Imports[1]
+		assert.Equal(t, "data", namespace.Type)
+		assert.Equal(t, "Namespace of the application", namespace.Description, "expect the local type to be resolved")
+
+		config := doc.Imports[2]
+		assert.False(t, config.Required)
+		assert.Equal(t, "Configuration of the application", config.Description, "expect the blueprint file to be resolved")
+		assert.Contains(t, config.Schema, "logLevel")
+
+		replicas := doc.Imports[3]
+		assert.Equal(t, "replicas", replicas.Name)
+		assert.Equal(t, "config", replicas.Condition)
+
+		image := doc.Imports[4]
+		assert.Equal(t, `"nginx:1.21"`, image.Default)
+		assert.NotEmpty(t, image.SchemaError, "expect an error for a component descriptor reference without component descriptor")
+		assert.Contains(t, image.Schema, "cd://resources/image-schema")
+	}
+
+	if assert.Len(t, doc.Exports, 1) {
+		assert.Equal(t, "url", doc.Exports[0].Name)
+		assert.Equal(t, "URL of the application | public", doc.Exports[0].Description)
+	}
+
+	if assert.Len(t, doc.DeployExecutions, 1) {
+		exec := doc.DeployExecutions[0]
+		assert.Equal(t, "deploy-execution.yaml", exec.File)
+		assert.Equal(t, []string{"landscaper.gardener.cloud/helm", "landscaper.gardener.cloud/kubernetes-manifest"}, exec.DeployerTypes)
+		assert.True(t, exec.DeployerTypesGuessed)
+	}
+
+	if assert.Len(t, doc.Subinstallations, 2) {
+		monitoring := doc.Subinstallations[0]
+		assert.Equal(t, "monitoring", monitoring.Name)
+		assert.Equal(t, "inline", monitoring.Blueprint)
+		assert.Equal(t, "subinstallation.yaml", monitoring.File)
+		assert.Equal(t, []string{"cluster"}, monitoring.Imports)
+		assert.Equal(t, []string{"dashboard"}, monitoring.Exports)
+
+		database := doc.Subinstallations[1]
+		assert.Equal(t, "cd://componentReferences/db/resources/blueprint", database.Blueprint)
+		assert.Equal(t, "blueprint.yaml", database.File)
+		assert.Equal(t, []string{"namespace", "cluster"}, database.Imports)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	doc := generateTestDocument(t)
+	if doc == nil {
+		return
+	}
+
+	markdown := &bytes.Buffer{}
+	if assert.NoError(t, doc.Write(markdown, docs.MarkdownFormat)) {
+		assert.Contains(t, markdown.String(), "# Blueprint test")
+		assert.Contains(t, markdown.String(), "| [replicas](#import-replicas) | data | if config is set |  |  |")
+		assert.Contains(t, markdown.String(), "| [url](#export-url) | data | URL of the application \\| public |")
+		assert.Contains(t, markdown.String(), "| default | GoTemplate | `deploy-execution.yaml` | landscaper.gardener.cloud/helm, landscaper.gardener.cloud/kubernetes-manifest (guessed) |")
+		assert.Contains(t, markdown.String(), "Deployer types marked as guessed")
+	}
+
+	html := &bytes.Buffer{}
+	if assert.NoError(t, doc.Write(html, docs.HTMLFormat)) {
+		assert.Contains(t, html.String(), `<h3 id="import-namespace">import namespace</h3>`)
+		assert.Contains(t, html.String(), "&#34;logLevel&#34;", "expect the schema to be escaped")
+	}
+
+	assert.Error(t, doc.Write(&bytes.Buffer{}, "pdf"))
+}
+
+func TestSetDeployItems(t *testing.T) {
+	doc := generateTestDocument(t)
+	if doc == nil {
+		return
+	}
+
+	doc.SetDeployItems("root", []docs.DeployItem{
+		{Installation: "root", Name: "app", Type: "landscaper.gardener.cloud/container", Execution: "default"},
+		{Installation: "root/monitoring", Name: "dashboard", Type: "landscaper.gardener.cloud/helm", Execution: "default"},
+	})
+	if assert.Len(t, doc.DeployExecutions, 1) {
+		exec := doc.DeployExecutions[0]
+		assert.Equal(t, []string{"landscaper.gardener.cloud/container"}, exec.DeployerTypes, "expect only the types of the root installation")
+		assert.False(t, exec.DeployerTypesGuessed)
+	}
+
+	markdown := &bytes.Buffer{}
+	if assert.NoError(t, doc.Write(markdown, docs.MarkdownFormat)) {
+		assert.Contains(t, markdown.String(), "| default | GoTemplate | `deploy-execution.yaml` | landscaper.gardener.cloud/container |")
+		assert.NotContains(t, markdown.String(), "guessed")
+	}
+}
+
+func generateTestDocument(t *testing.T) *docs.Document {
+	fs, err := projectionfs.New(osfs.New(), "./testdata/blueprint")
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	generator := &docs.Generator{Fs: fs}
+	doc, err := generator.Generate("test")
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return doc
+}
@@ -0,0 +1,295 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package docs
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+)
+
+const (
+	// MarkdownFormat writes the documentation as Markdown.
+	MarkdownFormat = "markdown"
+	// HTMLFormat writes the documentation as standalone HTML page.
+	HTMLFormat = "html"
+)
+
+// Write writes the documentation in the given format.
+func (d *Document) Write(w io.Writer, format string) error {
+	switch format {
+	case MarkdownFormat:
+		return markdownTemplate.Execute(w, d)
+	case HTMLFormat:
+		return htmlTemplate.Execute(w, d)
+	default:
+		return fmt.Errorf("unknown documentation format %q: expected %s or %s", format, MarkdownFormat, HTMLFormat)
+	}
+}
+
+// RequiredText returns a human readable description whether the parameter is required.
+func (p Parameter) RequiredText() string {
+	switch {
+	case len(p.Condition) != 0 && p.Required:
+		return fmt.Sprintf("if %s is set", p.Condition)
+	case p.Required:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// TypeText returns the type of the parameter including the target type.
+func (p Parameter) TypeText() string {
+	if len(p.TargetType) != 0 {
+		return fmt.Sprintf("%s (%s)", p.Type, p.TargetType)
+	}
+	return p.Type
+}
+
+// GuessedDeployerTypes returns true if the deployer types of a deploy execution have been guessed from its template.
+func (d *Document) GuessedDeployerTypes() bool {
+	for _, exec := range d.DeployExecutions {
+		if exec.DeployerTypesGuessed {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownCell escapes a value so that it can be used in a cell of a markdown table.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(strings.TrimSpace(value), "\n", "<br>")
+}
+
+// markdownCode formats a value as inline code or returns an empty string for empty values.
+func markdownCode(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+	return "`" + markdownCell(value) + "`"
+}
+
+// anchor returns a html anchor id for a parameter.
+func anchor(kind, name string) string {
+	return strings.ToLower(kind + "-" + strings.NewReplacer(" ", "-", ".", "-", "/", "-").Replace(name))
+}
+
+// parameterSection is the input of the template of the section that describes a parameter.
+type parameterSection struct {
+	Kind      string
+	Parameter Parameter
+}
+
+var templateFuncs = map[string]interface{}{
+	"cell":   markdownCell,
+	"code":   markdownCode,
+	"join":   strings.Join,
+	"anchor": anchor,
+	"param": func(kind string, p Parameter) parameterSection {
+		return parameterSection{Kind: kind, Parameter: p}
+	},
+}
+
+var markdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(templateFuncs).Parse(
+	`# Blueprint {{ .Name }}
+
+## Imports
+{{ if .Imports }}
+| Name | Type | Required | Default | Description |
+| ---- | ---- | -------- | ------- | ----------- |
+{{- range .Imports }}
+| [{{ .Name }}](#{{ anchor "import" .Name }}) | {{ cell .TypeText }} | {{ cell .RequiredText }} | {{ code .Default }} | {{ cell .Description }} |
+{{- end }}
+{{ range .Imports }}{{ template "parameter" (param "import" .) }}{{ end }}
+{{- else }}
+The blueprint has no imports.
+{{ end }}
+## Exports
+{{ if .Exports }}
+| Name | Type | Description |
+| ---- | ---- | ----------- |
+{{- range .Exports }}
+| [{{ .Name }}](#{{ anchor "export" .Name }}) | {{ cell .TypeText }} | {{ cell .Description }} |
+{{- end }}
+{{ range .Exports }}{{ template "parameter" (param "export" .) }}{{ end }}
+{{- else }}
+The blueprint has no exports.
+{{ end }}
+## Deploy Executions
+{{ if .DeployExecutions }}
+| Name | Template Type | File | Deployer Types |
+| ---- | ------------- | ---- | -------------- |
+{{- range .DeployExecutions }}
+| {{ cell .Name }} | {{ cell .TemplateType }} | {{ code .File }} | {{ cell (join .DeployerTypes ", ") }}{{ if .DeployerTypesGuessed }} (guessed){{ end }} |
+{{- end }}
+{{ if .GuessedDeployerTypes }}
+Deployer types marked as guessed have been searched in the template text. Types that are templated are missing,
+and other keys named type may be listed. Generate the documentation with import values to get the types of the rendered deploy items.
+{{ end }}{{ else }}
+The blueprint has no deploy executions.
+{{ end }}
+{{- if .DeployItems }}
+## Deploy Items
+
+The deploy items have been rendered with the given import values.
+
+| Installation | Name | Deployer Type | Execution |
+| ------------ | ---- | ------------- | --------- |
+{{- range .DeployItems }}
+| {{ cell .Installation }} | {{ cell .Name }} | {{ cell .Type }} | {{ cell .Execution }} |
+{{- end }}
+{{ end }}
+## Subinstallations
+{{ if or .Subinstallations .SubinstallationExecutions }}
+{{- if .Subinstallations }}
+| Name | Blueprint | File | Imports | Exports |
+| ---- | --------- | ---- | ------- | ------- |
+{{- range .Subinstallations }}
+| {{ cell .Name }} | {{ code .Blueprint }} | {{ code .File }} | {{ cell (join .Imports ", ") }} | {{ cell (join .Exports ", ") }} |
+{{- end }}
+{{ end }}
+{{- if .SubinstallationExecutions }}
+Further subinstallations are rendered by the following executions:
+
+| Name | Template Type | File |
+| ---- | ------------- | ---- |
+{{- range .SubinstallationExecutions }}
+| {{ cell .Name }} | {{ cell .TemplateType }} | {{ code .File }} |
+{{- end }}
+{{ end }}
+{{- else }}
+The blueprint has no subinstallations.
+{{ end }}
+{{- define "parameter" }}{{ with .Parameter }}
+### <a id="{{ anchor $.Kind .Name }}"></a>{{ $.Kind }} {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{- if .TargetType }}
+Target type: ` + "`{{ .TargetType }}`" + `
+{{ end }}
+{{- if .Schema }}
+{{- if .SchemaError }}
+The references of the JSON schema could not be resolved: {{ .SchemaError }}
+{{ end }}
+` + "```json" + `
+{{ .Schema }}
+` + "```" + `
+{{ end }}
+{{- end }}{{ end }}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Blueprint {{ .Name }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>Blueprint {{ .Name }}</h1>
+
+<h2>Imports</h2>
+{{- if .Imports }}
+<table>
+<tr><th>Name</th><th>Type</th><th>Required</th><th>Default</th><th>Description</th></tr>
+{{- range .Imports }}
+<tr><td><a href="#{{ anchor "import" .Name }}">{{ .Name }}</a></td><td>{{ .TypeText }}</td><td>{{ .RequiredText }}</td><td><code>{{ .Default }}</code></td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- range .Imports }}{{ template "parameter" (param "import" .) }}{{ end }}
+{{- else }}
+<p>The blueprint has no imports.</p>
+{{- end }}
+
+<h2>Exports</h2>
+{{- if .Exports }}
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{- range .Exports }}
+<tr><td><a href="#{{ anchor "export" .Name }}">{{ .Name }}</a></td><td>{{ .TypeText }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- range .Exports }}{{ template "parameter" (param "export" .) }}{{ end }}
+{{- else }}
+<p>The blueprint has no exports.</p>
+{{- end }}
+
+<h2>Deploy Executions</h2>
+{{- if .DeployExecutions }}
+<table>
+<tr><th>Name</th><th>Template Type</th><th>File</th><th>Deployer Types</th></tr>
+{{- range .DeployExecutions }}
+<tr><td>{{ .Name }}</td><td>{{ .TemplateType }}</td><td><code>{{ .File }}</code></td><td>{{ join .DeployerTypes ", " }}{{ if .DeployerTypesGuessed }} (guessed){{ end }}</td></tr>
+{{- end }}
+</table>
+{{- if .GuessedDeployerTypes }}
+<p>Deployer types marked as guessed have been searched in the template text. Types that are templated are missing,
+and other keys named type may be listed. Generate the documentation with import values to get the types of the rendered deploy items.</p>
+{{- end }}
+{{- else }}
+<p>The blueprint has no deploy executions.</p>
+{{- end }}
+{{- if .DeployItems }}
+
+<h2>Deploy Items</h2>
+<p>The deploy items have been rendered with the given import values.</p>
+<table>
+<tr><th>Installation</th><th>Name</th><th>Deployer Type</th><th>Execution</th></tr>
+{{- range .DeployItems }}
+<tr><td>{{ .Installation }}</td><td>{{ .Name }}</td><td>{{ .Type }}</td><td>{{ .Execution }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+
+<h2>Subinstallations</h2>
+{{- if or .Subinstallations .SubinstallationExecutions }}
+{{- if .Subinstallations }}
+<table>
+<tr><th>Name</th><th>Blueprint</th><th>File</th><th>Imports</th><th>Exports</th></tr>
+{{- range .Subinstallations }}
+<tr><td>{{ .Name }}</td><td><code>{{ .Blueprint }}</code></td><td><code>{{ .File }}</code></td><td>{{ join .Imports ", " }}</td><td>{{ join .Exports ", " }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .SubinstallationExecutions }}
+<p>Further subinstallations are rendered by the following executions:</p>
+<table>
+<tr><th>Name</th><th>Template Type</th><th>File</th></tr>
+{{- range .SubinstallationExecutions }}
+<tr><td>{{ .Name }}</td><td>{{ .TemplateType }}</td><td><code>{{ .File }}</code></td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- else }}
+<p>The blueprint has no subinstallations.</p>
+{{- end }}
+</body>
+</html>
+{{- define "parameter" }}{{ with .Parameter }}
+<h3 id="{{ anchor $.Kind .Name }}">{{ $.Kind }} {{ .Name }}</h3>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+{{- if .TargetType }}
+<p>Target type: <code>{{ .TargetType }}</code></p>
+{{- end }}
+{{- if .Schema }}
+{{- if .SchemaError }}
+<p>The references of the JSON schema could not be resolved: {{ .SchemaError }}</p>
+{{- end }}
+<pre><code>{{ .Schema }}</code></pre>
+{{- end }}
+{{- end }}{{ end }}
+`))
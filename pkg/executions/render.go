@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executions
+
+import (
+	"fmt"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/landscaper/blueprints"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/spiff"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+)
+
+// RenderedDeployItem is a deploy item that has been rendered from a deploy execution.
+type RenderedDeployItem struct {
+	// Execution is the name of the deploy execution.
+	Execution string
+	// Name is the name of the deploy item.
+	Name string
+	// Type is the type of the deploy item.
+	Type string
+}
+
+// NewTemplaterFunc creates the templater for an execution.
+// Every execution gets its own templater, so that the executions do not share their template state.
+type NewTemplaterFunc func() (*template.Templater, error)
+
+// RenderDeployItems templates every deploy execution of the blueprint on its own with the given imports,
+// like landscaper does but without a component descriptor.
+// The errors of executions that cannot be rendered, e.g. because they use the component descriptor,
+// are returned by the name of the execution.
+func RenderDeployItems(fs vfs.FileSystem, blueprint *lsv1alpha1.Blueprint, imports map[string]interface{}) ([]RenderedDeployItem, map[string]error) {
+	newTemplater := func() (*template.Templater, error) {
+		stateHandler := template.NewMemoryStateHandler()
+		return template.New(gotemplate.New(nil, stateHandler), spiff.New(stateHandler)), nil
+	}
+	return TemplateDeployExecutions(newTemplater, template.DeployExecutionOptions{
+		Imports:   imports,
+		Blueprint: blueprints.New(blueprint, fs),
+	})
+}
+
+// TemplateDeployExecutions templates every deploy execution of the blueprint of the options on its own.
+// The errors of executions that cannot be rendered are returned by the name of the execution.
+func TemplateDeployExecutions(newTemplater NewTemplaterFunc, opts template.DeployExecutionOptions) ([]RenderedDeployItem, map[string]error) {
+	deployItems := []RenderedDeployItem{}
+	errs := map[string]error{}
+	for _, execution := range opts.Blueprint.Info.DeployExecutions {
+		info := *opts.Blueprint.Info
+		info.DeployExecutions = []lsv1alpha1.TemplateExecutor{execution}
+		info.SubinstallationExecutions = nil
+
+		templater, err := newTemplater()
+		if err != nil {
+			errs[execution.Name] = err
+			continue
+		}
+		executionOpts := opts
+		executionOpts.Blueprint = blueprints.New(&info, opts.Blueprint.Fs)
+		templates, err := templater.TemplateDeployExecutions(executionOpts)
+		if err != nil {
+			errs[execution.Name] = err
+			continue
+		}
+		for _, tmpl := range templates {
+			deployItems = append(deployItems, RenderedDeployItem{
+				Execution: execution.Name,
+				Name:      tmpl.Name,
+				Type:      string(tmpl.Type),
+			})
+		}
+	}
+	return deployItems, errs
+}
+
+// DeployItemSources returns the name of the deploy execution for each of the given deploy items
+// that have been rendered from the blueprint of the options.
+// The executions are only templated on their own if the blueprint has more than one deploy execution.
+func DeployItemSources(newTemplater NewTemplaterFunc, opts template.DeployExecutionOptions, deployItemNames []string) (map[string]string, error) {
+	sources := map[string]string{}
+	if len(opts.Blueprint.Info.DeployExecutions) == 1 {
+		for _, name := range deployItemNames {
+			sources[name] = opts.Blueprint.Info.DeployExecutions[0].Name
+		}
+		return sources, nil
+	}
+
+	deployItems, errs := TemplateDeployExecutions(newTemplater, opts)
+	for _, execution := range opts.Blueprint.Info.DeployExecutions {
+		if err, ok := errs[execution.Name]; ok {
+			return nil, fmt.Errorf("unable to template deploy execution %q: %w", execution.Name, err)
+		}
+	}
+	for _, di := range deployItems {
+		sources[di.Name] = di.Execution
+	}
+	return sources, nil
+}
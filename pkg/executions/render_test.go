@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executions_test
+
+import (
+	"errors"
+	"testing"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/landscaper/blueprints"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/spiff"
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/executions"
+)
+
+func TestRenderDeployItems(t *testing.T) {
+	blueprint := testBlueprint("first", "second")
+	deployItems, errs := executions.RenderDeployItems(memoryfs.New(), blueprint, map[string]interface{}{"value": map[string]interface{}{"name": "a"}})
+	assert.Empty(t, errs)
+	assert.Equal(t, []executions.RenderedDeployItem{
+		{Execution: "first", Name: "first-a", Type: "mock"},
+		{Execution: "second", Name: "second-a", Type: "mock"},
+	}, deployItems)
+
+	_, errs = executions.RenderDeployItems(memoryfs.New(), blueprint, map[string]interface{}{})
+	assert.Len(t, errs, 2, "expect an error for every execution that cannot be templated without the import")
+}
+
+func TestDeployItemSources(t *testing.T) {
+	opts := template.DeployExecutionOptions{
+		Imports:   map[string]interface{}{"value": map[string]interface{}{"name": "a"}},
+		Blueprint: blueprints.New(testBlueprint("first", "second"), memoryfs.New()),
+	}
+	templated := 0
+	newTemplater := func() (*template.Templater, error) {
+		templated++
+		stateHandler := template.NewMemoryStateHandler()
+		return template.New(gotemplate.New(nil, stateHandler), spiff.New(stateHandler)), nil
+	}
+
+	sources, err := executions.DeployItemSources(newTemplater, opts, []string{"first-a", "second-a"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"first-a": "first", "second-a": "second"}, sources)
+		assert.Equal(t, 2, templated)
+	}
+
+	opts.Blueprint = blueprints.New(testBlueprint("first"), memoryfs.New())
+	templated = 0
+	sources, err = executions.DeployItemSources(newTemplater, opts, []string{"first-a"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"first-a": "first"}, sources)
+		assert.Equal(t, 0, templated, "expect a single execution not to be templated again")
+	}
+
+	opts.Blueprint = blueprints.New(testBlueprint("first", "second"), memoryfs.New())
+	_, err = executions.DeployItemSources(func() (*template.Templater, error) {
+		return nil, errors.New("templater error")
+	}, opts, []string{"first-a", "second-a"})
+	assert.EqualError(t, err, `unable to template deploy execution "first": templater error`)
+}
+
+// testBlueprint returns a blueprint with a deploy execution for each name,
+// which renders the deploy item "<name>-<name of the value import>".
+func testBlueprint(names ...string) *lsv1alpha1.Blueprint {
+	blueprint := &lsv1alpha1.Blueprint{}
+	for _, name := range names {
+		blueprint.DeployExecutions = append(blueprint.DeployExecutions, lsv1alpha1.TemplateExecutor{
+			Name:     name,
+			Type:     lsv1alpha1.GOTemplateType,
+			Template: lsv1alpha1.NewAnyJSON([]byte(`"deployItems:\n- name: ` + name + `-{{ .imports.value.name }}\n  type: mock\n  config: {}\n"`)),
+		})
+	}
+	return blueprint
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executions
+
+import (
+	"fmt"
+	"strings"
+	gotmpl "text/template"
+
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Kind is the kind of a template execution, i.e. the name of the list in the blueprint that defines it.
+type Kind string
+
+const (
+	DeployExecution          Kind = "deployExecutions"
+	SubinstallationExecution Kind = "subinstallationExecutions"
+	ExportExecution          Kind = "exportExecutions"
+)
+
+// Source describes where the template of an execution is defined in the blueprint directory.
+type Source struct {
+	// Kind is the kind of the execution.
+	Kind Kind
+	// Index is the index of the execution in the list of executions of its kind.
+	Index int
+	// Name is the name of the execution.
+	Name string
+	// Type is the template type of the execution.
+	Type lsv1alpha1.TemplateType
+	// File is the path of the file containing the template relative to the blueprint directory.
+	// It is the blueprint.yaml for inline templates and the execution file otherwise.
+	File string
+	// Template is the raw template.
+	Template string
+	// FileContent is the content of the file containing the template.
+	FileContent string
+
+	// lineOffset is the number of lines in front of the first template line.
+	lineOffset int
+	// columnOffset is the number of characters in front of every template line.
+	columnOffset int
+}
+
+// Position returns the line and column in the source file for the given line and column in the template.
+// Lines and columns are 1-based, a column of 0 means that the column is unknown.
+func (s *Source) Position(line, column int) (int, int) {
+	if column == 0 {
+		return line + s.lineOffset, 0
+	}
+	return line + s.lineOffset, column + s.columnOffset
+}
+
+// String returns a short description of the execution, e.g. `deploy execution "my-execution"`.
+func (s *Source) String() string {
+	switch s.Kind {
+	case DeployExecution:
+		return fmt.Sprintf("deploy execution %q", s.Name)
+	case SubinstallationExecution:
+		return fmt.Sprintf("subinstallation execution %q", s.Name)
+	case ExportExecution:
+		return fmt.Sprintf("export execution %q", s.Name)
+	default:
+		return fmt.Sprintf("execution %q", s.Name)
+	}
+}
+
+// LocateExecutions returns the sources of all deploy, subinstallation and export executions
+// that are defined in the blueprint.yaml of the given blueprint filesystem.
+func LocateExecutions(fs vfs.FileSystem) ([]*Source, error) {
+	data, err := vfs.ReadFile(fs, lsv1alpha1.BlueprintFileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	sources := []*Source{}
+	for _, kind := range []Kind{DeployExecution, SubinstallationExecution, ExportExecution} {
+		list := mappingValue(doc.Content[0], string(kind))
+		if list == nil || list.Kind != yamlv3.SequenceNode {
+			continue
+		}
+		for i, item := range list.Content {
+			source := &Source{
+				Kind:  kind,
+				Index: i,
+			}
+			if node := mappingValue(item, "name"); node != nil {
+				source.Name = node.Value
+			}
+			if node := mappingValue(item, "type"); node != nil {
+				source.Type = lsv1alpha1.TemplateType(node.Value)
+			}
+
+			if node := mappingValue(item, "file"); node != nil && len(node.Value) != 0 {
+				fileData, err := vfs.ReadFile(fs, node.Value)
+				if err != nil {
+					return nil, fmt.Errorf("unable to read file %s of %s: %w", node.Value, source, err)
+				}
+				source.File = strings.TrimPrefix(node.Value, "/")
+				source.Template = string(fileData)
+				source.FileContent = string(fileData)
+			} else if node := mappingValue(item, "template"); node != nil {
+				source.File = lsv1alpha1.BlueprintFileName
+				source.Template = node.Value
+				source.FileContent = string(data)
+				source.lineOffset, source.columnOffset = inlineOffsets(node, lines)
+			}
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+// Find returns the source of the execution with the given kind and name or nil if no such execution exists.
+func Find(sources []*Source, kind Kind, name string) *Source {
+	for _, source := range sources {
+		if source.Kind == kind && source.Name == name {
+			return source
+		}
+	}
+	return nil
+}
+
+// inlineOffsets calculates the line and column offsets of an inline template.
+func inlineOffsets(node *yamlv3.Node, lines []string) (int, int) {
+	switch node.Style {
+	case yamlv3.LiteralStyle, yamlv3.FoldedStyle:
+		// the content of block scalars starts in the line after the indicator
+		// and is indented like its first non-empty line.
+		for i := node.Line; i < len(lines); i++ {
+			trimmed := strings.TrimLeft(lines[i], " ")
+			if len(trimmed) != 0 {
+				return node.Line, len(lines[i]) - len(trimmed)
+			}
+		}
+		return node.Line, 0
+	case yamlv3.DoubleQuotedStyle, yamlv3.SingleQuotedStyle:
+		return node.Line - 1, node.Column
+	default:
+		return node.Line - 1, node.Column - 1
+	}
+}
+
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ParseTemplate parses the go template of the execution without executing it.
+// Spiff templates are not checked. Parse errors are returned as TemplateError
+// that lists the given keys of the imports that are declared by the blueprint.
+func (s *Source) ParseTemplate(importKeys []string) error {
+	if s.Type != lsv1alpha1.GOTemplateType {
+		return nil
+	}
+	_, err := gotmpl.New(string(s.Kind)).
+		Funcs(gotemplate.LandscaperSprigFuncMap()).
+		Funcs(gotemplate.LandscaperTplFuncMap(nil, nil, nil, nil)).
+		Parse(s.Template)
+	if err != nil {
+		return NewTemplateError(s, err, importKeys)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executions_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	gotmpl "text/template"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/executions"
+)
+
+func TestLocateExecutions(t *testing.T) {
+	sources := locateTestExecutions(t)
+	if !assert.Len(t, sources, 3) {
+		return
+	}
+
+	inline := executions.Find(sources, executions.DeployExecution, "inline")
+	if assert.NotNil(t, inline) {
+		assert.Equal(t, "blueprint.yaml", inline.File)
+		line, column := inline.Position(5, 15)
+		assert.Equal(t, 19, line, "expect the line in the blueprint.yaml")
+		assert.Equal(t, 19, column, "expect the column in the blueprint.yaml")
+	}
+
+	fromFile := executions.Find(sources, executions.DeployExecution, "from-file")
+	if assert.NotNil(t, fromFile) {
+		assert.Equal(t, "deploy-execution.yaml", fromFile.File)
+		line, column := fromFile.Position(5, 11)
+		assert.Equal(t, 5, line)
+		assert.Equal(t, 11, column)
+	}
+
+	quoted := executions.Find(sources, executions.SubinstallationExecution, "quoted")
+	if assert.NotNil(t, quoted) {
+		line, column := quoted.Position(1, 19)
+		assert.Equal(t, 27, line)
+		assert.Equal(t, 32, column)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	sources := locateTestExecutions(t)
+
+	assert.NoError(t, executions.Find(sources, executions.DeployExecution, "inline").ParseTemplate(nil))
+
+	err := executions.Find(sources, executions.DeployExecution, "from-file").ParseTemplate(nil)
+	var tmplErr *executions.TemplateError
+	if assert.True(t, errors.As(err, &tmplErr)) {
+		assert.Equal(t, "deploy-execution.yaml:5", tmplErr.Location())
+		assert.Contains(t, tmplErr.Message, `function "invalidFunc" not defined`)
+		assert.Contains(t, tmplErr.Snippet(), "> 5 |     name: {{ .imports.cluster.metadata.name | invalidFunc }}")
+	}
+
+	err = executions.Find(sources, executions.SubinstallationExecution, "quoted").ParseTemplate(nil)
+	if assert.True(t, errors.As(err, &tmplErr)) {
+		assert.Equal(t, "blueprint.yaml:27", tmplErr.Location())
+	}
+}
+
+func TestTemplateErrorOfExecution(t *testing.T) {
+	sources := locateTestExecutions(t)
+	inline := executions.Find(sources, executions.DeployExecution, "inline")
+
+	imports := map[string]interface{}{
+		"replicas": 3,
+		"cluster":  map[string]interface{}{},
+	}
+	tmpl, err := gotmpl.New("deploy execution").Option("missingkey=zero").Parse(inline.Template)
+	assert.NoError(t, err)
+	execErr := tmpl.Execute(&bytes.Buffer{}, map[string]interface{}{"imports": imports})
+	assert.Error(t, execErr)
+
+	tmplErr := executions.NewTemplateError(inline, execErr, executions.ImportKeys(imports))
+	assert.Equal(t, "blueprint.yaml:19:30", tmplErr.Location())
+	assert.Equal(t, []string{"cluster", "replicas"}, tmplErr.ImportKeys)
+	assert.Contains(t, tmplErr.Error(), `blueprint.yaml:19:30: error in deploy execution "inline": executing "deploy execution" at <.imports.replicas.value>`)
+	assert.Contains(t, tmplErr.Error(), "available imports: cluster, replicas")
+	assert.Contains(t, tmplErr.Snippet(), ">")
+}
+
+func locateTestExecutions(t *testing.T) []*executions.Source {
+	fs, err := projectionfs.New(osfs.New(), "./testdata/blueprint")
+	assert.NoError(t, err)
+	sources, err := executions.LocateExecutions(fs)
+	assert.NoError(t, err)
+	return sources
+}
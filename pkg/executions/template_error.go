@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+)
+
+// snippetContext is the number of lines that are shown before and after the erroneous line.
+const snippetContext = 2
+
+// goTemplateErrorRegexp matches the location of go template parse and execution errors,
+// e.g. "template: deploy execution:3:12: executing ...".
+var goTemplateErrorRegexp = regexp.MustCompile(`^template: [^:]*:([0-9]+)(?::([0-9]+))?: (.*)$`)
+
+// TemplateError is an error of a template execution that is mapped to the source of the template.
+type TemplateError struct {
+	// Source is the source of the failed execution.
+	Source *Source
+	// Line is the line of the error in the source file or 0 if it is unknown.
+	Line int
+	// Column is the column of the error in the source file or 0 if it is unknown.
+	Column int
+	// Message is the error message without location information.
+	Message string
+	// ImportKeys are the keys of the imports that are available in the template.
+	ImportKeys []string
+	// Err is the original error.
+	Err error
+}
+
+// NewTemplateError maps the error of the given execution to the source of the execution.
+// The import keys are the keys of the imports that are available in the template.
+func NewTemplateError(source *Source, err error, importKeys []string) *TemplateError {
+	tmplErr := &TemplateError{
+		Source:     source,
+		Message:    stripDetails(err.Error()),
+		ImportKeys: importKeys,
+		Err:        err,
+	}
+
+	firstLine := strings.SplitN(tmplErr.Message, "\n", 2)[0]
+	if m := goTemplateErrorRegexp.FindStringSubmatch(firstLine); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		column := 0
+		if len(m[2]) != 0 {
+			// go template columns are 0-based byte offsets
+			column, _ = strconv.Atoi(m[2])
+			column++
+		}
+		tmplErr.Line, tmplErr.Column = source.Position(line, column)
+		tmplErr.Message = m[3]
+	}
+	return tmplErr
+}
+
+// ImportKeys returns the sorted keys of the given imports.
+func ImportKeys(imports map[string]interface{}) []string {
+	keys := make([]string, 0, len(imports))
+	for key := range imports {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Location returns the location of the error as "file:line:column".
+func (e *TemplateError) Location() string {
+	switch {
+	case e.Line == 0:
+		return e.Source.File
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d", e.Source.File, e.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", e.Source.File, e.Line, e.Column)
+	}
+}
+
+// Snippet returns the erroneous line with some surrounding lines of the source file.
+// An empty string is returned if the line of the error is unknown.
+func (e *TemplateError) Snippet() string {
+	lines := strings.Split(e.Source.FileContent, "\n")
+	if e.Line == 0 || e.Line > len(lines) {
+		return ""
+	}
+	first := e.Line - snippetContext
+	if first < 1 {
+		first = 1
+	}
+	last := e.Line + snippetContext
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+
+	var sb strings.Builder
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == e.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(&sb, "%s %*d | %s\n", marker, width, i, lines[i-1])
+		if i == e.Line && e.Column > 0 {
+			fmt.Fprintf(&sb, "  %s | %s^\n", strings.Repeat(" ", width), strings.Repeat(" ", e.Column-1))
+		}
+	}
+	return sb.String()
+}
+
+// Error returns the error message with the location, the snippet and the available imports.
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s: error in %s: %s\n%s", e.Location(), e.Source, e.Message, e.Details())
+}
+
+// Details returns the snippet of the source file followed by the available imports.
+func (e *TemplateError) Details() string {
+	var sb strings.Builder
+	sb.WriteString(e.Snippet())
+	if len(e.ImportKeys) == 0 {
+		sb.WriteString("no imports available")
+	} else {
+		fmt.Fprintf(&sb, "available imports: %s", strings.Join(e.ImportKeys, ", "))
+	}
+	return sb.String()
+}
+
+// Unwrap returns the original error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// stripDetails removes the template source and input that are added to template errors by the landscaper,
+// as they are replaced by the snippet of the source file and the import keys.
+func stripDetails(msg string) string {
+	for _, marker := range []string{"\ntemplate source:", "\ntemplate input:"} {
+		if i := strings.Index(msg, marker); i != -1 {
+			msg = msg[:i]
+		}
+	}
+	return strings.TrimSpace(msg)
+}
+
+// IsTemplateError returns whether the error has been caused by the execution of a go template.
+func IsTemplateError(err error) bool {
+	var (
+		tmplErr    *gotemplate.TemplateError
+		noValueErr *gotemplate.NoValueError
+	)
+	return errors.As(err, &tmplErr) || errors.As(err, &noValueErr)
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Status is the readiness of a kubernetes object.
+type Status string
+
+const (
+	// Ready means that the object is ready or that there is no readiness check for its kind.
+	Ready Status = "Ready"
+	// NotReady means that the object exists but is not ready.
+	NotReady Status = "NotReady"
+	// Missing means that the object does not exist.
+	Missing Status = "Missing"
+	// Unknown means that the readiness of the object could not be determined.
+	Unknown Status = "Unknown"
+)
+
+// Result is the result of the readiness check of an object.
+type Result struct {
+	Status Status `json:"status"`
+	// Message describes why the object is not ready.
+	Message string `json:"message,omitempty"`
+}
+
+// checkFunc checks the readiness of an object that has been converted into its typed representation.
+type checkFunc func(obj *unstructured.Unstructured) (Result, error)
+
+// checks contains the readiness checks by group and kind. They correspond to the default readiness checks
+// of the manifest and helm deployers, extended by some further kinds.
+var checks = map[string]checkFunc{
+	"apps/Deployment":        checkDeployment,
+	"apps/StatefulSet":       checkStatefulSet,
+	"apps/DaemonSet":         checkDaemonSet,
+	"apps/ReplicaSet":        checkReplicaSet,
+	"batch/Job":              checkJob,
+	"/Pod":                   checkPod,
+	"/PersistentVolumeClaim": checkPersistentVolumeClaim,
+	"/ReplicationController": checkReplicationController,
+}
+
+// Check returns the readiness of an object. Objects of kinds without readiness check are ready if they exist.
+func Check(obj *unstructured.Unstructured) Result {
+	gvk := obj.GroupVersionKind()
+	check, ok := checks[fmt.Sprintf("%s/%s", gvk.Group, gvk.Kind)]
+	if !ok {
+		return Result{Status: Ready}
+	}
+	result, err := check(obj)
+	if err != nil {
+		return Result{Status: Unknown, Message: err.Error()}
+	}
+	return result
+}
+
+func ready() (Result, error) {
+	return Result{Status: Ready}, nil
+}
+
+func notReady(format string, args ...interface{}) (Result, error) {
+	return Result{Status: NotReady, Message: fmt.Sprintf(format, args...)}, nil
+}
+
+func convert(obj *unstructured.Unstructured, typed interface{}) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return fmt.Errorf("cannot convert %s: %w", obj.GetKind(), err)
+	}
+	return nil
+}
+
+func replicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func checkDeployment(obj *unstructured.Unstructured) (Result, error) {
+	deployment := &appsv1.Deployment{}
+	if err := convert(obj, deployment); err != nil {
+		return Result{}, err
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return notReady("observed generation %d is outdated", deployment.Status.ObservedGeneration)
+	}
+	desired := replicas(deployment.Spec.Replicas)
+	if deployment.Status.UpdatedReplicas < desired {
+		return notReady("%d of %d replicas updated", deployment.Status.UpdatedReplicas, desired)
+	}
+	if deployment.Status.AvailableReplicas < desired {
+		return notReady("%d of %d replicas available", deployment.Status.AvailableReplicas, desired)
+	}
+	return ready()
+}
+
+func checkStatefulSet(obj *unstructured.Unstructured) (Result, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	if err := convert(obj, statefulSet); err != nil {
+		return Result{}, err
+	}
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return notReady("observed generation %d is outdated", statefulSet.Status.ObservedGeneration)
+	}
+	desired := replicas(statefulSet.Spec.Replicas)
+	if statefulSet.Status.ReadyReplicas < desired {
+		return notReady("%d of %d replicas ready", statefulSet.Status.ReadyReplicas, desired)
+	}
+	return ready()
+}
+
+func checkDaemonSet(obj *unstructured.Unstructured) (Result, error) {
+	daemonSet := &appsv1.DaemonSet{}
+	if err := convert(obj, daemonSet); err != nil {
+		return Result{}, err
+	}
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return notReady("observed generation %d is outdated", daemonSet.Status.ObservedGeneration)
+	}
+	if daemonSet.Status.UpdatedNumberScheduled < daemonSet.Status.DesiredNumberScheduled {
+		return notReady("%d of %d pods updated", daemonSet.Status.UpdatedNumberScheduled, daemonSet.Status.DesiredNumberScheduled)
+	}
+	if daemonSet.Status.NumberReady < daemonSet.Status.DesiredNumberScheduled {
+		return notReady("%d of %d pods ready", daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled)
+	}
+	return ready()
+}
+
+func checkReplicaSet(obj *unstructured.Unstructured) (Result, error) {
+	replicaSet := &appsv1.ReplicaSet{}
+	if err := convert(obj, replicaSet); err != nil {
+		return Result{}, err
+	}
+	desired := replicas(replicaSet.Spec.Replicas)
+	if replicaSet.Status.ReadyReplicas < desired {
+		return notReady("%d of %d replicas ready", replicaSet.Status.ReadyReplicas, desired)
+	}
+	return ready()
+}
+
+func checkReplicationController(obj *unstructured.Unstructured) (Result, error) {
+	controller := &corev1.ReplicationController{}
+	if err := convert(obj, controller); err != nil {
+		return Result{}, err
+	}
+	desired := replicas(controller.Spec.Replicas)
+	if controller.Status.ReadyReplicas < desired {
+		return notReady("%d of %d replicas ready", controller.Status.ReadyReplicas, desired)
+	}
+	return ready()
+}
+
+func checkJob(obj *unstructured.Unstructured) (Result, error) {
+	job := &batchv1.Job{}
+	if err := convert(obj, job); err != nil {
+		return Result{}, err
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return ready()
+		case batchv1.JobFailed:
+			return notReady("job failed: %s", condition.Message)
+		}
+	}
+	return notReady("job has not completed: %d active, %d succeeded, %d failed pods", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+}
+
+func checkPod(obj *unstructured.Unstructured) (Result, error) {
+	pod := &corev1.Pod{}
+	if err := convert(obj, pod); err != nil {
+		return Result{}, err
+	}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return ready()
+	case corev1.PodRunning:
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return ready()
+			}
+		}
+		return notReady("pod is running but not ready")
+	default:
+		return notReady("pod is in phase %s", pod.Status.Phase)
+	}
+}
+
+func checkPersistentVolumeClaim(obj *unstructured.Unstructured) (Result, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := convert(obj, pvc); err != nil {
+		return Result{}, err
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return notReady("claim is in phase %s", pvc.Status.Phase)
+	}
+	return ready()
+}
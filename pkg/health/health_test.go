@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func newObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	assert.NoError(t, yaml.Unmarshal([]byte(manifest), &obj.Object))
+	return obj
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected Result
+	}{
+		{
+			name: "kind without readiness check",
+			manifest: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test`,
+			expected: Result{Status: Ready},
+		},
+		{
+			name: "ready deployment",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  updatedReplicas: 2
+  availableReplicas: 2`,
+			expected: Result{Status: Ready},
+		},
+		{
+			name: "deployment with outdated generation",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+  generation: 2
+status:
+  observedGeneration: 1`,
+			expected: Result{Status: NotReady, Message: "observed generation 1 is outdated"},
+		},
+		{
+			name: "deployment with unavailable replicas",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: 3
+status:
+  updatedReplicas: 3
+  availableReplicas: 1`,
+			expected: Result{Status: NotReady, Message: "1 of 3 replicas available"},
+		},
+		{
+			name: "statefulset with default replicas",
+			manifest: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: test
+status:
+  readyReplicas: 0`,
+			expected: Result{Status: NotReady, Message: "0 of 1 replicas ready"},
+		},
+		{
+			name: "failed job",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+status:
+  conditions:
+  - type: Failed
+    status: "True"
+    message: BackoffLimitExceeded`,
+			expected: Result{Status: NotReady, Message: "job failed: BackoffLimitExceeded"},
+		},
+		{
+			name: "completed job",
+			manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+status:
+  conditions:
+  - type: Complete
+    status: "True"`,
+			expected: Result{Status: Ready},
+		},
+		{
+			name: "running pod that is not ready",
+			manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+status:
+  phase: Running
+  conditions:
+  - type: Ready
+    status: "False"`,
+			expected: Result{Status: NotReady, Message: "pod is running but not ready"},
+		},
+		{
+			name: "pending persistent volume claim",
+			manifest: `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: test
+status:
+  phase: Pending`,
+			expected: Result{Status: NotReady, Message: "claim is in phase Pending"},
+		},
+		{
+			name: "invalid object",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: many`,
+			expected: Result{Status: Unknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(newObject(t, tt.manifest))
+			assert.Equal(t, tt.expected.Status, result.Status)
+			if tt.expected.Message != "" {
+				assert.Equal(t, tt.expected.Message, result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policies
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	gotmpl "text/template"
+
+	"github.com/gardener/landscaper/pkg/landscaper/installations/executions/template/gotemplate"
+	"github.com/xeipuuv/gojsonschema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Object is a rendered object the rules are evaluated for.
+type Object struct {
+	// Installation is the path of the installation that rendered the object.
+	Installation string
+	// DeployItem is the name of the deploy item that is or contains the object.
+	DeployItem string
+	// DeployItemType is the type of the deploy item that is or contains the object.
+	DeployItemType string
+	// Object is the rendered object, i.e. a deploy item or one of its manifests.
+	Object *unstructured.Unstructured
+	// DeployItemObject is the deploy item that is or contains the object.
+	DeployItemObject *unstructured.Unstructured
+}
+
+// ObjectReference identifies an object of the check report.
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// String returns the reference as "kind/namespace/name".
+func (r ObjectReference) String() string {
+	if len(r.Namespace) == 0 {
+		return fmt.Sprintf("%s/%s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// Violation describes an object that does not satisfy a rule.
+type Violation struct {
+	// Rule is the id of the violated rule.
+	Rule string `json:"rule"`
+	// Severity is the severity of the violated rule.
+	Severity Severity `json:"severity"`
+	// Installation is the path of the installation that rendered the object.
+	Installation string `json:"installation"`
+	// DeployItem is the name of the deploy item that is or contains the object.
+	DeployItem string `json:"deployItem"`
+	// Object is the violating object.
+	Object ObjectReference `json:"object"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// Report is the result of the evaluation of rules.
+type Report struct {
+	// Rules is the number of evaluated rules.
+	Rules int `json:"rules"`
+	// Objects is the number of checked objects.
+	Objects int `json:"objects"`
+	// Violations contains all violations in the order of the objects and rules.
+	Violations []Violation `json:"violations"`
+}
+
+// HasErrors returns whether the report contains violations with severity error.
+func (r *Report) HasErrors() bool {
+	for _, v := range r.Violations {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluator evaluates a set of rules against rendered objects.
+type Evaluator struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	apiVersions     sets.String
+	kinds           sets.String
+	deployItemTypes sets.String
+	condition       *gotmpl.Template
+	message         *gotmpl.Template
+	schema          *gojsonschema.Schema
+}
+
+// NewEvaluator compiles the conditions, messages and schemas of the given rules.
+func NewEvaluator(rules []Rule) (*Evaluator, error) {
+	e := &Evaluator{
+		rules: make([]compiledRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		compiled := compiledRule{
+			Rule:            rule,
+			apiVersions:     sets.NewString(rule.Match.APIVersions...),
+			kinds:           sets.NewString(rule.Match.Kinds...),
+			deployItemTypes: sets.NewString(rule.Match.DeployItemTypes...),
+		}
+
+		var err error
+		if len(rule.Condition) != 0 {
+			compiled.condition, err = newTemplate(rule.ID, rule.Condition)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse condition of rule %q: %w", rule.ID, err)
+			}
+		}
+		if len(rule.Message) != 0 {
+			compiled.message, err = newTemplate(rule.ID, rule.Message)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse message of rule %q: %w", rule.ID, err)
+			}
+		}
+		if len(rule.Schema) != 0 {
+			compiled.schema, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(rule.Schema))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse schema of rule %q: %w", rule.ID, err)
+			}
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, nil
+}
+
+// Evaluate evaluates all rules against the given objects.
+// An error is only returned if a rule cannot be evaluated, violations are part of the report.
+func (e *Evaluator) Evaluate(objects []Object) (*Report, error) {
+	report := &Report{
+		Rules:      len(e.rules),
+		Objects:    len(objects),
+		Violations: []Violation{},
+	}
+	for _, obj := range objects {
+		for _, rule := range e.rules {
+			if !rule.matches(obj) {
+				continue
+			}
+			messages, err := rule.evaluate(obj)
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate rule %q for %s of deploy item %s in installation %s: %w",
+					rule.ID, reference(obj.Object), obj.DeployItem, obj.Installation, err)
+			}
+			for _, msg := range messages {
+				report.Violations = append(report.Violations, Violation{
+					Rule:         rule.ID,
+					Severity:     rule.Severity,
+					Installation: obj.Installation,
+					DeployItem:   obj.DeployItem,
+					Object:       reference(obj.Object),
+					Message:      msg,
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+func (r *compiledRule) matches(obj Object) bool {
+	if r.apiVersions.Len() != 0 && !r.apiVersions.Has(obj.Object.GetAPIVersion()) {
+		return false
+	}
+	if r.kinds.Len() != 0 && !r.kinds.Has(obj.Object.GetKind()) {
+		return false
+	}
+	if r.deployItemTypes.Len() != 0 && !r.deployItemTypes.Has(obj.DeployItemType) {
+		return false
+	}
+	return true
+}
+
+// evaluate returns the violation messages of the rule for the given object.
+func (r *compiledRule) evaluate(obj Object) ([]string, error) {
+	data := templateData(obj)
+	messages := []string{}
+
+	if r.condition != nil {
+		result, err := execute(r.condition, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute condition: %w", err)
+		}
+		switch result {
+		case "true":
+		case "false":
+			msg, err := r.violationMessage(data)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, msg)
+		default:
+			return nil, fmt.Errorf("condition must render to true or false but rendered to %q", result)
+		}
+	}
+
+	if r.schema != nil {
+		result, err := r.schema.Validate(gojsonschema.NewGoLoader(obj.Object.Object))
+		if err != nil {
+			return nil, fmt.Errorf("unable to validate schema: %w", err)
+		}
+		for _, schemaErr := range result.Errors() {
+			messages = append(messages, schemaErr.String())
+		}
+	}
+
+	return messages, nil
+}
+
+func (r *compiledRule) violationMessage(data map[string]interface{}) (string, error) {
+	if r.message == nil {
+		if len(r.Description) != 0 {
+			return r.Description, nil
+		}
+		return fmt.Sprintf("rule %s is violated", r.ID), nil
+	}
+	msg, err := execute(r.message, data)
+	if err != nil {
+		return "", fmt.Errorf("unable to execute message: %w", err)
+	}
+	return msg, nil
+}
+
+func newTemplate(name, text string) (*gotmpl.Template, error) {
+	return gotmpl.New(name).
+		Funcs(gotemplate.LandscaperSprigFuncMap()).
+		Option("missingkey=zero").
+		Parse(text)
+}
+
+func execute(tmpl *gotmpl.Template, data map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func templateData(obj Object) map[string]interface{} {
+	data := map[string]interface{}{
+		"installation": obj.Installation,
+		"object":       obj.Object.Object,
+	}
+	if obj.DeployItemObject != nil {
+		data["deployItem"] = obj.DeployItemObject.Object
+	}
+	return data
+}
+
+func reference(obj *unstructured.Unstructured) ObjectReference {
+	return ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
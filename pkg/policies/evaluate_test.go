@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policies_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/gardener/landscapercli/pkg/policies"
+)
+
+func TestEvaluate(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "a", "image": "registry.example.com/a:1.0.0"},
+						map[string]interface{}{"name": "b", "image": "docker.io/b:1.0.0"},
+					},
+				},
+			},
+		},
+	}}
+	objects := []policies.Object{
+		{
+			Installation:   "root",
+			DeployItem:     "app",
+			DeployItemType: "landscaper.gardener.cloud/helm",
+			Object:         deployment,
+		},
+	}
+
+	t.Run("reject-non-boolean-conditions", func(t *testing.T) {
+		evaluator, err := policies.NewEvaluator([]policies.Rule{
+			{
+				ID:        "kind",
+				Condition: `{{ .object.kind }}`,
+			},
+		})
+		assert.NoError(t, err)
+		_, err = evaluator.Evaluate(objects)
+		assert.Error(t, err)
+	})
+
+	t.Run("report-violations", func(t *testing.T) {
+		evaluator, err := policies.NewEvaluator([]policies.Rule{
+			{
+				ID:          "trusted-registry",
+				Description: "images must come from registry.example.com",
+				Match:       policies.Match{Kinds: []string{"Deployment"}},
+				Condition:   `{{ $ok := true }}{{ range .object.spec.template.spec.containers }}{{ if not (hasPrefix "registry.example.com/" .image) }}{{ $ok = false }}{{ end }}{{ end }}{{ $ok }}`,
+			},
+			{
+				ID:        "not-in-default-namespace",
+				Severity:  policies.SeverityWarning,
+				Condition: `{{ ne .object.metadata.namespace "default" }}`,
+				Message:   `{{ .installation }}/{{ .object.metadata.name }} is deployed to the default namespace`,
+			},
+			{
+				ID:        "other-deployer",
+				Match:     policies.Match{DeployItemTypes: []string{"landscaper.gardener.cloud/kubernetes-manifest"}},
+				Condition: `false`,
+			},
+			{
+				ID:     "has-replicas",
+				Schema: []byte(`{"type": "object", "properties": {"spec": {"required": ["replicas"]}}}`),
+			},
+		})
+		assert.NoError(t, err)
+		report, err := evaluator.Evaluate(objects)
+		assert.NoError(t, err)
+		assert.True(t, report.HasErrors())
+		assert.Equal(t, 4, report.Rules)
+		assert.Equal(t, 1, report.Objects)
+
+		messages := []string{}
+		for _, v := range report.Violations {
+			messages = append(messages, v.Rule+": "+v.Message)
+		}
+		assert.Equal(t, []string{
+			"trusted-registry: images must come from registry.example.com",
+			"not-in-default-namespace: root/app is deployed to the default namespace",
+			"has-replicas: spec: replicas is required",
+		}, messages)
+	})
+
+	t.Run("reject-invalid-templates", func(t *testing.T) {
+		_, err := policies.NewEvaluator([]policies.Rule{
+			{
+				ID:        "invalid",
+				Condition: `{{ .object`,
+			},
+		})
+		assert.Error(t, err)
+	})
+}
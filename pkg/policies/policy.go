@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policies
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// Severity defines how a violation of a rule is treated.
+type Severity string
+
+const (
+	// SeverityError marks violations that fail the check.
+	SeverityError Severity = "error"
+	// SeverityWarning marks violations that are reported but do not fail the check.
+	SeverityWarning Severity = "warning"
+)
+
+// Policy is a set of rules that is read from a policy file.
+type Policy struct {
+	// Rules are the rules of the policy.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule defines a condition that has to be satisfied by all matching objects.
+// A rule defines a condition, a schema or both.
+type Rule struct {
+	// ID is the unique identifier of the rule.
+	ID string `json:"id"`
+	// Description describes the rule.
+	Description string `json:"description,omitempty"`
+	// Severity defines how a violation is treated. Defaults to error.
+	Severity Severity `json:"severity,omitempty"`
+	// Match selects the objects the rule is evaluated for.
+	Match Match `json:"match,omitempty"`
+	// Condition is a go template that has to render to "true" if the object satisfies the rule and to "false" otherwise.
+	// The object is available as ".object", its deploy item as ".deployItem" and the installation path as ".installation".
+	Condition string `json:"condition,omitempty"`
+	// Schema is a json schema the object has to satisfy.
+	Schema json.RawMessage `json:"schema,omitempty"`
+	// Message is the message that is reported if the condition is not satisfied.
+	// It is a go template with the same input as the condition.
+	Message string `json:"message,omitempty"`
+
+	// source is the file the rule is defined in.
+	source string
+}
+
+// Match selects the objects a rule is evaluated for.
+// All given fields have to match. An empty match selects all objects.
+type Match struct {
+	// APIVersions is the list of api versions of the matching objects.
+	APIVersions []string `json:"apiVersions,omitempty"`
+	// Kinds is the list of kinds of the matching objects.
+	// Deploy items themselves are matched with kind "DeployItem".
+	Kinds []string `json:"kinds,omitempty"`
+	// DeployItemTypes is the list of deploy item types of the matching objects.
+	// Manifests are matched by the type of the deploy item that contains them.
+	DeployItemTypes []string `json:"deployItemTypes,omitempty"`
+}
+
+// Source returns the file the rule has been read from.
+func (r Rule) Source() string {
+	return r.source
+}
+
+// LoadFromDir reads all policy files (*.yaml, *.yml, *.json) of the given directory.
+// The rules are returned in the order of the file names and the order in the files.
+func LoadFromDir(fs vfs.FileSystem, dir string) ([]Rule, error) {
+	files, err := vfs.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read policy directory %s: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+	rules := []Rule{}
+	ids := map[string]string{}
+	for _, file := range files {
+		if file.IsDir() || !isPolicyFile(file.Name()) {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		data, err := vfs.ReadFile(fs, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read policy file %s: %w", filePath, err)
+		}
+		policy := &Policy{}
+		if err := yaml.Unmarshal(data, policy); err != nil {
+			return nil, fmt.Errorf("unable to parse policy file %s: %w", filePath, err)
+		}
+		for _, rule := range policy.Rules {
+			rule.source = filePath
+			if err := rule.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid rule in policy file %s: %w", filePath, err)
+			}
+			if other, ok := ids[rule.ID]; ok {
+				return nil, fmt.Errorf("rule %q in policy file %s is already defined in %s", rule.ID, filePath, other)
+			}
+			ids[rule.ID] = filePath
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// Validate validates the rule definition.
+func (r *Rule) Validate() error {
+	if len(r.ID) == 0 {
+		return fmt.Errorf("rule id must not be empty")
+	}
+	if len(r.Condition) == 0 && len(r.Schema) == 0 {
+		return fmt.Errorf("rule %q must define a condition or a schema", r.ID)
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityError
+	case SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("rule %q has unknown severity %q", r.ID, r.Severity)
+	}
+	return nil
+}
+
+func isPolicyFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml" || ext == ".json"
+}
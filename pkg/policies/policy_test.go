@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policies_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/policies"
+)
+
+func TestLoadFromDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectedIDs []string
+		expectError bool
+	}{
+		{
+			name: "load-rules-in-file-order",
+			files: map[string]string{
+				"b.yaml":    "rules:\n- id: rule-b\n  condition: 'true'\n",
+				"a.yml":     "rules:\n- id: rule-a1\n  condition: 'true'\n- id: rule-a2\n  schema: {type: object}\n",
+				"README.md": "no policy",
+			},
+			expectedIDs: []string{"rule-a1", "rule-a2", "rule-b"},
+		},
+		{
+			name: "reject-duplicate-rule-ids",
+			files: map[string]string{
+				"a.yaml": "rules:\n- id: rule\n  condition: 'true'\n",
+				"b.yaml": "rules:\n- id: rule\n  condition: 'true'\n",
+			},
+			expectError: true,
+		},
+		{
+			name: "reject-rule-without-condition-and-schema",
+			files: map[string]string{
+				"a.yaml": "rules:\n- id: rule\n",
+			},
+			expectError: true,
+		},
+		{
+			name: "reject-unknown-severity",
+			files: map[string]string{
+				"a.yaml": "rules:\n- id: rule\n  severity: fatal\n  condition: 'true'\n",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := memoryfs.New()
+			assert.NoError(t, fs.MkdirAll("/policies", os.ModePerm))
+			for name, content := range test.files {
+				assert.NoError(t, vfs.WriteFile(fs, "/policies/"+name, []byte(content), os.ModePerm))
+			}
+
+			rules, err := policies.LoadFromDir(fs, "/policies")
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			ids := []string{}
+			for _, rule := range rules {
+				ids = append(ids, rule.ID)
+				assert.Equal(t, policies.SeverityError, rule.Severity, "expect severity error as default")
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,416 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscaper/pkg/landscaper/jsonschema"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+// DraftSchema is the JSON schema dialect of the bundled schema.
+const DraftSchema = "http://json-schema.org/draft-07/schema#"
+
+const (
+	refKey         = "$ref"
+	definitionsKey = "definitions"
+)
+
+// Bundler bundles the schemas of the imports and exports of a blueprint into a single self-contained JSON schema.
+type Bundler struct {
+	// Fs is the filesystem of the blueprint directory.
+	Fs vfs.FileSystem
+	// ComponentDescriptor is the optional component descriptor of the blueprint.
+	// It is required if a schema references a resource of the component descriptor.
+	ComponentDescriptor *cdv2.ComponentDescriptor
+	// ComponentResolver resolves referenced component descriptors.
+	ComponentResolver ctf.ComponentResolver
+	// Exports defines whether the schemas of the exports are added to the bundle.
+	Exports bool
+}
+
+// bundle contains the state of a single bundling run.
+type bundle struct {
+	*Bundler
+	blueprint   *lsv1alpha1.Blueprint
+	definitions map[string]interface{}
+}
+
+// Bundle creates the bundled schema of the blueprint.
+// The schema describes an import values file with the imports in the "imports" property
+// and, if enabled, the exports in the "exports" property.
+// All references to local types, blueprint files and component descriptor resources
+// are moved into the definitions of the bundle and the references are rewritten accordingly.
+func (b *Bundler) Bundle(title string) (map[string]interface{}, error) {
+	blueprint, err := readBlueprint(b.Fs)
+	if err != nil {
+		return nil, err
+	}
+
+	bn := b.newBundle(blueprint)
+
+	imports := newObjectSchema()
+	if err := bn.addImports(imports, blueprint.Imports, ""); err != nil {
+		return nil, err
+	}
+	properties := map[string]interface{}{
+		"imports": imports,
+	}
+	if b.Exports {
+		exports := newObjectSchema()
+		for _, exp := range blueprint.Exports {
+			base := pointer("properties", "exports", "properties", exp.Name)
+			schema, err := bn.parameterSchema(exp.FieldValueDefinition, string(exp.Type), base)
+			if err != nil {
+				return nil, fmt.Errorf("unable to bundle schema of export %s: %w", exp.Name, err)
+			}
+			exports["properties"].(map[string]interface{})[exp.Name] = schema
+		}
+		delete(exports, "required")
+		properties["exports"] = exports
+	}
+
+	result := map[string]interface{}{
+		"$schema":    DraftSchema,
+		"title":      title,
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(imports["required"].([]interface{})) != 0 {
+		result["required"] = []interface{}{"imports"}
+	} else {
+		delete(imports, "required")
+	}
+	if len(bn.definitions) != 0 {
+		result[definitionsKey] = bn.definitions
+	}
+	return result, nil
+}
+
+func (b *Bundler) newBundle(blueprint *lsv1alpha1.Blueprint) *bundle {
+	return &bundle{
+		Bundler:     b,
+		blueprint:   blueprint,
+		definitions: map[string]interface{}{},
+	}
+}
+
+// readBlueprint reads the blueprint of the blueprint directory.
+func readBlueprint(fs vfs.FileSystem) (*lsv1alpha1.Blueprint, error) {
+	data, err := vfs.ReadFile(fs, lsv1alpha1.BlueprintFileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	blueprint := &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	return blueprint, nil
+}
+
+// addImports adds the imports and recursively their conditional imports to the imports object schema.
+// Conditional imports are never required as they are only required if the import they depend on is set.
+func (b *bundle) addImports(imports map[string]interface{}, list lsv1alpha1.ImportDefinitionList, condition string) error {
+	for _, imp := range list {
+		base := pointer("properties", "imports", "properties", imp.Name)
+		schema, err := b.parameterSchema(imp.FieldValueDefinition, string(imp.Type), base)
+		if err != nil {
+			return fmt.Errorf("unable to bundle schema of import %s: %w", imp.Name, err)
+		}
+		if len(condition) != 0 {
+			schema = withAnnotation(schema, "description", strings.TrimSpace(fmt.Sprintf("%s Only used if import %s is set.", description(schema), condition)))
+		}
+		if imp.Default.Value.RawMessage != nil {
+			var value interface{}
+			if err := decodeJSON(imp.Default.Value.RawMessage, &value); err != nil {
+				return fmt.Errorf("unable to decode default value of import %s: %w", imp.Name, err)
+			}
+			schema = withAnnotation(schema, "default", value)
+		}
+		imports["properties"].(map[string]interface{})[imp.Name] = schema
+
+		required := imp.Required == nil || *imp.Required
+		if required && len(condition) == 0 && imp.Default.Value.RawMessage == nil {
+			imports["required"] = append(imports["required"].([]interface{}), imp.Name)
+		}
+		if err := b.addImports(imports, imp.ConditionalImports, imp.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parameterSchema returns the schema of the value of an import or export.
+// base is the json pointer of the schema in the bundle, which is used to rewrite references within the schema.
+func (b *bundle) parameterSchema(def lsv1alpha1.FieldValueDefinition, typ string, base string) (interface{}, error) {
+	switch {
+	case typ == string(lsv1alpha1.ImportTypeTargetList):
+		return map[string]interface{}{
+			"type":  "array",
+			"items": targetSchema(def.TargetType),
+		}, nil
+	case len(def.TargetType) != 0 || typ == string(lsv1alpha1.ImportTypeTarget):
+		return targetSchema(def.TargetType), nil
+	case typ == string(lsv1alpha1.ImportTypeComponentDescriptor):
+		return map[string]interface{}{
+			"type":        "object",
+			"description": "Component descriptor",
+		}, nil
+	case typ == string(lsv1alpha1.ImportTypeComponentDescriptorList):
+		return map[string]interface{}{
+			"type":        "array",
+			"description": "List of component descriptors",
+			"items":       map[string]interface{}{"type": "object"},
+		}, nil
+	}
+
+	if def.Schema == nil || def.Schema.RawMessage == nil {
+		return map[string]interface{}{}, nil
+	}
+	var data interface{}
+	if err := decodeJSON(def.Schema.RawMessage, &data); err != nil {
+		return nil, fmt.Errorf("unable to decode schema: %w", err)
+	}
+	return b.rewrite(data, base)
+}
+
+// rewrite returns a copy of the schema with all references replaced by references into the bundle.
+// base is the json pointer of the schema document in the bundle that is used for references within the document.
+func (b *bundle) rewrite(data interface{}, base string) (interface{}, error) {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			switch key {
+			case refKey:
+				ref, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid reference value: expected string, got %v", value)
+				}
+				newRef, err := b.reference(ref, base)
+				if err != nil {
+					return nil, err
+				}
+				res[key] = newRef
+				continue
+			case "$schema", "$id":
+				// the identifiers of the embedded documents would change the resolution of the references
+				continue
+			}
+			sub, err := b.rewrite(value, base)
+			if err != nil {
+				return nil, err
+			}
+			res[key] = sub
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(typed))
+		for i, value := range typed {
+			sub, err := b.rewrite(value, base)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = sub
+		}
+		return res, nil
+	default:
+		return data, nil
+	}
+}
+
+// reference adds the referenced schema to the definitions and returns the reference into the bundle.
+// References with unknown schemes are returned unchanged.
+func (b *bundle) reference(ref, base string) (string, error) {
+	uri, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid reference %q: %w", ref, err)
+	}
+
+	var key string
+	switch uri.Scheme {
+	case "":
+		if strings.HasPrefix(ref, "#") {
+			return "#" + base + uri.Fragment, nil
+		}
+		return ref, nil
+	case "local":
+		if len(uri.Path) != 0 {
+			return "", fmt.Errorf("invalid reference %q: a path is not supported for local types", ref)
+		}
+		key = "local:" + uri.Host
+		err = b.define(key, func() ([]byte, error) {
+			schema, ok := b.blueprint.LocalTypes[uri.Host]
+			if !ok {
+				return nil, fmt.Errorf("type %s is not defined in local types", uri.Host)
+			}
+			return schema.RawMessage, nil
+		})
+	case "blueprint":
+		filePath := filepath.Join(uri.Host, uri.Path)
+		key = "blueprint:" + filePath
+		err = b.define(key, func() ([]byte, error) {
+			data, err := vfs.ReadFile(b.Fs, filePath)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read schema from %s: %w", filePath, err)
+			}
+			return data, nil
+		})
+	case "cd":
+		docURI := *uri
+		docURI.Fragment = ""
+		key = docURI.String()
+		err = b.define(key, func() ([]byte, error) {
+			return b.resolveComponentDescriptorReference(docURI.String())
+		})
+	default:
+		return ref, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve reference %q: %w", ref, err)
+	}
+	return "#" + pointer(definitionsKey, key) + uri.Fragment, nil
+}
+
+// define adds the schema that is returned by the load function to the definitions if it is not yet defined.
+// The definition is registered before its references are rewritten so that cyclic references are supported.
+func (b *bundle) define(key string, load func() ([]byte, error)) error {
+	if _, ok := b.definitions[key]; ok {
+		return nil
+	}
+	b.definitions[key] = map[string]interface{}{}
+
+	raw, err := load()
+	if err != nil {
+		delete(b.definitions, key)
+		return err
+	}
+	var data interface{}
+	if err := decodeJSON(raw, &data); err != nil {
+		delete(b.definitions, key)
+		return fmt.Errorf("unable to decode schema: %w", err)
+	}
+	schema, err := b.rewrite(data, pointer(definitionsKey, key))
+	if err != nil {
+		delete(b.definitions, key)
+		return err
+	}
+	b.definitions[key] = schema
+	return nil
+}
+
+// resolveComponentDescriptorReference fetches the schema of a component descriptor resource.
+// The landscaper reference resolver is used so that the references of the fetched schema
+// are resolved in the context of the component the resource belongs to.
+func (b *bundle) resolveComponentDescriptorReference(ref string) ([]byte, error) {
+	resolver := jsonschema.NewReferenceResolver(&jsonschema.ReferenceContext{
+		ComponentDescriptor: b.ComponentDescriptor,
+		ComponentResolver:   b.ComponentResolver,
+	})
+	raw, err := json.Marshal(map[string]interface{}{refKey: ref})
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolver.Resolve(raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+// targetSchema returns the schema of a target with the given type.
+func targetSchema(targetType string) map[string]interface{} {
+	typeSchema := map[string]interface{}{"type": "string"}
+	description := "Target"
+	if len(targetType) != 0 {
+		typeSchema = map[string]interface{}{"const": targetType}
+		description = fmt.Sprintf("Target of type %s", targetType)
+	}
+	return map[string]interface{}{
+		"type":        "object",
+		"description": description,
+		"required":    []interface{}{"spec"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"const": "Target"},
+			"metadata":   map[string]interface{}{"type": "object"},
+			"spec": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"type"},
+				"properties": map[string]interface{}{
+					"type":   typeSchema,
+					"config": map[string]interface{}{},
+				},
+			},
+		},
+	}
+}
+
+func newObjectSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+		"required":   []interface{}{},
+	}
+}
+
+// withAnnotation sets an annotation like the description or the default value of a schema.
+// A reference of the schema is moved into "allOf" as keywords next to a reference are ignored in draft-07.
+func withAnnotation(schema interface{}, key string, value interface{}) interface{} {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return schema
+	}
+	if ref, isRef := obj[refKey]; isRef {
+		delete(obj, refKey)
+		allOf, _ := obj["allOf"].([]interface{})
+		obj["allOf"] = append(allOf, map[string]interface{}{refKey: ref})
+	}
+	obj[key] = value
+	return obj
+}
+
+// description returns the description of a schema including a trailing dot.
+func description(schema interface{}) string {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	desc, _ := obj["description"].(string)
+	desc = strings.TrimSpace(desc)
+	if len(desc) == 0 || strings.HasSuffix(desc, ".") {
+		return desc
+	}
+	return desc + "."
+}
+
+// pointer returns the json pointer of the given path.
+func pointer(path ...string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var buf strings.Builder
+	for _, elem := range path {
+		buf.WriteString("/")
+		buf.WriteString(escaper.Replace(elem))
+	}
+	return buf.String()
+}
+
+// decodeJSON decodes json data and keeps numbers as they are.
+func decodeJSON(data []byte, into interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(into)
+}
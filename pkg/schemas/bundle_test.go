@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas_test
+
+import (
+	"testing"
+
+	"github.com/mandelsoft/vfs/pkg/memoryfs"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/gardener/landscapercli/pkg/schemas"
+)
+
+func TestBundle(t *testing.T) {
+	fs, err := projectionfs.New(osfs.New(), "./testdata/blueprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	bundler := &schemas.Bundler{Fs: fs, Exports: true}
+	bundle, err := bundler.Bundle("test")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	definitions, ok := bundle["definitions"].(map[string]interface{})
+	if assert.True(t, ok) {
+		assert.Len(t, definitions, 3)
+		assert.Contains(t, definitions, "local:namespace")
+		assert.Contains(t, definitions, "blueprint:schemas/config.json")
+		assert.Contains(t, definitions, "blueprint:schemas/tree.json")
+	}
+
+	imports := bundle["properties"].(map[string]interface{})["imports"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"cluster", "namespace"}, imports["required"])
+	replicas := imports["properties"].(map[string]interface{})["replicas"].(map[string]interface{})
+	assert.Equal(t, "Only used if import config is set.", replicas["description"])
+	image := imports["properties"].(map[string]interface{})["image"].(map[string]interface{})
+	assert.Equal(t, "nginx:1.21", image["default"])
+	assert.Contains(t, bundle["properties"], "exports")
+
+	schemaLoader := gojsonschema.NewGoLoader(bundle)
+	valid := map[string]interface{}{
+		"imports": map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"apiVersion": "landscaper.gardener.cloud/v1alpha1",
+				"kind":       "Target",
+				"spec": map[string]interface{}{
+					"type": "landscaper.gardener.cloud/kubernetes-cluster",
+				},
+			},
+			"namespace": "default",
+			"config": map[string]interface{}{
+				"logLevel":  "info",
+				"namespace": "monitoring",
+			},
+			"tree": map[string]interface{}{
+				"name": "root",
+				"children": []interface{}{
+					map[string]interface{}{"name": "leaf"},
+				},
+			},
+		},
+	}
+	result, err := gojsonschema.Validate(schemaLoader, gojsonschema.NewGoLoader(valid))
+	if assert.NoError(t, err) {
+		assert.True(t, result.Valid(), "%v", result.Errors())
+	}
+
+	invalid := map[string]interface{}{
+		"imports": map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"type": "landscaper.gardener.cloud/helm",
+				},
+			},
+			"namespace": "Default",
+			"config": map[string]interface{}{
+				"logLevel": "trace",
+			},
+			"tree": map[string]interface{}{
+				"children": []interface{}{
+					map[string]interface{}{"name": 1},
+				},
+			},
+		},
+	}
+	result, err = gojsonschema.Validate(schemaLoader, gojsonschema.NewGoLoader(invalid))
+	if assert.NoError(t, err) {
+		assert.Len(t, result.Errors(), 4, "%v", result.Errors())
+	}
+}
+
+func TestBundleUnresolvableReference(t *testing.T) {
+	fs := memoryfs.New()
+	blueprint := []byte(`apiVersion: landscaper.gardener.cloud/v1alpha1
+kind: Blueprint
+imports:
+- name: image
+  schema:
+    $ref: "cd://resources/image-schema"
+`)
+	if !assert.NoError(t, vfs.WriteFile(fs, "blueprint.yaml", blueprint, 0644)) {
+		return
+	}
+
+	_, err := (&schemas.Bundler{Fs: fs}).Bundle("test")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unable to bundle schema of import image")
+		assert.Contains(t, err.Error(), "no component descriptor defined to resolve the ref")
+	}
+}
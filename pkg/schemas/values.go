@@ -0,0 +1,479 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// ValuesGenerator generates an example import values file for a blueprint.
+type ValuesGenerator struct {
+	// Fs is the filesystem of the blueprint directory.
+	Fs vfs.FileSystem
+	// ComponentDescriptor is the optional component descriptor of the blueprint.
+	// It is required if a schema references a resource of the component descriptor.
+	ComponentDescriptor *cdv2.ComponentDescriptor
+	// ComponentResolver resolves referenced component descriptors.
+	ComponentResolver ctf.ComponentResolver
+}
+
+// Generate returns a yaml document with example values for all imports of the blueprint
+// in the format of the values files of the render command.
+// The values are taken from the default values of the imports and the defaults and examples of their schemas.
+// Each value is commented with the description, type and whether it is required.
+func (g *ValuesGenerator) Generate() (*yamlv3.Node, error) {
+	blueprint, err := readBlueprint(g.Fs)
+	if err != nil {
+		return nil, err
+	}
+
+	// the schemas are bundled so that references are not expanded in advance and cyclic references are supported
+	bundler := &Bundler{
+		Fs:                  g.Fs,
+		ComponentDescriptor: g.ComponentDescriptor,
+		ComponentResolver:   g.ComponentResolver,
+	}
+	eg := &exampleGenerator{
+		bundle:  bundler.newBundle(blueprint),
+		schemas: map[string]interface{}{},
+	}
+	eg.root = map[string]interface{}{
+		definitionsKey: eg.bundle.definitions,
+		"properties": map[string]interface{}{
+			"imports": map[string]interface{}{
+				"properties": eg.schemas,
+			},
+		},
+	}
+
+	imports := &yamlv3.Node{Kind: yamlv3.MappingNode}
+	if err := eg.addImports(imports, blueprint.Imports, ""); err != nil {
+		return nil, err
+	}
+	if len(imports.Content) == 0 {
+		imports.Style = yamlv3.FlowStyle
+	}
+	return &yamlv3.Node{
+		Kind: yamlv3.DocumentNode,
+		Content: []*yamlv3.Node{
+			{
+				Kind: yamlv3.MappingNode,
+				Content: []*yamlv3.Node{
+					{Kind: yamlv3.ScalarNode, Value: "imports", HeadComment: "Example import values of the blueprint.\nReplace the values before rendering the blueprint."},
+					imports,
+				},
+			},
+		},
+	}, nil
+}
+
+// GenerateImports returns the example values of the imports, e.g. to render the executions of the blueprint.
+func (g *ValuesGenerator) GenerateImports() (map[string]interface{}, error) {
+	doc, err := g.Generate()
+	if err != nil {
+		return nil, err
+	}
+	data, err := yamlv3.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal example values: %w", err)
+	}
+	values := struct {
+		Imports map[string]interface{} `json:"imports"`
+	}{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("unable to decode example values: %w", err)
+	}
+	return values.Imports, nil
+}
+
+// exampleGenerator generates example values from the bundled schemas of the imports.
+type exampleGenerator struct {
+	bundle *bundle
+	// schemas are the bundled schemas of the imports by their name.
+	schemas map[string]interface{}
+	// root is the document the references of the bundled schemas point into.
+	root map[string]interface{}
+}
+
+// addImports adds the example values of the imports and recursively of their conditional imports.
+func (g *exampleGenerator) addImports(imports *yamlv3.Node, list lsv1alpha1.ImportDefinitionList, condition string) error {
+	for _, imp := range list {
+		var comments []string
+		base := pointer("properties", "imports", "properties", imp.Name)
+		schema, err := g.bundle.parameterSchema(imp.FieldValueDefinition, string(imp.Type), base)
+		if err != nil {
+			// fall back to the unresolved schema
+			comments = append(comments, fmt.Sprintf("The references of the schema could not be resolved: %s", err.Error()))
+			schema = nil
+			if imp.Schema != nil && imp.Schema.RawMessage != nil {
+				if err := decodeJSON(imp.Schema.RawMessage, &schema); err != nil {
+					return fmt.Errorf("unable to decode schema of import %s: %w", imp.Name, err)
+				}
+			}
+		}
+		g.schemas[imp.Name] = schema
+		obj, _ := schema.(map[string]interface{})
+
+		var value *yamlv3.Node
+		switch {
+		case imp.Type == lsv1alpha1.ImportTypeTargetList:
+			value = &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: []*yamlv3.Node{exampleTarget(imp.Name, imp.TargetType)}}
+			comments = append([]string{fmt.Sprintf("Type: list of targets of type %s.", imp.TargetType)}, comments...)
+		case len(imp.TargetType) != 0 || imp.Type == lsv1alpha1.ImportTypeTarget:
+			value = exampleTarget(imp.Name, imp.TargetType)
+			comments = append([]string{fmt.Sprintf("Type: target of type %s.", imp.TargetType)}, comments...)
+		case imp.Type == lsv1alpha1.ImportTypeComponentDescriptor:
+			value = &yamlv3.Node{Kind: yamlv3.MappingNode, Style: yamlv3.FlowStyle}
+			comments = append([]string{"Type: component descriptor."}, comments...)
+		case imp.Type == lsv1alpha1.ImportTypeComponentDescriptorList:
+			value = &yamlv3.Node{Kind: yamlv3.SequenceNode, Style: yamlv3.FlowStyle}
+			comments = append([]string{"Type: list of component descriptors."}, comments...)
+		default:
+			comments = append(g.schemaComments(obj), comments...)
+			if imp.Default.Value.RawMessage != nil {
+				var defaultValue interface{}
+				if err := json.Unmarshal(imp.Default.Value.RawMessage, &defaultValue); err != nil {
+					return fmt.Errorf("unable to decode default value of import %s: %w", imp.Name, err)
+				}
+				value, err = valueNode(defaultValue)
+			} else {
+				value, err = g.exampleNode(obj, nil)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to create example value of import %s: %w", imp.Name, err)
+			}
+		}
+
+		required := imp.Required == nil || *imp.Required
+		switch {
+		case len(condition) != 0 && required:
+			comments = append(comments, fmt.Sprintf("Required if import %s is set.", condition))
+		case len(condition) != 0:
+			comments = append(comments, fmt.Sprintf("Optional, only used if import %s is set.", condition))
+		case imp.Default.Value.RawMessage != nil:
+			comments = append(comments, "Optional, the example value is the default value.")
+		case required:
+			comments = append(comments, "Required.")
+		default:
+			comments = append(comments, "Optional.")
+		}
+
+		key := scalarNode(imp.Name)
+		key.HeadComment = strings.Join(comments, "\n")
+		imports.Content = append(imports.Content, key, value)
+		if err := g.addImports(imports, imp.ConditionalImports, imp.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deref follows the references of the schema into the bundle.
+// It returns the referenced schema and the references that have been visited including the followed ones.
+// Nil is returned for references that point outside the bundle and for cyclic references.
+func (g *exampleGenerator) deref(schema map[string]interface{}, visited map[string]bool) (map[string]interface{}, map[string]bool) {
+	for schema != nil {
+		ref, ok := schema[refKey].(string)
+		if !ok {
+			return schema, visited
+		}
+		if !strings.HasPrefix(ref, "#") || visited[ref] {
+			return nil, visited
+		}
+		next := make(map[string]bool, len(visited)+1)
+		for r := range visited {
+			next[r] = true
+		}
+		next[ref] = true
+		visited = next
+
+		var current interface{} = g.root
+		for _, elem := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+			elem = strings.NewReplacer("~1", "/", "~0", "~").Replace(elem)
+			switch typed := current.(type) {
+			case map[string]interface{}:
+				current = typed[elem]
+			case []interface{}:
+				i, err := strconv.Atoi(elem)
+				if err != nil || i < 0 || i >= len(typed) {
+					return nil, visited
+				}
+				current = typed[i]
+			default:
+				return nil, visited
+			}
+		}
+		schema, _ = current.(map[string]interface{})
+	}
+	return nil, visited
+}
+
+// exampleTarget returns an example target of the given type.
+func exampleTarget(name, targetType string) *yamlv3.Node {
+	return &yamlv3.Node{
+		Kind: yamlv3.MappingNode,
+		Content: []*yamlv3.Node{
+			scalarNode("metadata"),
+			{
+				Kind: yamlv3.MappingNode,
+				Content: []*yamlv3.Node{
+					scalarNode("name"), scalarNode(name),
+					scalarNode("namespace"), scalarNode("default"),
+				},
+			},
+			scalarNode("spec"),
+			{
+				Kind: yamlv3.MappingNode,
+				Content: []*yamlv3.Node{
+					scalarNode("type"), scalarNode(targetType),
+					scalarNode("config"), {Kind: yamlv3.MappingNode, Style: yamlv3.FlowStyle},
+				},
+			},
+		},
+	}
+}
+
+// exampleNode returns an example value for the schema.
+// The value is taken from the default, the examples, the const value or the first enum value of the schema.
+// Otherwise, objects are generated with all their properties and arrays with a single item.
+// Cyclic references are not expanded, so that arrays of a recursive type are empty.
+func (g *exampleGenerator) exampleNode(schema map[string]interface{}, visited map[string]bool) (*yamlv3.Node, error) {
+	schema, visited = g.deref(schema, visited)
+	if schema == nil {
+		return valueNode(nil)
+	}
+	if value, ok := schema["default"]; ok {
+		return valueNode(value)
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) != 0 {
+		return valueNode(examples[0])
+	}
+	if value, ok := schema["example"]; ok {
+		return valueNode(value)
+	}
+	if value, ok := schema["const"]; ok {
+		return valueNode(value)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) != 0 {
+		return valueNode(enum[0])
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if subschemas, ok := schema[key].([]interface{}); ok && len(subschemas) != 0 {
+			merged := g.mergeSchemas(schema, subschemas, key == "allOf", visited)
+			return g.exampleNode(merged, visited)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		node := &yamlv3.Node{Kind: yamlv3.MappingNode}
+		properties, _ := schema["properties"].(map[string]interface{})
+		required := map[string]bool{}
+		if list, ok := schema["required"].([]interface{}); ok {
+			for _, name := range list {
+				if s, ok := name.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, _ := properties[name].(map[string]interface{})
+			value, err := g.exampleNode(property, visited)
+			if err != nil {
+				return nil, err
+			}
+			comments := g.schemaComments(property)
+			if required[name] {
+				comments = append(comments, "Required.")
+			}
+			key := scalarNode(name)
+			key.HeadComment = strings.Join(comments, "\n")
+			node.Content = append(node.Content, key, value)
+		}
+		if len(node.Content) == 0 {
+			node.Style = yamlv3.FlowStyle
+		}
+		return node, nil
+	case "array":
+		node := &yamlv3.Node{Kind: yamlv3.SequenceNode}
+		items, _ := schema["items"].(map[string]interface{})
+		if items, _ = g.deref(items, visited); items == nil {
+			node.Style = yamlv3.FlowStyle
+			return node, nil
+		}
+		item, err := g.exampleNode(items, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, item)
+		return node, nil
+	case "string":
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "", Style: yamlv3.DoubleQuotedStyle}, nil
+	case "integer", "number":
+		return valueNode(0)
+	case "boolean":
+		return valueNode(false)
+	default:
+		return valueNode(nil)
+	}
+}
+
+// mergeSchemas merges the subschemas of allOf into the schema or uses the first subschema of anyOf and oneOf.
+func (g *exampleGenerator) mergeSchemas(schema map[string]interface{}, subschemas []interface{}, all bool, visited map[string]bool) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range schema {
+		if key != "allOf" && key != "anyOf" && key != "oneOf" {
+			merged[key] = value
+		}
+	}
+	if !all {
+		subschemas = subschemas[:1]
+	}
+	properties := map[string]interface{}{}
+	if existing, ok := merged["properties"].(map[string]interface{}); ok {
+		for name, property := range existing {
+			properties[name] = property
+		}
+	}
+	var required []interface{}
+	if existing, ok := merged["required"].([]interface{}); ok {
+		required = append(required, existing...)
+	}
+	for _, sub := range subschemas {
+		subschema, _ := sub.(map[string]interface{})
+		if subschema, _ = g.deref(subschema, visited); subschema == nil {
+			continue
+		}
+		for key, value := range subschema {
+			switch key {
+			case "properties":
+				if subProperties, ok := value.(map[string]interface{}); ok {
+					for name, property := range subProperties {
+						properties[name] = property
+					}
+				}
+			case "required":
+				if subRequired, ok := value.([]interface{}); ok {
+					required = append(required, subRequired...)
+				}
+			default:
+				if _, ok := merged[key]; !ok {
+					merged[key] = value
+				}
+			}
+		}
+	}
+	if len(properties) != 0 {
+		merged["properties"] = properties
+	}
+	if len(required) != 0 {
+		merged["required"] = required
+	}
+	return merged
+}
+
+// schemaType returns the type of the schema.
+// The first type that is not null is used for schemas with multiple types.
+func schemaType(schema map[string]interface{}) string {
+	switch typed := schema["type"].(type) {
+	case string:
+		return typed
+	case []interface{}:
+		for _, t := range typed {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// schemaComments returns the comment lines that describe the schema.
+func (g *exampleGenerator) schemaComments(schema map[string]interface{}) []string {
+	schema, _ = g.deref(schema, nil)
+	var comments []string
+	if description, ok := schema["description"].(string); ok && len(strings.TrimSpace(description)) != 0 {
+		comments = append(comments, strings.TrimSpace(description))
+	}
+	typeText := schemaType(schema)
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) != 0 {
+		values := make([]string, 0, len(enum))
+		for _, value := range enum {
+			data, _ := json.Marshal(normalizeNumbers(value))
+			values = append(values, string(data))
+		}
+		typeText = "one of " + strings.Join(values, ", ")
+	}
+	if len(typeText) != 0 {
+		comments = append(comments, fmt.Sprintf("Type: %s.", typeText))
+	}
+	return comments
+}
+
+// valueNode encodes a value as yaml node.
+func valueNode(value interface{}) (*yamlv3.Node, error) {
+	node := &yamlv3.Node{}
+	if err := node.Encode(normalizeNumbers(value)); err != nil {
+		return nil, err
+	}
+	if (node.Kind == yamlv3.MappingNode || node.Kind == yamlv3.SequenceNode) && len(node.Content) == 0 {
+		node.Style = yamlv3.FlowStyle
+	}
+	return node, nil
+}
+
+// normalizeNumbers converts json numbers into integers or floats so that they are encoded as yaml numbers.
+func normalizeNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		if i, err := typed.Int64(); err == nil {
+			return i
+		}
+		if f, err := typed.Float64(); err == nil {
+			return f
+		}
+		return typed.String()
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(typed))
+		for key, v := range typed {
+			res[key] = normalizeNumbers(v)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(typed))
+		for i, v := range typed {
+			res[i] = normalizeNumbers(v)
+		}
+		return res
+	default:
+		return value
+	}
+}
+
+func scalarNode(value string) *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: value}
+}
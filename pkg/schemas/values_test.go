@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schemas_test
+
+import (
+	"testing"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/landscapercli/pkg/schemas"
+	"github.com/gardener/landscapercli/pkg/util"
+)
+
+func TestGenerateExampleValues(t *testing.T) {
+	fs, err := projectionfs.New(osfs.New(), "./testdata/blueprint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	generator := &schemas.ValuesGenerator{Fs: fs}
+	node, err := generator.Generate()
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := util.MarshalYaml(node)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out := string(data)
+	assert.Contains(t, out, "  # Type: target of type landscaper.gardener.cloud/kubernetes-cluster.\n  # Required.\n  cluster:\n")
+	assert.Contains(t, out, "  # Namespace of the application\n  # Type: string.\n  # Required.\n  namespace: \"\"\n")
+	assert.Contains(t, out, "    # Type: one of \"debug\", \"info\", \"error\".\n    # Required.\n    logLevel: debug\n")
+	assert.Contains(t, out, "  # Type: integer.\n  # Required if import config is set.\n  replicas: 0\n")
+	assert.Contains(t, out, "  # Optional, the example value is the default value.\n  image: nginx:1.21\n")
+
+	values := map[string]map[string]interface{}{}
+	if !assert.NoError(t, yaml.Unmarshal(data, &values)) {
+		return
+	}
+	imports := values["imports"]
+	assert.Equal(t, map[string]interface{}{
+		"hosts":     []interface{}{"example.com"},
+		"logLevel":  "debug",
+		"namespace": "",
+		"port":      float64(8080),
+	}, imports["config"])
+	assert.Equal(t, map[string]interface{}{
+		"children": []interface{}{},
+		"name":     "",
+	}, imports["tree"], "expect the cyclic reference not to be expanded")
+	assert.Equal(t, "landscaper.gardener.cloud/kubernetes-cluster", imports["cluster"].(map[string]interface{})["spec"].(map[string]interface{})["type"])
+}
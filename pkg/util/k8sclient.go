@@ -6,6 +6,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
@@ -14,19 +15,9 @@ import (
 
 //GetK8sClientFromCurrentConfiguredCluster returns a k8sClient and a namespace from the current context of the kubectl program.
 func GetK8sClientFromCurrentConfiguredCluster() (client.Client, string, error) {
-	rules := clientcmd.NewDefaultClientConfigLoadingRules()
-	rules.DefaultClientConfig = &clientcmd.DefaultClientConfig
-
-	overrides := &clientcmd.ConfigOverrides{ClusterDefaults: clientcmd.ClusterDefaults}
-	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
-	cfg, err := clientConfig.ClientConfig()
-
+	cfg, namespace, err := BuildKubeConfigFromConfigOrCurrentClusterContext("")
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot build k8s config %w", err)
-	}
-	namespace, _, err := clientConfig.Namespace()
-	if err != nil {
-		return nil, "", fmt.Errorf("error extracting namespace from current k8s context. %w", err)
+		return nil, "", err
 	}
 
 	scheme := runtime.NewScheme()
@@ -47,9 +38,9 @@ func BuildKubeClientFromConfigOrCurrentClusterContext(kubeconfig string, scheme
 	namespace := ""
 	var k8sClient client.Client
 	if kubeconfig != "" {
-		cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		cfg, _, err := BuildKubeConfigFromConfigOrCurrentClusterContext(kubeconfig)
 		if err != nil {
-			return nil, namespace, fmt.Errorf("cannot parse K8s config: %w", err)
+			return nil, namespace, err
 		}
 		k8sClient, err = client.New(cfg, client.Options{
 			Scheme: scheme,
@@ -66,3 +57,30 @@ func BuildKubeClientFromConfigOrCurrentClusterContext(kubeconfig string, scheme
 
 	return k8sClient, namespace, nil
 }
+
+// BuildKubeConfigFromConfigOrCurrentClusterContext returns the rest config for the given kubeconfig
+// or for the current context of the kubectl program together with its namespace.
+func BuildKubeConfigFromConfigOrCurrentClusterContext(kubeconfig string) (*rest.Config, string, error) {
+	if kubeconfig != "" {
+		cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, "", fmt.Errorf("cannot parse K8s config: %w", err)
+		}
+		return cfg, "", nil
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.DefaultClientConfig = &clientcmd.DefaultClientConfig
+
+	overrides := &clientcmd.ConfigOverrides{ClusterDefaults: clientcmd.ClusterDefaults}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot build k8s config %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("error extracting namespace from current k8s context. %w", err)
+	}
+	return cfg, namespace, nil
+}
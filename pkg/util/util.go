@@ -250,6 +250,44 @@ func BuildKubernetesClusterTarget(name, namespace, kubeconfigPath string) (*lsv1
 	return target, nil
 }
 
+// GetKubeconfigFromTarget returns the kubeconfig of a kubernetes cluster target.
+// A kubeconfig that is referenced by a secret is read from the secret in the namespace of the target.
+func GetKubeconfigFromTarget(ctx context.Context, k8sClient client.Client, target *lsv1alpha1.Target) ([]byte, error) {
+	if target.Spec.Type != lsv1alpha1.KubernetesClusterTargetType {
+		return nil, fmt.Errorf("target %s has type %s, but expected type %s", target.Name, target.Spec.Type, lsv1alpha1.KubernetesClusterTargetType)
+	}
+
+	config := lsv1alpha1.KubernetesClusterTargetConfig{}
+	if err := json.Unmarshal(target.Spec.Configuration.RawMessage, &config); err != nil {
+		return nil, fmt.Errorf("cannot decode configuration of target %s: %w", target.Name, err)
+	}
+	if config.Kubeconfig.StrVal != nil {
+		return []byte(*config.Kubeconfig.StrVal), nil
+	}
+	if config.Kubeconfig.SecretRef == nil {
+		return nil, fmt.Errorf("target %s contains no kubeconfig", target.Name)
+	}
+
+	ref := config.Kubeconfig.SecretRef
+	namespace := ref.Namespace
+	if len(namespace) == 0 {
+		namespace = target.Namespace
+	}
+	key := ref.Key
+	if len(key) == 0 {
+		key = lsv1alpha1.DefaultKubeconfigKey
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("cannot get secret %s/%s of target %s: %w", namespace, ref.Name, target.Name, err)
+	}
+	kubeconfig, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s of target %s has no key %s", namespace, ref.Name, target.Name, key)
+	}
+	return kubeconfig, nil
+}
+
 func GetBlueprintResource(cd *cdv2.ComponentDescriptor, blueprintResourceName string) (*cdv2.Resource, error) {
 	blueprintResources := map[string]cdv2.Resource{}
 	for _, resource := range cd.ComponentSpec.Resources {
@@ -1,12 +1,17 @@
 package util
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
 	"github.com/gardener/landscaper/apis/mediatype"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGetBlueprintResource(t *testing.T) {
@@ -167,3 +172,69 @@ func TestGetBlueprintResource(t *testing.T) {
 		})
 	}
 }
+
+func TestGetKubeconfigFromTarget(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: "my-namespace"},
+		Data: map[string][]byte{
+			lsv1alpha1.DefaultKubeconfigKey: []byte("kubeconfig-from-secret"),
+			"other":                         []byte("kubeconfig-from-other-key"),
+		},
+	}).Build()
+
+	newTarget := func(targetType lsv1alpha1.TargetType, config string) *lsv1alpha1.Target {
+		return &lsv1alpha1.Target{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-target", Namespace: "my-namespace"},
+			Spec: lsv1alpha1.TargetSpec{
+				Type:          targetType,
+				Configuration: lsv1alpha1.NewAnyJSON([]byte(config)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		target             *lsv1alpha1.Target
+		expectedKubeconfig string
+		expectedErr        string
+	}{
+		{
+			name:               "inline kubeconfig",
+			target:             newTarget(lsv1alpha1.KubernetesClusterTargetType, `{"kubeconfig": "inline-kubeconfig"}`),
+			expectedKubeconfig: "inline-kubeconfig",
+		},
+		{
+			name:               "kubeconfig from secret with default key",
+			target:             newTarget(lsv1alpha1.KubernetesClusterTargetType, `{"kubeconfig": {"secretRef": {"name": "my-secret"}}}`),
+			expectedKubeconfig: "kubeconfig-from-secret",
+		},
+		{
+			name:               "kubeconfig from secret with key",
+			target:             newTarget(lsv1alpha1.KubernetesClusterTargetType, `{"kubeconfig": {"secretRef": {"name": "my-secret", "namespace": "my-namespace", "key": "other"}}}`),
+			expectedKubeconfig: "kubeconfig-from-other-key",
+		},
+		{
+			name:        "missing key in secret",
+			target:      newTarget(lsv1alpha1.KubernetesClusterTargetType, `{"kubeconfig": {"secretRef": {"name": "my-secret", "key": "missing"}}}`),
+			expectedErr: "secret my-namespace/my-secret of target my-target has no key missing",
+		},
+		{
+			name:        "wrong target type",
+			target:      newTarget("landscaper.gardener.cloud/mock", `{}`),
+			expectedErr: "target my-target has type landscaper.gardener.cloud/mock, but expected type landscaper.gardener.cloud/kubernetes-cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfig, err := GetKubeconfigFromTarget(ctx, k8sClient, tt.target)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKubeconfig, string(kubeconfig))
+		})
+	}
+}
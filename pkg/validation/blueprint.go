@@ -0,0 +1,382 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/gardener/component-spec/bindings-go/ctf"
+	"github.com/gardener/landscaper/apis/core"
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	lsvalidation "github.com/gardener/landscaper/apis/core/validation"
+	"github.com/gardener/landscaper/pkg/api"
+	"github.com/gardener/landscaper/pkg/landscaper/jsonschema"
+	"github.com/gardener/landscaper/pkg/landscaper/registry/components/cdutils"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/gardener/landscapercli/pkg/executions"
+)
+
+// componentDescriptorScheme is the scheme of references that are resolved with the component descriptor.
+const componentDescriptorScheme = "cd"
+
+// BlueprintValidator validates a blueprint directory.
+type BlueprintValidator struct {
+	// Fs is the filesystem of the blueprint directory.
+	Fs vfs.FileSystem
+	// ComponentDescriptor is the optional component descriptor of the blueprint.
+	// References to the component descriptor are only resolved if it is set.
+	ComponentDescriptor *cdv2.ComponentDescriptor
+	// ComponentResolver resolves referenced component descriptors.
+	ComponentResolver ctf.ComponentResolver
+}
+
+// Validate checks the blueprint and returns all findings.
+// An error is only returned if the blueprint could not be checked at all.
+func (v *BlueprintValidator) Validate() (Findings, error) {
+	data, err := vfs.ReadFile(v.Fs, lsv1alpha1.BlueprintFileName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", lsv1alpha1.BlueprintFileName, err)
+	}
+	blueprint := &lsv1alpha1.Blueprint{}
+	if _, _, err := serializer.NewCodecFactory(api.LandscaperScheme).UniversalDecoder().Decode(data, nil, blueprint); err != nil {
+		return Findings{{
+			Rule:     RuleBlueprintDecode,
+			Severity: SeverityError,
+			File:     lsv1alpha1.BlueprintFileName,
+			Message:  err.Error(),
+		}}, nil
+	}
+	doc := parseYAMLFile(data)
+
+	findings := Findings{}
+	specFindings, err := v.validateSpec(blueprint, doc)
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, specFindings...)
+
+	fileFindings := v.validateFileReferences(blueprint, doc)
+	findings = append(findings, fileFindings...)
+	findings = append(findings, v.validateSchemas(blueprint, doc)...)
+
+	// the templates are only located if all execution files exist
+	if len(fileFindings) == 0 {
+		templateFindings, err := v.validateTemplates(blueprint)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, templateFindings...)
+	}
+
+	findings = append(findings, v.validateComponentReferences(blueprint, doc)...)
+	return findings, nil
+}
+
+// validateSpec runs the validation of the landscaper api.
+func (v *BlueprintValidator) validateSpec(blueprint *lsv1alpha1.Blueprint, doc *yamlFile) (Findings, error) {
+	coreBlueprint := &core.Blueprint{}
+	if err := lsv1alpha1.Convert_v1alpha1_Blueprint_To_core_Blueprint(blueprint, coreBlueprint, nil); err != nil {
+		return nil, err
+	}
+	findings := Findings{}
+	for _, fldErr := range lsvalidation.ValidateBlueprint(coreBlueprint) {
+		line, column := doc.Position(fldErr.Field)
+		findings = append(findings, Finding{
+			Rule:     RuleBlueprintSpec,
+			Severity: SeverityError,
+			File:     lsv1alpha1.BlueprintFileName,
+			Line:     line,
+			Column:   column,
+			Message:  fldErr.Error(),
+		})
+	}
+	return findings, nil
+}
+
+// validateFileReferences checks that all files referenced by executions and subinstallations exist.
+func (v *BlueprintValidator) validateFileReferences(blueprint *lsv1alpha1.Blueprint, doc *yamlFile) Findings {
+	findings := Findings{}
+	check := func(fldPath *field.Path, file string) {
+		if len(file) == 0 {
+			return
+		}
+		exists, err := vfs.FileExists(v.Fs, file)
+		if err == nil && exists {
+			return
+		}
+		msg := fmt.Sprintf("%s: file %q does not exist in the blueprint directory", fldPath, file)
+		if err != nil {
+			msg = fmt.Sprintf("%s: unable to read file %q: %s", fldPath, file, err.Error())
+		}
+		line, column := doc.Position(fldPath.String())
+		findings = append(findings, Finding{
+			Rule:     RuleFileReference,
+			Severity: SeverityError,
+			File:     lsv1alpha1.BlueprintFileName,
+			Line:     line,
+			Column:   column,
+			Message:  msg,
+		})
+	}
+
+	executionLists := []struct {
+		kind  executions.Kind
+		execs []lsv1alpha1.TemplateExecutor
+	}{
+		{executions.DeployExecution, blueprint.DeployExecutions},
+		{executions.SubinstallationExecution, blueprint.SubinstallationExecutions},
+		{executions.ExportExecution, blueprint.ExportExecutions},
+	}
+	for _, list := range executionLists {
+		for i, exec := range list.execs {
+			check(field.NewPath(string(list.kind)).Index(i).Child("file"), exec.File)
+		}
+	}
+	for i, subinst := range blueprint.Subinstallations {
+		check(field.NewPath("subinstallations").Index(i).Child("file"), subinst.File)
+	}
+	return findings
+}
+
+// validateSchemas checks that the schemas of all imports, exports and local types are valid json schemas
+// and that their references can be resolved.
+func (v *BlueprintValidator) validateSchemas(blueprint *lsv1alpha1.Blueprint, doc *yamlFile) Findings {
+	findings := Findings{}
+	check := func(fldPath *field.Path, schema *lsv1alpha1.JSONSchemaDefinition) {
+		if schema == nil || len(schema.RawMessage) == 0 {
+			return
+		}
+		line, column := doc.Position(fldPath.String())
+		finding := Finding{
+			Rule:     RuleJSONSchema,
+			Severity: SeverityError,
+			File:     lsv1alpha1.BlueprintFileName,
+			Line:     line,
+			Column:   column,
+		}
+
+		if v.ComponentDescriptor == nil {
+			refs, err := componentDescriptorRefs(schema.RawMessage, blueprint.LocalTypes)
+			if err != nil {
+				finding.Message = fmt.Sprintf("%s: invalid json schema: %s", fldPath, err.Error())
+				findings = append(findings, finding)
+				return
+			}
+			if len(refs) != 0 {
+				finding.Severity = SeverityWarning
+				finding.Message = fmt.Sprintf("%s: the references %s can only be resolved with a component descriptor",
+					fldPath, strings.Join(refs, ", "))
+				findings = append(findings, finding)
+				return
+			}
+		}
+
+		err := jsonschema.NewValidator(&jsonschema.ReferenceContext{
+			LocalTypes:          blueprint.LocalTypes,
+			BlueprintFs:         v.Fs,
+			ComponentDescriptor: v.ComponentDescriptor,
+			ComponentResolver:   v.ComponentResolver,
+		}).CompileSchema(schema.RawMessage)
+		if err != nil {
+			finding.Message = fmt.Sprintf("%s: invalid json schema: %s", fldPath, err.Error())
+			findings = append(findings, finding)
+		}
+	}
+
+	var checkImports func(fldPath *field.Path, imports lsv1alpha1.ImportDefinitionList)
+	checkImports = func(fldPath *field.Path, imports lsv1alpha1.ImportDefinitionList) {
+		for i, imp := range imports {
+			check(fldPath.Index(i).Child("schema"), imp.Schema)
+			checkImports(fldPath.Index(i).Child("imports"), imp.ConditionalImports)
+		}
+	}
+	checkImports(field.NewPath("imports"), blueprint.Imports)
+
+	for i, exp := range blueprint.Exports {
+		check(field.NewPath("exports").Index(i).Child("schema"), exp.Schema)
+	}
+
+	localTypeNames := make([]string, 0, len(blueprint.LocalTypes))
+	for name := range blueprint.LocalTypes {
+		localTypeNames = append(localTypeNames, name)
+	}
+	sort.Strings(localTypeNames)
+	for _, name := range localTypeNames {
+		schema := blueprint.LocalTypes[name]
+		check(field.NewPath("localTypes").Key(name), &schema)
+	}
+	return findings
+}
+
+// validateTemplates parses the go templates of all executions.
+func (v *BlueprintValidator) validateTemplates(blueprint *lsv1alpha1.Blueprint) (Findings, error) {
+	sources, err := executions.LocateExecutions(v.Fs)
+	if err != nil {
+		return nil, err
+	}
+
+	importKeys := []string{}
+	var addImportKeys func(imports lsv1alpha1.ImportDefinitionList)
+	addImportKeys = func(imports lsv1alpha1.ImportDefinitionList) {
+		for _, imp := range imports {
+			importKeys = append(importKeys, imp.Name)
+			addImportKeys(imp.ConditionalImports)
+		}
+	}
+	addImportKeys(blueprint.Imports)
+	sort.Strings(importKeys)
+
+	findings := Findings{}
+	for _, source := range sources {
+		err := source.ParseTemplate(importKeys)
+		if err == nil {
+			continue
+		}
+		var tmplErr *executions.TemplateError
+		if !errors.As(err, &tmplErr) {
+			return nil, err
+		}
+		findings = append(findings, Finding{
+			Rule:     RuleTemplateSyntax,
+			Severity: SeverityError,
+			File:     source.File,
+			Line:     tmplErr.Line,
+			Column:   tmplErr.Column,
+			Message:  fmt.Sprintf("error in %s: %s", source, tmplErr.Message),
+			Details:  tmplErr.Details(),
+		})
+	}
+	return findings, nil
+}
+
+// validateComponentReferences checks that the component descriptor references of the blueprints of all
+// subinstallations can be resolved.
+func (v *BlueprintValidator) validateComponentReferences(blueprint *lsv1alpha1.Blueprint, doc *yamlFile) Findings {
+	findings := Findings{}
+	check := func(file string, fileDoc *yamlFile, fldPath *field.Path, tmpl *lsv1alpha1.InstallationTemplate) {
+		ref := tmpl.Blueprint.Ref
+		if !strings.HasPrefix(ref, componentDescriptorScheme+"://") {
+			return
+		}
+		line, column := fileDoc.Position(fldPath.String())
+		finding := Finding{
+			Rule:     RuleComponentReference,
+			Severity: SeverityError,
+			File:     file,
+			Line:     line,
+			Column:   column,
+		}
+
+		if v.ComponentDescriptor == nil {
+			finding.Severity = SeverityWarning
+			finding.Message = fmt.Sprintf("%s: the blueprint reference %q of subinstallation %q can only be resolved with a component descriptor",
+				fldPath, ref, tmpl.Name)
+			findings = append(findings, finding)
+			return
+		}
+
+		uri, err := cdutils.ParseURI(ref)
+		if err == nil {
+			_, _, err = uri.GetResource(v.ComponentDescriptor, v.ComponentResolver, v.ComponentDescriptor.GetEffectiveRepositoryContext())
+		}
+		if err != nil {
+			finding.Message = fmt.Sprintf("%s: unable to resolve the blueprint reference %q of subinstallation %q: %s",
+				fldPath, ref, tmpl.Name, err.Error())
+			findings = append(findings, finding)
+		}
+	}
+
+	for i, subinst := range blueprint.Subinstallations {
+		if subinst.InstallationTemplate != nil {
+			check(lsv1alpha1.BlueprintFileName, doc, field.NewPath("subinstallations").Index(i).Child("blueprint", "ref"), subinst.InstallationTemplate)
+			continue
+		}
+		// missing files have already been reported
+		data, err := vfs.ReadFile(v.Fs, subinst.File)
+		if err != nil {
+			continue
+		}
+		tmpl := &lsv1alpha1.InstallationTemplate{}
+		if err := yaml.Unmarshal(data, tmpl); err != nil {
+			findings = append(findings, Finding{
+				Rule:     RuleBlueprintDecode,
+				Severity: SeverityError,
+				File:     strings.TrimPrefix(subinst.File, "/"),
+				Message:  fmt.Sprintf("unable to decode subinstallation: %s", err.Error()),
+			})
+			continue
+		}
+		check(strings.TrimPrefix(subinst.File, "/"), parseYAMLFile(data), field.NewPath("blueprint", "ref"), tmpl)
+	}
+	return findings
+}
+
+// componentDescriptorRefs returns all component descriptor references of a json schema
+// including the references of the local types that are used by the schema.
+func componentDescriptorRefs(schema []byte, localTypes map[string]lsv1alpha1.JSONSchemaDefinition) ([]string, error) {
+	refs := []string{}
+	visited := map[string]bool{}
+
+	var walk func(data interface{}) error
+	walk = func(data interface{}) error {
+		switch val := data.(type) {
+		case map[string]interface{}:
+			if ref, ok := val["$ref"].(string); ok {
+				uri, err := url.Parse(ref)
+				if err != nil {
+					return fmt.Errorf("invalid reference %q: %w", ref, err)
+				}
+				switch uri.Scheme {
+				case componentDescriptorScheme:
+					refs = append(refs, ref)
+				case "local":
+					localType, ok := localTypes[uri.Host]
+					if ok && !visited[uri.Host] {
+						visited[uri.Host] = true
+						var localData interface{}
+						if err := json.Unmarshal(localType.RawMessage, &localData); err != nil {
+							return fmt.Errorf("invalid local type %q: %w", uri.Host, err)
+						}
+						if err := walk(localData); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			for _, elem := range val {
+				if err := walk(elem); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, elem := range val {
+				if err := walk(elem); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(schema, &data); err != nil {
+		return nil, err
+	}
+	if err := walk(data); err != nil {
+		return nil, err
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"testing"
+
+	cdv2 "github.com/gardener/component-spec/bindings-go/apis/v2"
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	"github.com/mandelsoft/vfs/pkg/vfs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/validation"
+)
+
+func TestValidateValidBlueprint(t *testing.T) {
+	validator := &validation.BlueprintValidator{
+		Fs: testBlueprintFs(t, "valid"),
+	}
+	findings, err := validator.Validate()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, findings.HasErrors())
+	if assert.Len(t, findings, 1, "expect a warning for the component descriptor reference") {
+		assert.Equal(t, validation.RuleComponentReference, findings[0].Rule)
+		assert.Equal(t, validation.SeverityWarning, findings[0].Severity)
+		assert.Equal(t, "blueprint.yaml:35:5", findings[0].Location())
+	}
+}
+
+func TestValidateWithComponentDescriptor(t *testing.T) {
+	validator := &validation.BlueprintValidator{
+		Fs:                  testBlueprintFs(t, "valid"),
+		ComponentDescriptor: testComponentDescriptor("sub-blueprint"),
+	}
+	findings, err := validator.Validate()
+	if assert.NoError(t, err) {
+		assert.Len(t, findings, 0)
+	}
+
+	validator.ComponentDescriptor = testComponentDescriptor("other-blueprint")
+	findings, err = validator.Validate()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, findings.HasErrors())
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, validation.RuleComponentReference, findings[0].Rule)
+		assert.Contains(t, findings[0].Message, "local resource sub-blueprint cannot be found")
+	}
+}
+
+func TestValidateInvalidBlueprint(t *testing.T) {
+	validator := &validation.BlueprintValidator{
+		Fs: testBlueprintFs(t, "invalid"),
+	}
+	findings, err := validator.Validate()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, findings.HasErrors())
+
+	locations := map[string]string{}
+	for _, finding := range findings {
+		locations[finding.Location()] = finding.Rule
+		if finding.Rule == validation.RuleTemplateSyntax {
+			t.Errorf("templates must not be parsed if execution files are missing: %s", finding)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"blueprint.yaml:5:3":  validation.RuleJSONSchema,
+		"blueprint.yaml:10:3": validation.RuleJSONSchema,
+		"blueprint.yaml:14:3": validation.RuleJSONSchema,
+		"blueprint.yaml:17:3": validation.RuleJSONSchema,
+		"blueprint.yaml:23:3": validation.RuleFileReference,
+	}, locations)
+	assert.Equal(t, 1, findings.Count(validation.SeverityWarning), "expect a warning for the component descriptor reference")
+}
+
+func TestValidateTemplates(t *testing.T) {
+	validator := &validation.BlueprintValidator{
+		Fs: testBlueprintFs(t, "template-error"),
+	}
+	findings, err := validator.Validate()
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, validation.RuleTemplateSyntax, findings[0].Rule)
+		assert.Equal(t, "deploy-execution.yaml:3", findings[0].Location())
+		assert.Contains(t, findings[0].Message, `function "invalidFunc" not defined`)
+		assert.Contains(t, findings[0].Details, "available imports: replicas")
+	}
+}
+
+func testBlueprintFs(t *testing.T, name string) vfs.FileSystem {
+	fs, err := projectionfs.New(osfs.New(), "./testdata/"+name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func testComponentDescriptor(resourceName string) *cdv2.ComponentDescriptor {
+	cd := &cdv2.ComponentDescriptor{}
+	cd.Name = "example.com/validate"
+	cd.Version = "v0.1.0"
+	cd.Resources = []cdv2.Resource{
+		{
+			IdentityObjectMeta: cdv2.IdentityObjectMeta{
+				Name:    resourceName,
+				Version: "v0.1.0",
+				Type:    "blueprint",
+			},
+			Relation: cdv2.LocalRelation,
+		},
+	}
+	return cd
+}
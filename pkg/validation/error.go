@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error is an error that is described by findings.
+// It is used to report validation errors that can be printed as structured findings.
+type Error struct {
+	Findings Findings
+}
+
+// Errorf returns an error with a single finding of severity error for the given rule.
+func Errorf(rule string, format string, args ...interface{}) error {
+	return &Error{
+		Findings: Findings{{
+			Rule:     rule,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf(format, args...),
+		}},
+	}
+}
+
+// FileErrorf returns an error with a single finding of severity error for the given rule and file.
+func FileErrorf(rule string, file string, format string, args ...interface{}) error {
+	err := Errorf(rule, format, args...).(*Error)
+	err.Findings[0].File = file
+	return err
+}
+
+// Error returns the messages of all findings.
+// Findings with a position in a file are printed with their location, severity and rule.
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Findings))
+	for _, finding := range e.Findings {
+		if finding.Line == 0 {
+			msgs = append(msgs, finding.Message)
+			continue
+		}
+		msgs = append(msgs, finding.String())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// FindingsFromError returns the findings that are described by the given error.
+// Errors that are not described by findings are reported as one finding of the given rule.
+func FindingsFromError(err error, rule string) Findings {
+	var validationErr *Error
+	if errors.As(err, &validationErr) {
+		return validationErr.Findings
+	}
+	return Findings{{
+		Rule:     rule,
+		Severity: SeverityError,
+		Message:  err.Error(),
+	}}
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	// SeverityError marks findings that make the blueprint unusable.
+	SeverityError Severity = "error"
+	// SeverityWarning marks findings that could not be checked completely or that are likely to cause problems.
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	// RuleBlueprintDecode reports a blueprint.yaml that cannot be decoded.
+	RuleBlueprintDecode = "blueprint-decode"
+	// RuleBlueprintSpec reports violations of the blueprint api validation.
+	RuleBlueprintSpec = "blueprint-spec"
+	// RuleFileReference reports files that are referenced by the blueprint but do not exist in the blueprint directory.
+	RuleFileReference = "file-reference"
+	// RuleJSONSchema reports invalid json schemas of imports, exports and local types.
+	RuleJSONSchema = "json-schema"
+	// RuleTemplateSyntax reports go templates of executions that cannot be parsed.
+	RuleTemplateSyntax = "template-syntax"
+	// RuleComponentReference reports component descriptor references that cannot be resolved.
+	RuleComponentReference = "component-reference"
+	// RuleInvalidArgument reports invalid arguments or flags of a command.
+	RuleInvalidArgument = "invalid-argument"
+	// RuleMissingArgument reports required arguments or flags of a command that are not set.
+	RuleMissingArgument = "missing-argument"
+	// RuleDeployItemName reports deploy item names that are no valid identifiers.
+	RuleDeployItemName = "deployitem-name"
+	// RuleDeployItemExists reports deploy items that already exist in a component.
+	RuleDeployItemExists = "deployitem-exists"
+	// RuleExecution reports errors that occurred while executing a command after all inputs have been validated.
+	RuleExecution = "execution"
+)
+
+// ruleDescriptions contains a short description of every rule.
+var ruleDescriptions = map[string]string{
+	RuleBlueprintDecode:    "The blueprint and its subinstallations can be decoded.",
+	RuleBlueprintSpec:      "The blueprint passes the validation of the landscaper api.",
+	RuleFileReference:      "Referenced files exist.",
+	RuleJSONSchema:         "Schemas are valid json schemas and their references can be resolved.",
+	RuleTemplateSyntax:     "Go templates of executions can be parsed.",
+	RuleComponentReference: "Component descriptor references can be resolved.",
+	RuleInvalidArgument:    "Arguments and flags are valid.",
+	RuleMissingArgument:    "Required arguments and flags are set.",
+	RuleDeployItemName:     "Deploy item names are valid identifiers.",
+	RuleDeployItemExists:   "Deploy items are not added twice.",
+	RuleExecution:          "The command can be executed.",
+}
+
+// Finding is a single problem that has been found by a validation.
+type Finding struct {
+	// Rule is the name of the check that reported the finding.
+	Rule string `json:"rule"`
+	// Severity is the severity of the finding.
+	Severity Severity `json:"severity"`
+	// File is the path of the affected file relative to the validated directory.
+	File string `json:"file,omitempty"`
+	// Line is the 1-based line in the file or 0 if it is unknown.
+	Line int `json:"line,omitempty"`
+	// Column is the 1-based column in the file or 0 if it is unknown.
+	Column int `json:"column,omitempty"`
+	// Message describes the finding.
+	Message string `json:"message"`
+	// Details contains additional multi-line information, e.g. a snippet of the affected file.
+	Details string `json:"details,omitempty"`
+}
+
+// Location returns the location of the finding as "file:line:column".
+func (f Finding) Location() string {
+	switch {
+	case f.Line == 0:
+		return f.File
+	case f.Column == 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+}
+
+// String returns the finding in the format "location: severity: message [rule]" followed by its details.
+func (f Finding) String() string {
+	var sb strings.Builder
+	if loc := f.Location(); len(loc) != 0 {
+		sb.WriteString(loc)
+		sb.WriteString(": ")
+	}
+	fmt.Fprintf(&sb, "%s: %s [%s]", f.Severity, f.Message, f.Rule)
+	if len(f.Details) != 0 {
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimSuffix(f.Details, "\n"))
+	}
+	return sb.String()
+}
+
+// Findings is a list of findings.
+type Findings []Finding
+
+// HasErrors returns whether at least one finding has the severity error.
+func (f Findings) HasErrors() bool {
+	for _, finding := range f {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// AsWarnings returns the findings with severity warning for all findings except those of the given rules.
+func (f Findings) AsWarnings(exceptRules ...string) Findings {
+	result := make(Findings, 0, len(f))
+	for _, finding := range f {
+		keep := false
+		for _, rule := range exceptRules {
+			keep = keep || finding.Rule == rule
+		}
+		if !keep {
+			finding.Severity = SeverityWarning
+		}
+		result = append(result, finding)
+	}
+	return result
+}
+
+// Count returns the number of findings with the given severity.
+func (f Findings) Count(severity Severity) int {
+	count := 0
+	for _, finding := range f {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// TextOutput prints the findings in a human-readable format.
+	TextOutput = "text"
+	// JSONOutput prints the findings as json report.
+	JSONOutput = "json"
+	// SARIFOutput prints the findings as SARIF 2.1.0 log.
+	SARIFOutput = "sarif"
+)
+
+// OutputFormats are all supported output formats of validation reports.
+var OutputFormats = []string{TextOutput, JSONOutput, SARIFOutput}
+
+// OutputFlagUsage is the usage of the flag that defines the output format of a validation report.
+var OutputFlagUsage = fmt.Sprintf("The format of the validation findings. Can be %s.", strings.Join(OutputFormats, ", "))
+
+// ValidateOutputFormat checks that the given output format is supported.
+func ValidateOutputFormat(format string) error {
+	for _, f := range OutputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return Errorf(RuleInvalidArgument, "output format is expected to be one of %s but got '%s'", strings.Join(OutputFormats, ", "), format)
+}
+
+// Report is the machine-readable result of a validation.
+type Report struct {
+	// Directory is the validated directory.
+	// The files of all findings are relative to it or to the working directory if it is empty.
+	Directory string `json:"directory,omitempty"`
+	// Findings contains all findings of the validation.
+	Findings Findings `json:"findings"`
+	// Errors is the number of findings with severity error.
+	Errors int `json:"errors"`
+	// Warnings is the number of findings with severity warning.
+	Warnings int `json:"warnings"`
+}
+
+// NewReport creates a new report for the findings of the given directory.
+func NewReport(directory string, findings Findings) *Report {
+	if findings == nil {
+		findings = Findings{}
+	}
+	return &Report{
+		Directory: directory,
+		Findings:  findings,
+		Errors:    findings.Count(SeverityError),
+		Warnings:  findings.Count(SeverityWarning),
+	}
+}
+
+// Write writes the report in the given format.
+// The text format contains only the findings, separated by empty lines.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case TextOutput:
+		for _, finding := range r.Findings {
+			if _, err := fmt.Fprintf(w, "%s\n\n", finding); err != nil {
+				return err
+			}
+		}
+		return nil
+	case JSONOutput:
+		return writeJSON(w, r)
+	case SARIFOutput:
+		return writeJSON(w, r.sarif())
+	default:
+		return ValidateOutputFormat(format)
+	}
+}
+
+// WriteReport writes the report of a command in the given format to stdout and returns the writer for all further
+// messages of the command. Structured reports must be the only output on stdout so that they can be processed further,
+// therefore the messages are written to stderr for the json and sarif formats.
+func WriteReport(stdout, stderr io.Writer, format string, report *Report) (io.Writer, error) {
+	out := stdout
+	if format != TextOutput {
+		out = stderr
+	}
+	return out, report.Write(stdout, format)
+}
+
+// PrintError prints an error of a command in the given format.
+// Errors that are not described by findings are reported as one finding of the given rule.
+// In the text format only the error message is printed.
+func PrintError(w io.Writer, format string, directory string, err error, rule string) {
+	if format != JSONOutput && format != SARIFOutput {
+		fmt.Fprintln(w, err.Error())
+		return
+	}
+	if writeErr := NewReport(directory, FindingsFromError(err, rule)).Write(w, format); writeErr != nil {
+		fmt.Fprintln(w, err.Error())
+	}
+}
+
+func writeJSON(w io.Writer, obj interface{}) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardener/landscapercli/pkg/validation"
+)
+
+var testFindings = validation.Findings{
+	{
+		Rule:     validation.RuleJSONSchema,
+		Severity: validation.SeverityError,
+		File:     "blueprint.yaml",
+		Line:     10,
+		Column:   3,
+		Message:  "imports[0].schema: invalid json schema",
+	},
+	{
+		Rule:     validation.RuleComponentReference,
+		Severity: validation.SeverityWarning,
+		File:     "subinstallations/sub.yaml",
+		Message:  "the blueprint reference can only be resolved with a component descriptor",
+	},
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if !assert.NoError(t, validation.NewReport("path/to/blueprint", testFindings).Write(buf, validation.JSONOutput)) {
+		return
+	}
+
+	report := &validation.Report{}
+	if !assert.NoError(t, json.Unmarshal(buf.Bytes(), report)) {
+		return
+	}
+	assert.Equal(t, "path/to/blueprint", report.Directory)
+	assert.Equal(t, testFindings, report.Findings)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if !assert.NoError(t, validation.NewReport("path/to/blueprint", testFindings).Write(buf, validation.SARIFOutput)) {
+		return
+	}
+
+	log := struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}{}
+	if !assert.NoError(t, json.Unmarshal(buf.Bytes(), &log)) {
+		return
+	}
+	assert.Equal(t, "2.1.0", log.Version)
+	if !assert.Len(t, log.Runs, 1) {
+		return
+	}
+	run := log.Runs[0]
+	assert.Equal(t, "landscaper-cli", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, 2)
+	if !assert.Len(t, run.Results, 2) {
+		return
+	}
+
+	assert.Equal(t, validation.RuleJSONSchema, run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	location := run.Results[0].Locations[0].PhysicalLocation
+	assert.Equal(t, "path/to/blueprint/blueprint.yaml", location.ArtifactLocation.URI)
+	if assert.NotNil(t, location.Region) {
+		assert.Equal(t, 10, location.Region.StartLine)
+		assert.Equal(t, 3, location.Region.StartColumn)
+	}
+
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, "path/to/blueprint/subinstallations/sub.yaml", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+}
+
+func TestWriteReport(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	out, err := validation.WriteReport(stdout, stderr, validation.TextOutput, validation.NewReport("", testFindings))
+	assert.NoError(t, err)
+	assert.Equal(t, stdout, out)
+	assert.Contains(t, stdout.String(), "imports[0].schema: invalid json schema")
+
+	stdout.Reset()
+	out, err = validation.WriteReport(stdout, stderr, validation.JSONOutput, validation.NewReport("", testFindings))
+	assert.NoError(t, err)
+	assert.Equal(t, stderr, out, "messages must not be mixed with the structured report")
+	assert.True(t, json.Valid(stdout.Bytes()))
+}
+
+func TestFindingsFromError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", validation.FileErrorf(validation.RuleFileReference, "deployment.yaml", "manifest file %s does not exist", "deployment.yaml"))
+	findings := validation.FindingsFromError(err, validation.RuleInvalidArgument)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, validation.RuleFileReference, findings[0].Rule)
+		assert.Equal(t, "deployment.yaml", findings[0].File)
+		assert.Equal(t, "manifest file deployment.yaml does not exist", findings[0].Message)
+	}
+
+	findings = validation.FindingsFromError(errors.New("some error"), validation.RuleInvalidArgument)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, validation.RuleInvalidArgument, findings[0].Rule)
+		assert.Equal(t, validation.SeverityError, findings[0].Severity)
+		assert.Equal(t, "some error", findings[0].Message)
+	}
+
+	assert.Error(t, validation.ValidateOutputFormat("xml"))
+	assert.NoError(t, validation.ValidateOutputFormat(validation.SARIFOutput))
+}
+
+func TestFindingsAsWarnings(t *testing.T) {
+	findings := testFindings.AsWarnings(validation.RuleComponentReference)
+	assert.Equal(t, validation.SeverityWarning, findings[0].Severity)
+	assert.Equal(t, validation.SeverityWarning, findings[1].Severity)
+	assert.False(t, findings.HasErrors())
+	assert.True(t, testFindings.HasErrors(), "the original findings must not be changed")
+
+	findings = testFindings.AsWarnings(validation.RuleJSONSchema)
+	assert.True(t, findings.HasErrors())
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"path"
+	"path/filepath"
+
+	"github.com/gardener/landscapercli/pkg/version"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifToolName           = "landscaper-cli"
+	sarifToolInformationURI = "https://github.com/gardener/landscapercli"
+)
+
+// The sarif types only contain the subset of the SARIF 2.1.0 format that is needed to report findings.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarif converts the report into a SARIF log with one run.
+func (r *Report) sarif() *sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           sarifToolName,
+				Version:        version.Get().GitVersion,
+				InformationURI: sarifToolInformationURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndices := map[string]int{}
+	for _, finding := range r.Findings {
+		index, ok := ruleIndices[finding.Rule]
+		if !ok {
+			description, ok := ruleDescriptions[finding.Rule]
+			if !ok {
+				description = finding.Rule
+			}
+			index = len(run.Tool.Driver.Rules)
+			ruleIndices[finding.Rule] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               finding.Rule,
+				ShortDescription: sarifMessage{Text: description},
+			})
+		}
+
+		result := sarifResult{
+			RuleID:    finding.Rule,
+			RuleIndex: index,
+			Level:     string(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+		}
+		if len(finding.File) != 0 {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: r.fileURI(finding.File)},
+				},
+			}
+			if finding.Line != 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   finding.Line,
+					StartColumn: finding.Column,
+				}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		if len(finding.Details) != 0 {
+			result.Properties = map[string]string{"details": finding.Details}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// fileURI returns the uri of a file relative to the working directory
+// so that the results can be mapped to the files of a repository.
+func (r *Report) fileURI(file string) string {
+	file = filepath.ToSlash(file)
+	if path.IsAbs(file) || len(r.Directory) == 0 {
+		return path.Clean(file)
+	}
+	return path.Join(filepath.ToSlash(r.Directory), file)
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"regexp"
+	"strconv"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// fieldPathElementRegexp matches the elements of a field path like "imports[0].schema" or "localTypes[my-type]".
+var fieldPathElementRegexp = regexp.MustCompile(`([^.\[\]]+)|\[([^\]]*)\]`)
+
+// yamlFile is a parsed yaml file that is used to find the location of fields.
+type yamlFile struct {
+	root *yamlv3.Node
+}
+
+// parseYAMLFile parses the given data.
+// Lookups in files that cannot be parsed always return an unknown position.
+func parseYAMLFile(data []byte) *yamlFile {
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(data, doc); err != nil || doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return &yamlFile{}
+	}
+	return &yamlFile{root: doc.Content[0]}
+}
+
+// Position returns the line and column of the field with the given path.
+// The position of the deepest existing parent is returned if the field does not exist
+// so that errors about missing fields point to the object that should contain them.
+func (f *yamlFile) Position(fieldPath string) (int, int) {
+	if f.root == nil {
+		return 0, 0
+	}
+	line, column := 0, 0
+	node := f.root
+	for _, m := range fieldPathElementRegexp.FindAllStringSubmatch(fieldPath, -1) {
+		key := m[1]
+		if len(key) == 0 {
+			key = m[2]
+		}
+		var next, pos *yamlv3.Node
+		switch node.Kind {
+		case yamlv3.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(node.Content) {
+				next, pos = node.Content[i], node.Content[i]
+			}
+		case yamlv3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					// point to the key as the value of nested objects starts in the next line
+					next, pos = node.Content[i+1], node.Content[i]
+					break
+				}
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+		line, column = pos.Line, pos.Column
+	}
+	return line, column
+}